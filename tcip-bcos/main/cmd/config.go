@@ -0,0 +1,51 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"github.com/spf13/cobra"
+)
+
+// ConfigCMD 配置文件相关命令
+//
+//	@return *cobra.Command
+func ConfigCMD() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Config file tools",
+		Long:  "Config file tools",
+	}
+	configCmd.AddCommand(configCheckCMD())
+	return configCmd
+}
+
+func configCheckCMD() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the config file",
+		Long:  "Check every section of the config file and print all problems, exit with 1 if any problem is found",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ymlFile := conf.GetAbsPath(conf.ConfigFilePath)
+			config, err := conf.ReadLocalConfig(ymlFile)
+			if err != nil {
+				fmt.Printf("read %s error: %s\n", ymlFile, err.Error())
+				os.Exit(1)
+			}
+			if err = conf.Validate(config); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("%s is OK\n", ymlFile)
+			return nil
+		},
+	}
+	startAttachFlags(checkCmd, []string{flagNameOfConfigFilepath})
+	return checkCmd
+}
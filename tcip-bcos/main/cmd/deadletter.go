@@ -0,0 +1,182 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/deadletter"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"github.com/spf13/cobra"
+)
+
+const flagNameOfAll = "all"
+
+var deadLetterAll bool
+
+// DeadLetterCMD 死信队列管理命令，leveldb不能被多个进程同时打开，需要先停止网关
+//
+//	@return *cobra.Command
+func DeadLetterCMD() *cobra.Command {
+	deadLetterCmd := &cobra.Command{
+		Use:   "deadletter",
+		Short: "Manage relay requests which ran out of retries",
+		Long:  "Manage relay requests which ran out of retries, tcip-bcos must be stopped before running",
+	}
+	deadLetterCmd.AddCommand(deadLetterListCMD())
+	deadLetterCmd.AddCommand(deadLetterShowCMD())
+	deadLetterCmd.AddCommand(deadLetterRedriveCMD())
+	deadLetterCmd.AddCommand(deadLetterDiscardCMD())
+	return deadLetterCmd
+}
+
+func deadLetterListCMD() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List dead letters",
+		Long:  "List dead letters",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			initDeadLetter(cmd)
+			letters, err := deadletter.List()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-32s %-18s %-12s %-10s %-8s %-20s %s\n",
+				"ID", "KIND", "CHAIN", "HEIGHT", "ATTEMPTS", "LAST TIME", "ERROR")
+			for _, letter := range letters {
+				fmt.Printf("%-32s %-18s %-12s %-10d %-8d %-20s %s\n",
+					letter.Id, letter.Kind, letter.ChainRid, letter.BlockHeight, letter.Attempts,
+					time.Unix(letter.LastTime, 0).Format("2006-01-02 15:04:05"), letter.Error)
+			}
+			return nil
+		},
+	}
+	startAttachFlags(listCmd, []string{flagNameOfConfigFilepath})
+	return listCmd
+}
+
+func deadLetterShowCMD() *cobra.Command {
+	showCmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a dead letter",
+		Long:  "Show a dead letter",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initDeadLetter(cmd)
+			letter, err := deadletter.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if letter == nil {
+				return fmt.Errorf("dead letter %s not found", args[0])
+			}
+			letterByte, err := json.MarshalIndent(letter, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(letterByte))
+			return nil
+		},
+	}
+	startAttachFlags(showCmd, []string{flagNameOfConfigFilepath})
+	return showCmd
+}
+
+func deadLetterRedriveCMD() *cobra.Command {
+	redriveCmd := &cobra.Command{
+		Use:   "redrive [id...]",
+		Short: "Send dead letters to the relay gateway again",
+		Long:  "Send dead letters to the relay gateway again, a dead letter is removed once it succeeds",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initDeadLetter(cmd)
+			if err := gateway.Load(); err != nil {
+				return err
+			}
+			if gateway.IsDisabled() {
+				return fmt.Errorf("gateway is disabled: %s", gateway.GetStatus().Reason)
+			}
+			if err := request.InitRequestManager(); err != nil {
+				return err
+			}
+			return forEachDeadLetter(args, func(id string) error {
+				if err := request.RequestV1.Redrive(id); err != nil {
+					return err
+				}
+				fmt.Printf("dead letter %s redriven\n", id)
+				return nil
+			})
+		},
+	}
+	startAttachFlags(redriveCmd, []string{flagNameOfConfigFilepath})
+	redriveCmd.Flags().BoolVar(&deadLetterAll, flagNameOfAll, false, "redrive all dead letters")
+	return redriveCmd
+}
+
+func deadLetterDiscardCMD() *cobra.Command {
+	discardCmd := &cobra.Command{
+		Use:   "discard [id...]",
+		Short: "Discard dead letters",
+		Long:  "Discard dead letters",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			initDeadLetter(cmd)
+			return forEachDeadLetter(args, func(id string) error {
+				if err := deadletter.Delete(id); err != nil {
+					return err
+				}
+				fmt.Printf("dead letter %s discarded\n", id)
+				return nil
+			})
+		},
+	}
+	startAttachFlags(discardCmd, []string{flagNameOfConfigFilepath})
+	discardCmd.Flags().BoolVar(&deadLetterAll, flagNameOfAll, false, "discard all dead letters")
+	return discardCmd
+}
+
+// initDeadLetter 初始化配置和数据库
+//
+//	@param cmd
+func initDeadLetter(cmd *cobra.Command) {
+	initLocalConfig(cmd)
+	db.NewDbHandle()
+}
+
+// forEachDeadLetter 对指定的死信或者--all时的全部死信执行handle，出错时继续处理后面的死信
+//
+//	@param ids
+//	@param handle
+//	@return error
+func forEachDeadLetter(ids []string, handle func(id string) error) error {
+	if deadLetterAll {
+		letters, err := deadletter.List()
+		if err != nil {
+			return err
+		}
+		ids = make([]string, 0, len(letters))
+		for _, letter := range letters {
+			ids = append(ids, letter.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return errors.New("no dead letter id specified, use --all for all dead letters")
+	}
+	failed := 0
+	for _, id := range ids {
+		if err := handle(id); err != nil {
+			fmt.Printf("dead letter %s failed: %s\n", id, err.Error())
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d dead letters failed", failed, len(ids))
+	}
+	return nil
+}
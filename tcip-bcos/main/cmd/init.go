@@ -21,6 +21,6 @@ const (
 func initLocalConfig(cmd *cobra.Command) {
 	if err := conf.InitLocalConfig(cmd); err != nil {
 		fmt.Println(err)
-		os.Exit(0)
+		os.Exit(1)
 	}
 }
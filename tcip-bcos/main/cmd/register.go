@@ -0,0 +1,62 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"github.com/spf13/cobra"
+)
+
+// RegisterCMD 注册网关，注册成功后网关ID写回配置文件
+//
+//	@return *cobra.Command
+func RegisterCMD() *cobra.Command {
+	registerCmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register tcip-bcos to the relay gateway",
+		Long:  "Register tcip-bcos to the relay gateway, the returned gateway id is written back to base.gateway_id",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			initLocalConfig(cmd)
+			if err := request.InitRequestManager(); err != nil {
+				return err
+			}
+			if err := request.RequestV1.GatewayRegister(conf.ConfigFilePath); err != nil {
+				return err
+			}
+			fmt.Printf("register success, gateway id: %s\n", conf.Config.BaseConfig.GatewayID)
+			return nil
+		},
+	}
+	startAttachFlags(registerCmd, []string{flagNameOfConfigFilepath})
+	return registerCmd
+}
+
+// UpdateCMD 把配置文件中的网关信息更新到中继网关
+//
+//	@return *cobra.Command
+func UpdateCMD() *cobra.Command {
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update gateway info on the relay gateway",
+		Long:  "Update gateway info on the relay gateway",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			initLocalConfig(cmd)
+			if err := request.InitRequestManager(); err != nil {
+				return err
+			}
+			if err := request.RequestV1.GatewayUpdate(); err != nil {
+				return err
+			}
+			fmt.Printf("update success, gateway id: %s\n", conf.Config.BaseConfig.GatewayID)
+			return nil
+		},
+	}
+	startAttachFlags(updateCmd, []string{flagNameOfConfigFilepath})
+	return updateCmd
+}
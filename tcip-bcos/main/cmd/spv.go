@@ -0,0 +1,73 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// spvInstall 安装spv合约
+	spvInstall = "install"
+	// spvUpdate 更新spv合约
+	spvUpdate = "update"
+)
+
+var (
+	spvVersion     string
+	spvPath        string
+	spvRuntimeType string
+	spvParams      string
+	spvChainRid    string
+	spvOperation   string
+)
+
+// SpvCMD 在中继链上安装或者更新子链的spv合约
+//
+//	@return *cobra.Command
+func SpvCMD() *cobra.Command {
+	spvCmd := &cobra.Command{
+		Use:   "spv",
+		Short: "Install or update the spv contract of a chain on the relay chain",
+		Long:  "Install or update the spv contract of a chain on the relay chain",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if spvOperation != spvInstall && spvOperation != spvUpdate {
+				return fmt.Errorf("unsupported operation %s, should be %s or %s",
+					spvOperation, spvInstall, spvUpdate)
+			}
+			initLocalConfig(cmd)
+			if err := request.InitRequestManager(); err != nil {
+				return err
+			}
+			var err error
+			if spvOperation == spvInstall {
+				err = request.RequestV1.InitSpvContract(spvVersion, spvPath, spvRuntimeType, spvParams, spvChainRid)
+			} else {
+				err = request.RequestV1.UpdateSpvContract(spvVersion, spvPath, spvRuntimeType, spvParams, spvChainRid)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s spv contract success, chain: %s, version: %s\n", spvOperation, spvChainRid, spvVersion)
+			return nil
+		},
+	}
+	startAttachFlags(spvCmd, []string{flagNameOfConfigFilepath})
+	flags := spvCmd.Flags()
+	flags.StringVarP(&spvVersion, "version", "v", "", "spv contract version")
+	flags.StringVarP(&spvPath, "path", "p", "", "spv contract file path")
+	flags.StringVarP(&spvRuntimeType, "runtime-type", "r", "DOCKER_GO", "spv contract runtime type")
+	flags.StringVarP(&spvParams, "params", "P", "{}", "spv contract params, json object of strings")
+	flags.StringVarP(&spvChainRid, "chain-rid", "C", "", "chain resource id of the spv contract")
+	flags.StringVarP(&spvOperation, "operation", "O", spvInstall, "install or update")
+	for _, name := range []string{"version", "path", "chain-rid"} {
+		_ = spvCmd.MarkFlagRequired(name)
+	}
+	return spvCmd
+}
@@ -6,12 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/rpcserver"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/server"
 
@@ -45,8 +46,7 @@ func StartCMD() *cobra.Command {
 
 func mainStart() {
 	cliLog = logger.GetLogger(logger.ModuleStart)
-	config, _ := json.Marshal(conf.Config)
-	cliLog.Debug(string(config))
+	cliLog.Infof("effective config: %s", conf.Redacted(conf.Config))
 
 	rpcServer, err := rpcserver.NewRpcServer()
 	if err != nil {
@@ -69,6 +69,8 @@ func mainStart() {
 
 	// handle exit signal in separate go routines
 	go handleExitSignal(errorC)
+	go handleResumeSignal()
+	conf.WatchConfig(reloadConfig)
 
 	// listen error signal in main function
 	err = <-errorC
@@ -96,14 +98,57 @@ func startAttachFlags(cmd *cobra.Command, flagNames []string) {
 	}
 }
 
-// handleExitSignal listen exit signal for process stop
+// handleExitSignal listen exit signal for process stop, SIGHUP reloads the config file
 func handleExitSignal(exitC chan<- error) {
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGTERM, os.Interrupt, syscall.SIGINT)
+	signal.Notify(signalChan, syscall.SIGTERM, os.Interrupt, syscall.SIGINT, syscall.SIGHUP)
 	defer signal.Stop(signalChan)
 
 	for sig := range signalChan {
+		if sig == syscall.SIGHUP {
+			cliLog.Infof("received reload signal: %d (%s)", sig, sig)
+			reloadConfig()
+			continue
+		}
 		cliLog.Infof("received exit signal: %d (%s)", sig, sig)
 		exitC <- nil
 	}
 }
+
+// reloadConfig 重新加载配置文件，日志级别、访问名单、区块头同步间隔和批次、默认超时立即生效，
+// 其他配置的变化需要重启，配置有误时继续使用旧配置
+func reloadConfig() {
+	result, err := conf.Reload()
+	if err != nil {
+		cliLog.Errorf("reload config from %s failed, keep the old one: %s", conf.ConfigFilePath, err.Error())
+		return
+	}
+	if len(result.Applied) == 0 && len(result.Restart) == 0 {
+		cliLog.Infof("config %s reloaded, nothing changed", conf.ConfigFilePath)
+		return
+	}
+	if len(result.Applied) != 0 {
+		if err = rpcserver.ReloadAccessPolicy(result.RpcConfig); err != nil {
+			cliLog.Errorf("reload access lists failed: %s", err.Error())
+		}
+		cliLog.Infof("config %s reloaded, applied: %s", conf.ConfigFilePath, strings.Join(result.Applied, ", "))
+	}
+	if len(result.Restart) != 0 {
+		cliLog.Warnf("config %s changed, restart required to apply: %s", conf.ConfigFilePath,
+			strings.Join(result.Restart, ", "))
+	}
+}
+
+// handleResumeSignal 收到SIGUSR1信号时恢复被禁用的网关，中继网关仍然禁用本网关时会再次进入禁用状态
+func handleResumeSignal() {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGUSR1)
+	defer signal.Stop(signalChan)
+
+	for sig := range signalChan {
+		cliLog.Infof("received resume signal: %d (%s)", sig, sig)
+		if gateway.Enable() {
+			cliLog.Info("gateway is enabled by admin, resume forwarding")
+		}
+	}
+}
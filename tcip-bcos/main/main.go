@@ -17,6 +17,11 @@ func main() {
 	mainCmd := &cobra.Command{Use: "tcip-bcos"}
 	mainCmd.AddCommand(cmd.StartCMD())
 	mainCmd.AddCommand(cmd.VersionCMD())
+	mainCmd.AddCommand(cmd.RegisterCMD())
+	mainCmd.AddCommand(cmd.UpdateCMD())
+	mainCmd.AddCommand(cmd.SpvCMD())
+	mainCmd.AddCommand(cmd.DeadLetterCMD())
+	mainCmd.AddCommand(cmd.ConfigCMD())
 
 	err := mainCmd.Execute()
 	if err != nil {
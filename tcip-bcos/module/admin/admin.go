@@ -0,0 +1,295 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/admin/adminpb"
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/deadletter"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/txrecord"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultRecordLimit 默认返回的交易记录条数
+	defaultRecordLimit = 100
+	// maxRecordLimit 最多返回的交易记录条数
+	maxRecordLimit = 1000
+)
+
+// Server 运维管理接口，只允许admin角色调用，由rpc服务的认证拦截器保证
+type Server struct {
+	adminpb.UnimplementedAdminServer
+	log *zap.SugaredLogger
+}
+
+// NewServer 新建运维管理接口
+//
+//	@return *Server
+func NewServer() *Server {
+	return &Server{
+		log: logger.GetLogger(logger.ModuleAdmin),
+	}
+}
+
+// ListChains 列出每条链的高度、区块头同步进度、事件游标和转发队列，会访问链
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.ListChainsResponse
+//	@return error
+func (s *Server) ListChains(ctx context.Context, req *adminpb.ListChainsRequest) (
+	*adminpb.ListChainsResponse, error) {
+	depth := request.RequestV1.QueueDepth()
+	res := &adminpb.ListChainsResponse{}
+	for _, chain := range chain_client.ChainClientV1.ChainStatus() {
+		res.Chains = append(res.Chains, &adminpb.ChainInfo{
+			ChainRid:         chain.ChainRid,
+			Reachable:        chain.Reachable,
+			Height:           chain.Height,
+			HeaderSyncHeight: chain.HeaderSyncHeight,
+			HeaderSyncLag:    chain.HeaderSyncLag,
+			EventCursor:      chain.EventCursor,
+			Subscribed:       chain.Subscribed,
+			LastEventHeight:  chain.LastEventHeight,
+			QueueDepth:       int64(depth[chain.ChainRid]),
+			Error:            chain.Error,
+		})
+	}
+	return res, nil
+}
+
+// ResyncBlockHeader 从指定高度重新同步区块头
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) ResyncBlockHeader(ctx context.Context, req *adminpb.ResyncBlockHeaderRequest) (
+	*adminpb.AdminResponse, error) {
+	if req.ChainRid == "" || req.FromHeight < 0 {
+		return nil, status.Error(codes.InvalidArgument, "chain_rid is required and from_height can not be negative")
+	}
+	if err := chain_client.ChainClientV1.ResyncBlockHeader(req.ChainRid, req.FromHeight); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.log.Infof("[ResyncBlockHeader] chain %s from %d", req.ChainRid, req.FromHeight)
+	return &adminpb.AdminResponse{Message: "block header will be resynced in the next round"}, nil
+}
+
+// SetEventCursor 修改事件游标
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) SetEventCursor(ctx context.Context, req *adminpb.SetEventCursorRequest) (
+	*adminpb.AdminResponse, error) {
+	if req.ChainRid == "" || req.Height < 0 {
+		return nil, status.Error(codes.InvalidArgument, "chain_rid is required and height can not be negative")
+	}
+	if err := chain_client.ChainClientV1.SetEventCursor(req.ChainRid, req.Height); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.log.Infof("[SetEventCursor] chain %s to %d", req.ChainRid, req.Height)
+	return &adminpb.AdminResponse{Message: "event cursor takes effect after restart, use ReplayEvents to replay now"},
+		nil
+}
+
+// ReplayEvents 重放一段高度的跨链事件
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) ReplayEvents(ctx context.Context, req *adminpb.ReplayEventsRequest) (
+	*adminpb.AdminResponse, error) {
+	if req.ChainRid == "" || req.FromHeight < 0 || req.ToHeight < 0 ||
+		req.ToHeight != 0 && req.FromHeight > req.ToHeight {
+		return nil, status.Error(codes.InvalidArgument, "chain_rid is required and height range is invalid")
+	}
+	if err := chain_client.ChainClientV1.ReplayEvent(req.ChainRid, req.FromHeight, req.ToHeight); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.log.Infof("[ReplayEvents] chain %s from %d to %d", req.ChainRid, req.FromHeight, req.ToHeight)
+	return &adminpb.AdminResponse{Message: "events are being replayed"}, nil
+}
+
+// ListDeadLetters 列出死信，不返回请求内容
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.ListDeadLettersResponse
+//	@return error
+func (s *Server) ListDeadLetters(ctx context.Context, req *adminpb.ListDeadLettersRequest) (
+	*adminpb.ListDeadLettersResponse, error) {
+	letters, err := deadletter.List()
+	if err != nil {
+		s.log.Errorf("[ListDeadLetters] %s", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &adminpb.ListDeadLettersResponse{}
+	for _, letter := range letters {
+		res.DeadLetters = append(res.DeadLetters, &adminpb.DeadLetter{
+			Id:          letter.Id,
+			Kind:        letter.Kind,
+			ChainRid:    letter.ChainRid,
+			TxId:        letter.TxId,
+			BlockHeight: letter.BlockHeight,
+			Error:       letter.Error,
+			Attempts:    int64(letter.Attempts),
+			FirstTime:   letter.FirstTime,
+			LastTime:    letter.LastTime,
+		})
+	}
+	return res, nil
+}
+
+// RedriveDeadLetter 重新发送死信，成功后删除，失败时更新死信
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) RedriveDeadLetter(ctx context.Context, req *adminpb.DeadLetterRequest) (
+	*adminpb.AdminResponse, error) {
+	if err := s.checkDeadLetter(req.Id); err != nil {
+		return nil, err
+	}
+	if err := request.RequestV1.Redrive(req.Id); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.log.Infof("[RedriveDeadLetter] %s", req.Id)
+	return &adminpb.AdminResponse{Message: "dead letter redriven"}, nil
+}
+
+// DiscardDeadLetter 丢弃死信
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) DiscardDeadLetter(ctx context.Context, req *adminpb.DeadLetterRequest) (
+	*adminpb.AdminResponse, error) {
+	if err := s.checkDeadLetter(req.Id); err != nil {
+		return nil, err
+	}
+	if err := deadletter.Delete(req.Id); err != nil {
+		s.log.Errorf("[DiscardDeadLetter] %s", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.log.Infof("[DiscardDeadLetter] %s", req.Id)
+	return &adminpb.AdminResponse{Message: "dead letter discarded"}, nil
+}
+
+// checkDeadLetter 检查死信是否存在
+//
+//	@receiver s
+//	@param id
+//	@return error
+func (s *Server) checkDeadLetter(id string) error {
+	if id == "" {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	letter, err := deadletter.Get(id)
+	if err != nil {
+		s.log.Errorf("[checkDeadLetter] %s", err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+	if letter == nil {
+		return status.Errorf(codes.NotFound, "dead letter %s not found", id)
+	}
+	return nil
+}
+
+// ListLogLevels 列出日志模块当前的级别
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.ListLogLevelsResponse
+//	@return error
+func (s *Server) ListLogLevels(ctx context.Context, req *adminpb.ListLogLevelsRequest) (
+	*adminpb.ListLogLevelsResponse, error) {
+	levels := logger.GetLogLevels()
+	modules := make([]string, 0, len(levels))
+	for module := range levels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	res := &adminpb.ListLogLevelsResponse{}
+	for _, module := range modules {
+		res.Levels = append(res.Levels, &adminpb.LogLevel{Module: module, Level: levels[module]})
+	}
+	return res, nil
+}
+
+// SetLogLevel 修改日志模块的级别
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.AdminResponse
+//	@return error
+func (s *Server) SetLogLevel(ctx context.Context, req *adminpb.SetLogLevelRequest) (
+	*adminpb.AdminResponse, error) {
+	if err := logger.SetLogLevel(req.Module, req.Level); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.log.Infof("[SetLogLevel] log level of %s is set to %s", req.Module, req.Level)
+	return &adminpb.AdminResponse{Message: "log level changed until restart"}, nil
+}
+
+// ListTxRecords 按时间倒序查询跨链交易记录
+//
+//	@receiver s
+//	@param ctx
+//	@param req
+//	@return *adminpb.ListTxRecordsResponse
+//	@return error
+func (s *Server) ListTxRecords(ctx context.Context, req *adminpb.ListTxRecordsRequest) (
+	*adminpb.ListTxRecordsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRecordLimit
+	}
+	if limit > maxRecordLimit {
+		limit = maxRecordLimit
+	}
+	records, err := txrecord.List(req.CrossChainId, req.ChainRid, limit)
+	if err != nil {
+		s.log.Errorf("[ListTxRecords] %s", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &adminpb.ListTxRecordsResponse{}
+	for _, record := range records {
+		res.Records = append(res.Records, &adminpb.TxRecord{
+			CrossChainId: record.CrossChainId,
+			Phase:        record.Phase,
+			ChainRid:     record.ChainRid,
+			TxId:         record.TxId,
+			BlockHeight:  record.BlockHeight,
+			Success:      record.Success,
+			Message:      record.Message,
+			Time:         record.Time,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"context"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/admin/adminpb"
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/deadletter"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/txrecord"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient 在内存连接上启动运维管理接口
+func newTestClient(t *testing.T) adminpb.AdminClient {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+			LogLevel:     logger.INFO,
+		},
+	}
+	logger.InitLogConfig(log)
+	conf.Config.BaseConfig = &conf.BaseConfig{TxVerifyType: "notneed"}
+	conf.Config.ChainConfig = []*conf.ChainConfig{{ChainRid: "chain1"}}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	db.NewDbHandle()
+	_ = request.InitRequestManagerMock()
+	_ = chain_client.InitChainClientMock()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	adminpb.RegisterAdminServer(server, NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}), grpc.WithInsecure())
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return adminpb.NewAdminClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	chains, err := client.ListChains(ctx, &adminpb.ListChainsRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(chains.Chains))
+	assert.Equal(t, "chain1", chains.Chains[0].ChainRid)
+	assert.True(t, chains.Chains[0].Subscribed)
+
+	assert.Nil(t, txrecord.Put(&txrecord.Record{CrossChainId: "1", Phase: txrecord.PhaseTry, ChainRid: "chain1",
+		Success: true}))
+	records, err := client.ListTxRecords(ctx, &adminpb.ListTxRecordsRequest{CrossChainId: "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records.Records))
+	assert.Equal(t, txrecord.PhaseTry, records.Records[0].Phase)
+
+	assert.Nil(t, deadletter.Put(&deadletter.Letter{Id: "letter1", Kind: deadletter.KindBeginCrossChain}))
+	letters, err := client.ListDeadLetters(ctx, &adminpb.ListDeadLettersRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(letters.DeadLetters))
+	_, err = client.DiscardDeadLetter(ctx, &adminpb.DeadLetterRequest{Id: "letter1"})
+	assert.Nil(t, err)
+
+	_, err = client.SetLogLevel(ctx, &adminpb.SetLogLevelRequest{Module: "default", Level: "debug"})
+	assert.Nil(t, err)
+	levels, err := client.ListLogLevels(ctx, &adminpb.ListLogLevelsRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(levels.Levels))
+	assert.Equal(t, logger.ModuleDefault, levels.Levels[0].Module)
+	assert.Equal(t, logger.DEBUG, levels.Levels[0].Level)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "discard missing dead letter",
+			call: func() error {
+				_, err := client.DiscardDeadLetter(ctx, &adminpb.DeadLetterRequest{Id: "letter1"})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "unknown log module",
+			call: func() error {
+				_, err := client.SetLogLevel(ctx, &adminpb.SetLogLevelRequest{Module: "unknown", Level: "INFO"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid replay range",
+			call: func() error {
+				_, err := client.ReplayEvents(ctx, &adminpb.ReplayEventsRequest{ChainRid: "chain1",
+					FromHeight: 10, ToHeight: 5})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "replay",
+			call: func() error {
+				_, err := client.ReplayEvents(ctx, &adminpb.ReplayEventsRequest{ChainRid: "chain1", FromHeight: 5})
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "resync without chain",
+			call: func() error {
+				_, err := client.ResyncBlockHeader(ctx, &adminpb.ResyncBlockHeaderRequest{FromHeight: 1})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, status.Code(tt.call()))
+		})
+	}
+}
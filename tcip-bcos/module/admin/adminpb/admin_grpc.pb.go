@@ -0,0 +1,445 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// ListChains 列出每条链的高度、区块头同步进度、事件游标和转发队列
+	ListChains(ctx context.Context, in *ListChainsRequest, opts ...grpc.CallOption) (*ListChainsResponse, error)
+	// ResyncBlockHeader 从指定高度重新同步区块头，下一轮同步生效，只在spv验证时可用
+	ResyncBlockHeader(ctx context.Context, in *ResyncBlockHeaderRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// SetEventCursor 修改事件游标，下次订阅事件（重启）时从这个高度开始
+	SetEventCursor(ctx context.Context, in *SetEventCursorRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// ReplayEvents 重新订阅一段高度的跨链事件并转发，不影响事件游标
+	ReplayEvents(ctx context.Context, in *ReplayEventsRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// ListDeadLetters 列出重试耗尽的中继网关请求
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	// RedriveDeadLetter 重新发送死信，成功后删除
+	RedriveDeadLetter(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// DiscardDeadLetter 丢弃死信
+	DiscardDeadLetter(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// ListLogLevels 列出每个日志模块当前的级别
+	ListLogLevels(ctx context.Context, in *ListLogLevelsRequest, opts ...grpc.CallOption) (*ListLogLevelsResponse, error)
+	// SetLogLevel 修改日志模块的级别，重启后恢复配置文件中的级别
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// ListTxRecords 查询跨链交易记录，按时间倒序
+	ListTxRecords(ctx context.Context, in *ListTxRecordsRequest, opts ...grpc.CallOption) (*ListTxRecordsResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListChains(ctx context.Context, in *ListChainsRequest, opts ...grpc.CallOption) (*ListChainsResponse, error) {
+	out := new(ListChainsResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ListChains", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResyncBlockHeader(ctx context.Context, in *ResyncBlockHeaderRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ResyncBlockHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetEventCursor(ctx context.Context, in *SetEventCursorRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/SetEventCursor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReplayEvents(ctx context.Context, in *ReplayEventsRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ReplayEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ListDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RedriveDeadLetter(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/RedriveDeadLetter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DiscardDeadLetter(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/DiscardDeadLetter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListLogLevels(ctx context.Context, in *ListLogLevelsRequest, opts ...grpc.CallOption) (*ListLogLevelsResponse, error) {
+	out := new(ListLogLevelsResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ListLogLevels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListTxRecords(ctx context.Context, in *ListTxRecordsRequest, opts ...grpc.CallOption) (*ListTxRecordsResponse, error) {
+	out := new(ListTxRecordsResponse)
+	err := c.cc.Invoke(ctx, "/tcip_bcos.admin.Admin/ListTxRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// ListChains 列出每条链的高度、区块头同步进度、事件游标和转发队列
+	ListChains(context.Context, *ListChainsRequest) (*ListChainsResponse, error)
+	// ResyncBlockHeader 从指定高度重新同步区块头，下一轮同步生效，只在spv验证时可用
+	ResyncBlockHeader(context.Context, *ResyncBlockHeaderRequest) (*AdminResponse, error)
+	// SetEventCursor 修改事件游标，下次订阅事件（重启）时从这个高度开始
+	SetEventCursor(context.Context, *SetEventCursorRequest) (*AdminResponse, error)
+	// ReplayEvents 重新订阅一段高度的跨链事件并转发，不影响事件游标
+	ReplayEvents(context.Context, *ReplayEventsRequest) (*AdminResponse, error)
+	// ListDeadLetters 列出重试耗尽的中继网关请求
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	// RedriveDeadLetter 重新发送死信，成功后删除
+	RedriveDeadLetter(context.Context, *DeadLetterRequest) (*AdminResponse, error)
+	// DiscardDeadLetter 丢弃死信
+	DiscardDeadLetter(context.Context, *DeadLetterRequest) (*AdminResponse, error)
+	// ListLogLevels 列出每个日志模块当前的级别
+	ListLogLevels(context.Context, *ListLogLevelsRequest) (*ListLogLevelsResponse, error)
+	// SetLogLevel 修改日志模块的级别，重启后恢复配置文件中的级别
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*AdminResponse, error)
+	// ListTxRecords 查询跨链交易记录，按时间倒序
+	ListTxRecords(context.Context, *ListTxRecordsRequest) (*ListTxRecordsResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) ListChains(context.Context, *ListChainsRequest) (*ListChainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChains not implemented")
+}
+func (UnimplementedAdminServer) ResyncBlockHeader(context.Context, *ResyncBlockHeaderRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResyncBlockHeader not implemented")
+}
+func (UnimplementedAdminServer) SetEventCursor(context.Context, *SetEventCursorRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEventCursor not implemented")
+}
+func (UnimplementedAdminServer) ReplayEvents(context.Context, *ReplayEventsRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayEvents not implemented")
+}
+func (UnimplementedAdminServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedAdminServer) RedriveDeadLetter(context.Context, *DeadLetterRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedriveDeadLetter not implemented")
+}
+func (UnimplementedAdminServer) DiscardDeadLetter(context.Context, *DeadLetterRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardDeadLetter not implemented")
+}
+func (UnimplementedAdminServer) ListLogLevels(context.Context, *ListLogLevelsRequest) (*ListLogLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLogLevels not implemented")
+}
+func (UnimplementedAdminServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedAdminServer) ListTxRecords(context.Context, *ListTxRecordsRequest) (*ListTxRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTxRecords not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_ListChains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListChains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ListChains",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListChains(ctx, req.(*ListChainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResyncBlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResyncBlockHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResyncBlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ResyncBlockHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResyncBlockHeader(ctx, req.(*ResyncBlockHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetEventCursor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEventCursorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetEventCursor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/SetEventCursor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetEventCursor(ctx, req.(*SetEventCursorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReplayEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReplayEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ReplayEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReplayEvents(ctx, req.(*ReplayEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ListDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RedriveDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RedriveDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/RedriveDeadLetter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RedriveDeadLetter(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DiscardDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DiscardDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/DiscardDeadLetter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DiscardDeadLetter(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListLogLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLogLevelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListLogLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ListLogLevels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListLogLevels(ctx, req.(*ListLogLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListTxRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTxRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListTxRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tcip_bcos.admin.Admin/ListTxRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListTxRecords(ctx, req.(*ListTxRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tcip_bcos.admin.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListChains",
+			Handler:    _Admin_ListChains_Handler,
+		},
+		{
+			MethodName: "ResyncBlockHeader",
+			Handler:    _Admin_ResyncBlockHeader_Handler,
+		},
+		{
+			MethodName: "SetEventCursor",
+			Handler:    _Admin_SetEventCursor_Handler,
+		},
+		{
+			MethodName: "ReplayEvents",
+			Handler:    _Admin_ReplayEvents_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _Admin_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "RedriveDeadLetter",
+			Handler:    _Admin_RedriveDeadLetter_Handler,
+		},
+		{
+			MethodName: "DiscardDeadLetter",
+			Handler:    _Admin_DiscardDeadLetter_Handler,
+		},
+		{
+			MethodName: "ListLogLevels",
+			Handler:    _Admin_ListLogLevels_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _Admin_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "ListTxRecords",
+			Handler:    _Admin_ListTxRecords_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,42 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+)
+
+// ReadKey 读取pem私钥，配置了密码时解密openssl传统格式加密的私钥，返回未加密的pem
+//
+//	@param file
+//	@param password
+//	@return []byte
+//	@return error
+func ReadKey(file, password string) ([]byte, error) {
+	keyPem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return keyPem, nil
+	}
+	block, _ := pem.Decode(keyPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid pem key: %s", file)
+	}
+	// 只支持openssl传统格式（带DEK-Info）的加密私钥
+	if !x509.IsEncryptedPEMBlock(block) {
+		return nil, fmt.Errorf("key %s is not encrypted but a password is configured", file)
+	}
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key %s failed, %s", file, err.Error())
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKey(t *testing.T) {
+	dir := initTest(t)
+	defer os.RemoveAll(dir)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	plainPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	plainFile := path.Join(dir, "plain.key")
+	assert.Nil(t, ioutil.WriteFile(plainFile, plainPem, 0600))
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("password"), x509.PEMCipherAES256)
+	assert.Nil(t, err)
+	encryptedFile := path.Join(dir, "encrypted.key")
+	assert.Nil(t, ioutil.WriteFile(encryptedFile, pem.EncodeToMemory(block), 0600))
+
+	tests := []struct {
+		name     string
+		file     string
+		password string
+		wantErr  bool
+	}{
+		{name: "plain", file: plainFile},
+		{name: "encrypted", file: encryptedFile, password: "password"},
+		{name: "password for plain key", file: plainFile, password: "password", wantErr: true},
+		{name: "missing", file: path.Join(dir, "missing.key"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadKey(tt.file, tt.password)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, plainPem, got)
+		})
+	}
+
+	// 密码错误时大概率填充校验失败，极少数情况下解出错误的内容
+	got, err := ReadKey(encryptedFile, "wrong")
+	assert.True(t, err != nil || !bytes.Equal(plainPem, got))
+}
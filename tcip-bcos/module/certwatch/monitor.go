@@ -0,0 +1,247 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	cmx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMonitorInterval = time.Hour
+	day                    = 24 * time.Hour
+)
+
+// defaultWarnDays 默认在距离过期30、7、1天时告警
+var defaultWarnDays = []int{30, 7, 1}
+
+// CertStatus 证书的有效期，文件中有多个证书时使用最早过期的一个
+type CertStatus struct {
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Subject string `json:"subject,omitempty"`
+	// NotAfter 过期时间的unix时间戳
+	NotAfter int64  `json:"not_after,omitempty"`
+	DaysLeft int64  `json:"days_left"`
+	Expired  bool   `json:"expired"`
+	Error    string `json:"error,omitempty"`
+}
+
+// watched 监控有效期的证书文件
+type watched struct {
+	name string
+	file string
+	// notAfter 上次检查到的过期时间，证书更换后重新告警
+	notAfter time.Time
+	// warned 已经告警过的最小天数阈值，-1表示还没有告警
+	warned int
+}
+
+var (
+	lock      sync.Mutex
+	certs     = make([]*watched, 0)
+	statuses  = make([]*CertStatus, 0)
+	startOnce sync.Once
+	log       *zap.SugaredLogger
+)
+
+// Register 登记需要监控有效期的证书文件，name相同时替换文件，空路径会被忽略
+//
+//	@param name 用于日志、指标和状态
+//	@param file
+func Register(name, file string) {
+	if file == "" {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for _, cert := range certs {
+		if cert.name == name {
+			cert.file = file
+			cert.notAfter = time.Time{}
+			cert.warned = -1
+			return
+		}
+	}
+	certs = append(certs, &watched{name: name, file: file, warned: -1})
+}
+
+// Start 启动后台检查，需要在Register之后调用
+//
+//	@param config
+func Start(config *conf.CertMonitorConfig) {
+	startOnce.Do(func() {
+		go run(getInterval(config), getWarnDays(config))
+	})
+}
+
+// run 定时检查证书有效期
+//
+//	@param interval
+//	@param warnDays
+func run(interval time.Duration, warnDays []int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		Check(warnDays)
+		<-ticker.C
+	}
+}
+
+// Check 检查全部证书的有效期，更新指标，临近过期时按阈值告警，每个阈值只告警一次，过期后每次检查都报错
+//
+//	@param warnDays 距离过期多少天时告警，从大到小排列
+//	@return []*CertStatus
+func Check(warnDays []int) []*CertStatus {
+	lock.Lock()
+	defer lock.Unlock()
+	now := time.Now()
+	result := make([]*CertStatus, 0, len(certs))
+	for _, cert := range certs {
+		status := readStatus(cert.name, cert.file, now)
+		result = append(result, status)
+		if status.Error != "" {
+			getLog().Errorf("[Check] read certificate %s %s failed: %s", cert.name, cert.file, status.Error)
+			continue
+		}
+		metrics.CertExpiry.WithLabelValues(cert.name).Set(float64(status.NotAfter))
+		notAfter := time.Unix(status.NotAfter, 0)
+		if !notAfter.Equal(cert.notAfter) {
+			cert.notAfter = notAfter
+			cert.warned = -1
+		}
+		if status.Expired {
+			getLog().Errorf("[Check] certificate %s %s (%s) expired at %s", cert.name, cert.file,
+				status.Subject, notAfter.Format(time.RFC3339))
+			continue
+		}
+		threshold := crossedThreshold(warnDays, notAfter.Sub(now))
+		if threshold >= 0 && (cert.warned < 0 || threshold < cert.warned) {
+			cert.warned = threshold
+			getLog().Warnf("[Check] certificate %s %s (%s) expires in %d days at %s", cert.name, cert.file,
+				status.Subject, status.DaysLeft, notAfter.Format(time.RFC3339))
+		}
+	}
+	statuses = result
+	return result
+}
+
+// Status 最近一次检查的结果，剩余天数和是否过期按当前时间重新计算
+//
+//	@return []*CertStatus
+func Status() []*CertStatus {
+	lock.Lock()
+	defer lock.Unlock()
+	now := time.Now()
+	result := make([]*CertStatus, 0, len(statuses))
+	for _, status := range statuses {
+		current := *status
+		if current.Error == "" {
+			notAfter := time.Unix(current.NotAfter, 0)
+			current.DaysLeft = int64(notAfter.Sub(now) / day)
+			current.Expired = !now.Before(notAfter)
+		}
+		result = append(result, &current)
+	}
+	return result
+}
+
+// readStatus 读取证书文件中最早过期的证书
+//
+//	@param name
+//	@param file
+//	@param now
+//	@return *CertStatus
+func readStatus(name, file string, now time.Time) *CertStatus {
+	status := &CertStatus{Name: name, File: file}
+	certPem, err := ioutil.ReadFile(file)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	var earliest *cmx509.Certificate
+	for block, rest := pem.Decode(certPem); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := cmx509.ParseCertificate(block.Bytes)
+		if err != nil {
+			status.Error = err.Error()
+			return status
+		}
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	if earliest == nil {
+		status.Error = fmt.Sprintf("no certificate found in %s", file)
+		return status
+	}
+	status.Subject = earliest.Subject.String()
+	status.NotAfter = earliest.NotAfter.Unix()
+	status.DaysLeft = int64(earliest.NotAfter.Sub(now) / day)
+	status.Expired = !now.Before(earliest.NotAfter)
+	return status
+}
+
+// crossedThreshold 剩余时间已经进入的最小天数阈值，没有进入任何阈值时返回-1
+//
+//	@param warnDays
+//	@param left
+//	@return int
+func crossedThreshold(warnDays []int, left time.Duration) int {
+	threshold := -1
+	for _, days := range warnDays {
+		if left <= time.Duration(days)*day && (threshold < 0 || days < threshold) {
+			threshold = days
+		}
+	}
+	return threshold
+}
+
+// getInterval 检查间隔
+//
+//	@param config
+//	@return time.Duration
+func getInterval(config *conf.CertMonitorConfig) time.Duration {
+	if config == nil || config.Interval == 0 {
+		return defaultMonitorInterval
+	}
+	return time.Duration(config.Interval) * time.Second
+}
+
+// getWarnDays 告警阈值，从大到小排列
+//
+//	@param config
+//	@return []int
+func getWarnDays(config *conf.CertMonitorConfig) []int {
+	if config == nil || len(config.WarnDays) == 0 {
+		return defaultWarnDays
+	}
+	warnDays := append([]int{}, config.WarnDays...)
+	sort.Sort(sort.Reverse(sort.IntSlice(warnDays)))
+	return warnDays
+}
+
+// getLog 第一次使用时获取日志，日志配置在启动时才初始化
+//
+//	@return *zap.SugaredLogger
+func getLog() *zap.SugaredLogger {
+	if log == nil {
+		log = logger.GetLogger(logger.ModuleCert)
+	}
+	return log
+}
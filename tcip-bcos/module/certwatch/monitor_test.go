@@ -0,0 +1,117 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest(t *testing.T) string {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	logger.InitLogConfig(log)
+	lock.Lock()
+	certs = make([]*watched, 0)
+	statuses = make([]*CertStatus, 0)
+	lock.Unlock()
+	dir, err := ioutil.TempDir("", "certwatch")
+	assert.Nil(t, err)
+	return dir
+}
+
+// newCertPem 生成指定过期时间的自签名证书
+func newCertPem(t *testing.T, commonName string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * day),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCheck(t *testing.T) {
+	dir := initTest(t)
+	defer os.RemoveAll(dir)
+	now := time.Now()
+
+	serverFile := path.Join(dir, "server.crt")
+	assert.Nil(t, ioutil.WriteFile(serverFile, newCertPem(t, "server", now.Add(5*day+time.Hour)), 0600))
+	caFile := path.Join(dir, "ca.crt")
+	caPem := append(newCertPem(t, "ca1", now.Add(100*day)), newCertPem(t, "ca2", now.Add(-time.Hour))...)
+	assert.Nil(t, ioutil.WriteFile(caFile, caPem, 0600))
+	Register("server", serverFile)
+	Register("ca", caFile)
+	Register("missing", path.Join(dir, "missing.crt"))
+	Register("empty", "")
+
+	result := Check(defaultWarnDays)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, int64(5), result[0].DaysLeft)
+	assert.False(t, result[0].Expired)
+	assert.Equal(t, "CN=server", result[0].Subject)
+	// 多个证书时使用最早过期的
+	assert.Equal(t, "CN=ca2", result[1].Subject)
+	assert.True(t, result[1].Expired)
+	assert.NotEmpty(t, result[2].Error)
+	assert.Equal(t, 7, certs[0].warned)
+	assert.Equal(t, result, Status())
+
+	// 更换证书后重新告警
+	assert.Nil(t, ioutil.WriteFile(serverFile, newCertPem(t, "server", now.Add(20*day)), 0600))
+	Check(defaultWarnDays)
+	assert.Equal(t, 30, certs[0].warned)
+}
+
+func TestCrossedThreshold(t *testing.T) {
+	tests := []struct {
+		name string
+		left time.Duration
+		want int
+	}{
+		{name: "far", left: 60 * day, want: -1},
+		{name: "30 days", left: 30 * day, want: 30},
+		{name: "10 days", left: 10 * day, want: 30},
+		{name: "7 days", left: 6 * day, want: 7},
+		{name: "hours", left: time.Hour, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, crossedThreshold(defaultWarnDays, tt.left))
+		})
+	}
+}
+
+func TestGetWarnDays(t *testing.T) {
+	assert.Equal(t, defaultWarnDays, getWarnDays(nil))
+	assert.Equal(t, []int{14, 3, 1}, getWarnDays(&conf.CertMonitorConfig{WarnDays: []int{1, 14, 3}}))
+	assert.Equal(t, defaultMonitorInterval, getInterval(&conf.CertMonitorConfig{}))
+	assert.Equal(t, time.Minute, getInterval(&conf.CertMonitorConfig{Interval: 60}))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CheckInterval 检查证书文件是否变化的间隔
+const CheckInterval = 10 * time.Second
+
+// Fingerprint 文件的修改时间和大小，任何一个文件变化时结果都会变化，空路径会被跳过
+//
+//	@param files
+//	@return string
+//	@return error
+func Fingerprint(files ...string) (string, error) {
+	fingerprint := ""
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fingerprint += fmt.Sprintf("|%s:%d:%d", file, info.ModTime().UnixNano(), info.Size())
+	}
+	return fingerprint, nil
+}
+
+// Reloader 证书文件变化时重新加载的凭证，加载失败时继续使用旧的凭证
+type Reloader struct {
+	name        string
+	files       []string
+	load        func() (interface{}, error)
+	log         *zap.SugaredLogger
+	lock        sync.Mutex
+	current     interface{}
+	fingerprint string
+	lastCheck   time.Time
+}
+
+// NewReloader 新建并立即加载一次凭证
+//
+//	@param name 用于日志
+//	@param files 需要监视的证书、私钥和ca文件
+//	@param load 根据文件生成凭证
+//	@param log
+//	@return *Reloader
+//	@return error
+func NewReloader(name string, files []string, load func() (interface{}, error),
+	log *zap.SugaredLogger) (*Reloader, error) {
+	fingerprint, err := Fingerprint(files...)
+	if err != nil {
+		return nil, err
+	}
+	current, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return &Reloader{
+		name:        name,
+		files:       files,
+		load:        load,
+		log:         log,
+		current:     current,
+		fingerprint: fingerprint,
+		lastCheck:   time.Now(),
+	}, nil
+}
+
+// Get 获取当前的凭证，距离上次检查超过CheckInterval时检查文件是否变化
+//
+//	@receiver r
+//	@return interface{}
+func (r *Reloader) Get() interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if time.Since(r.lastCheck) < CheckInterval {
+		return r.current
+	}
+	r.lastCheck = time.Now()
+	fingerprint, err := Fingerprint(r.files...)
+	if err != nil {
+		r.log.Errorf("[Get] check %s certificates failed, keep the old ones: %s", r.name, err.Error())
+		return r.current
+	}
+	if fingerprint == r.fingerprint {
+		return r.current
+	}
+	current, err := r.load()
+	if err != nil {
+		// 不更新指纹，下次检查时重试，避免证书和私钥只替换了一个时永远使用旧凭证
+		r.log.Errorf("[Get] reload %s certificates failed, keep the old ones: %s", r.name, err.Error())
+		return r.current
+	}
+	r.log.Infof("[Get] %s certificates reloaded", r.name)
+	r.current = current
+	r.fingerprint = fingerprint
+	return r.current
+}
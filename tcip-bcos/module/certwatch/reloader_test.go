@@ -0,0 +1,62 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certwatch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader(t *testing.T) {
+	dir := initTest(t)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "server.crt")
+	assert.Nil(t, ioutil.WriteFile(file, []byte("v1"), 0600))
+
+	load := func() (interface{}, error) {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if string(content) == "broken" {
+			return nil, fmt.Errorf("broken certificate")
+		}
+		return string(content), nil
+	}
+	reloader, err := NewReloader("test", []string{file, ""}, load, logger.GetLogger(logger.ModuleCert))
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", reloader.Get())
+
+	// 检查间隔内不重新加载
+	assert.Nil(t, ioutil.WriteFile(file, []byte("v2"), 0600))
+	assert.Equal(t, "v1", reloader.Get())
+
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "v2", reloader.Get())
+
+	// 加载失败时继续使用旧的凭证，修复后重新加载
+	assert.Nil(t, ioutil.WriteFile(file, []byte("broken"), 0600))
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "v2", reloader.Get())
+	assert.Nil(t, ioutil.WriteFile(file, []byte("v3"), 0600))
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "v3", reloader.Get())
+
+	// 文件不存在时继续使用旧的凭证
+	assert.Nil(t, os.Remove(file))
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "v3", reloader.Get())
+
+	_, err = NewReloader("test", []string{file}, load, logger.GetLogger(logger.ModuleCert))
+	assert.NotNil(t, err)
+}
@@ -8,6 +8,7 @@ package chain_client
 import (
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
 	"context"
 	"encoding/base64"
@@ -17,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -36,6 +38,10 @@ import (
 
 const (
 	toBlock = "latest"
+	// pendingCrossHeightKeyFormat 运维修改的事件游标，下次订阅事件时生效
+	pendingCrossHeightKeyFormat = "%s_pending_cross_height"
+	// defaultStatusTimeout 检查链状态时访问链的默认超时时间
+	defaultStatusTimeout = 3 * time.Second
 )
 
 // ChainClientItfc 链客户端接口
@@ -49,6 +55,44 @@ type ChainClientItfc interface {
 	TxProve(txProve string) bool
 	// CheckChain 验证了链的连通性
 	CheckChain() bool
+	// CheckContract 验证合约地址合法并且已经部署
+	CheckContract(chainRid, contractName string) error
+	// ChainStatus 每条链的连通性、高度和事件订阅状态
+	ChainStatus() []*ChainStatus
+	// ResyncBlockHeader 从指定高度重新同步区块头
+	ResyncBlockHeader(chainRid string, fromHeight int64) error
+	// SetEventCursor 修改事件游标，下次订阅事件时生效
+	SetEventCursor(chainRid string, height int64) error
+	// ReplayEvent 重放一段高度的跨链事件
+	ReplayEvent(chainRid string, fromHeight, toHeight int64) error
+	// ScanContractTx 扫描一段高度内调用合约的交易
+	ScanContractTx(chainRid, contractName string, fromHeight, toHeight int64) (
+		[]*bcostypes.TransactionDetail, int64, error)
+}
+
+// ChainStatus 链的健康状态
+type ChainStatus struct {
+	ChainRid  string `json:"chain_rid"`
+	Reachable bool   `json:"reachable"`
+	Height    int64  `json:"height"`
+	// HeaderSyncHeight 已经同步到中继网关的区块头高度，只有spv验证时同步区块头
+	HeaderSyncHeight int64 `json:"header_sync_height,omitempty"`
+	HeaderSyncLag    int64 `json:"header_sync_lag,omitempty"`
+	// EventCursor 下次订阅事件的起始高度
+	EventCursor int64 `json:"event_cursor"`
+	// EventCursorPending 事件游标是运维修改的，还没有生效
+	EventCursorPending bool `json:"event_cursor_pending,omitempty"`
+	Subscribed         bool `json:"subscribed"`
+	// LastEventHeight 最近一次收到跨链事件的区块高度，还没有收到事件时是订阅的起始高度
+	LastEventHeight int64  `json:"last_event_height"`
+	Error           string `json:"error,omitempty"`
+}
+
+// eventState 事件订阅状态
+type eventState struct {
+	subscribed      bool
+	lastEventHeight int64
+	err             string
 }
 
 // ChainClient 链客户端结构体
@@ -57,6 +101,12 @@ type ChainClient struct {
 	client map[string]*sdk.Client
 	// 日志对象
 	log *zap.SugaredLogger
+	// eventLock 保护events
+	eventLock sync.Mutex
+	// events 每条链的事件订阅状态
+	events map[string]*eventState
+	// headerLock 同步区块头和修改区块头同步高度互斥
+	headerLock sync.Mutex
 }
 
 // ChainClientV1 连交互模块对象
@@ -75,6 +125,7 @@ func InitChainClient() error {
 	bcosClient := &ChainClient{
 		client: make(map[string]*sdk.Client),
 		log:    logger.GetLogger(logger.ModuleChainClient),
+		events: make(map[string]*eventState),
 	}
 	for _, chainConfig := range conf.Config.ChainConfig {
 		cc, err := createSDK(chainConfig.SdkConfigPath)
@@ -107,7 +158,7 @@ func InitChainClient() error {
 //	@param chainRid
 //	@param startBlock
 func (c *ChainClient) listenBlockHeader(chainRid string) {
-	interval := time.Duration(conf.Config.BlockHeaderSync.Interval) * time.Second
+	interval := time.Duration(conf.HeaderSyncInterval()) * time.Second
 
 	timer := time.NewTimer(interval)
 
@@ -118,12 +169,15 @@ func (c *ChainClient) listenBlockHeader(chainRid string) {
 			if err != nil {
 				c.log.Errorf("[listenBlockHeader] %s", err.Error())
 			}
-			timer.Reset(interval)
+			// 同步间隔可以在运行时重新加载，每一轮重新读取
+			timer.Reset(time.Duration(conf.HeaderSyncInterval()) * time.Second)
 		}
 	}
 }
 
 func (c *ChainClient) syncBlockHeaderBath(chainRid string) error {
+	c.headerLock.Lock()
+	defer c.headerLock.Unlock()
 	client, err := c.getChainClient(chainRid)
 	if err != nil {
 		c.log.Errorf("[syncBlockHeaderBath] %s", err.Error())
@@ -135,14 +189,17 @@ func (c *ChainClient) syncBlockHeaderBath(chainRid string) error {
 		c.log.Errorf("[syncBlockHeaderBath] %s, GetCurrentBlockHeight error", err.Error())
 		return err
 	}
+	metrics.ChainHeight.WithLabelValues(chainRid).Set(float64(lastBlockHeight))
 	c.log.Infof("[syncBlockHeaderBath] startBlock %d, lastBlockHeight %d", startBlock, lastBlockHeight)
 	const errorFormat = "[syncBlockHeaderBath] %s, startBlock %d, lastBlockHeight %d"
 	if startBlock != 0 {
 		startBlock += 1
 	}
 	needSyncCount := lastBlockHeight - startBlock + 1
+	// 批次大小可以在运行时重新加载，一轮同步中使用同一个值
+	batchCount := conf.HeaderSyncBatchCount()
 	blockHeaderBatch := make([]string, 0)
-	if needSyncCount < conf.Config.BlockHeaderSync.BatchCount {
+	if needSyncCount < batchCount {
 		for i := startBlock; i <= lastBlockHeight; i++ {
 			block, err := client.GetBlockByNumber(context.Background(), i, false)
 			if err != nil {
@@ -154,18 +211,17 @@ func (c *ChainClient) syncBlockHeaderBath(chainRid string) error {
 		if len(blockHeaderBatch) == 0 {
 			return nil
 		}
-		request.RequestV1.SyncBlockHeader(nil, blockHeaderBatch, chainRid, uint64(lastBlockHeight))
-		return nil
+		return request.RequestV1.SyncBlockHeader(nil, blockHeaderBatch, chainRid, uint64(lastBlockHeight))
 	} else {
-		reqCount := needSyncCount / conf.Config.BlockHeaderSync.BatchCount
-		if needSyncCount%conf.Config.BlockHeaderSync.BatchCount != 0 {
+		reqCount := needSyncCount / batchCount
+		if needSyncCount%batchCount != 0 {
 			reqCount += 1
 		}
 		for i := int64(0); i < reqCount; i++ {
 			successBlockHeight := uint64(0)
 			blockHeaderBatch = make([]string, 0)
-			for j := int64(0); j < conf.Config.BlockHeaderSync.BatchCount; j++ {
-				blockHeight := startBlock + i*conf.Config.BlockHeaderSync.BatchCount + j
+			for j := int64(0); j < batchCount; j++ {
+				blockHeight := startBlock + i*batchCount + j
 				if blockHeight > lastBlockHeight {
 					break
 				}
@@ -180,7 +236,12 @@ func (c *ChainClient) syncBlockHeaderBath(chainRid string) error {
 			if len(blockHeaderBatch) == 0 {
 				continue
 			}
-			request.RequestV1.SyncBlockHeader(nil, blockHeaderBatch, chainRid, successBlockHeight)
+			// 区块头必须连续同步，前面的批次失败后后面的批次不再同步
+			err = request.RequestV1.SyncBlockHeader(nil, blockHeaderBatch, chainRid, successBlockHeight)
+			if err != nil {
+				c.log.Errorf(errorFormat, err.Error(), startBlock, lastBlockHeight)
+				return err
+			}
 		}
 	}
 	return nil
@@ -197,7 +258,27 @@ func (c *ChainClient) getLaseBlockHeaderByteBase64(blockHeader *bcostypes.Block)
 //	@param dbEvent
 //	@return error
 func (c *ChainClient) listenEvent(chainRid, contractName string) error {
-	startBlcok := c.getLaseCrossHeight(chainRid)
+	startBlcok := c.takeEventCursor(chainRid)
+	c.setEventState(chainRid, false, startBlcok, "")
+	err := c.subscribeEvent(chainRid, contractName, startBlcok, toBlock, false)
+	if err != nil {
+		c.setEventState(chainRid, false, startBlcok, err.Error())
+		return err
+	}
+	c.setEventState(chainRid, true, startBlcok, "")
+	return nil
+}
+
+// subscribeEvent 订阅跨链事件，收到的事件放入转发队列
+//
+//	@receiver c
+//	@param chainRid
+//	@param contractName
+//	@param fromBlock 起始高度
+//	@param to 结束高度，latest表示持续订阅
+//	@param replay 是否是运维重放，重放不影响订阅状态和事件游标
+//	@return error
+func (c *ChainClient) subscribeEvent(chainRid, contractName string, fromBlock int64, to string, replay bool) error {
 	client, err := c.getChainClient(chainRid)
 	if err != nil {
 		msg := fmt.Sprintf("[listenEvent] chain client error: %s\n", err.Error())
@@ -214,13 +295,16 @@ func (c *ChainClient) listenEvent(chainRid, contractName string) error {
 		).Hex(),
 	}
 	eventLogParams := bcostypes.EventLogParams{
-		FromBlock: fmt.Sprintf("%d", startBlcok),
-		ToBlock:   toBlock,
+		FromBlock: fmt.Sprintf("%d", fromBlock),
+		ToBlock:   to,
 		GroupID:   fmt.Sprintf("%d", client.GetGroupID()),
 		Topics:    topics,
 		Addresses: []string{contractName},
 	}
 	err = client.SubscribeEventLogs(eventLogParams, func(status int, logs []bcostypes.Log) {
+		if !replay {
+			c.updateEventState(chainRid, status, logs)
+		}
 		logRes, err2 := json.MarshalIndent(logs, "", "  ")
 		if err2 != nil {
 			c.log.Warnf("[listenEvent] logs marshalIndent error: %v", err2)
@@ -292,17 +376,20 @@ func (c *ChainClient) listenEvent(chainRid, contractName string) error {
 			Tx:           txByte,
 			TxId:         logs[0].TxHash.Hex(),
 			BlockHeight:  int64(logs[0].BlockNumber),
+			Replay:       replay,
 		}
 		c.log.Infof("[listenEvent] eventInfo: %v\n", eventInfo.ToString())
+		metrics.EventDetected.WithLabelValues(chainRid).Inc()
 
-		go request.RequestV1.BeginCrossChain(eventInfo)
+		// 队列满时阻塞在这里，暂停接收新的事件
+		request.RequestV1.Dispatch(eventInfo)
 	})
 	if err != nil {
 		c.log.Errorf("[listenEvent] listen ChainRid %s error: %s", chainRid, err.Error())
 		return fmt.Errorf("[listenEvent] listen ChainRid %s error: %s", chainRid, err.Error())
 	}
-	c.log.Infof("[listenEvent] listen ChainRid %s success: eventName %s address %s",
-		chainRid, tcipcommon.EventName_CROSS_CHAIN_TRIGGER.String(), contractName)
+	c.log.Infof("[listenEvent] listen ChainRid %s success: eventName %s address %s, from %d to %s",
+		chainRid, tcipcommon.EventName_CROSS_CHAIN_TRIGGER.String(), contractName, fromBlock, to)
 	return nil
 }
 
@@ -320,6 +407,26 @@ func (c *ChainClient) listenEvent(chainRid, contractName string) error {
 //	@return *bcostypes.TransactionDetail 交易
 //	@return error 错误信息
 func (c *ChainClient) InvokeContract(chainRid, contractName, method, abiStr string, args string,
+	needTx bool) ([]string, *bcostypes.TransactionDetail, error) {
+	start := time.Now()
+	resArr, tx, err := c.invokeContract(chainRid, contractName, method, abiStr, args, needTx)
+	metrics.ObserveSince(metrics.InvokeContractDuration.WithLabelValues(chainRid, method, metrics.Result(err)), start)
+	return resArr, tx, err
+}
+
+// invokeContract 调用合约，记录交易消耗的gas
+//
+//	@receiver c
+//	@param chainRid 链资源id
+//	@param contractName 合约名称
+//	@param method 调用方法
+//	@param abiStr abi
+//	@param args 参数
+//	@param needTx 是否需要交易
+//	@return []string 返回参数
+//	@return *bcostypes.TransactionDetail 交易
+//	@return error 错误信息
+func (c *ChainClient) invokeContract(chainRid, contractName, method, abiStr string, args string,
 	needTx bool) ([]string, *bcostypes.TransactionDetail, error) {
 	argsArr, err := dealParam(args)
 	if err != nil {
@@ -354,6 +461,9 @@ func (c *ChainClient) InvokeContract(chainRid, contractName, method, abiStr stri
 
 	c.log.Debugf("[InvokeContract] invoke contract [%s %s %s] resp: %v\n, abi: %s, args: %v",
 		chainRid, contractName, method, receipt, abiStr, args)
+	if gasUsed, err := strconv.ParseUint(strings.TrimPrefix(receipt.GasUsed, "0x"), 16, 64); err == nil {
+		metrics.InvokeContractGas.WithLabelValues(chainRid, method).Observe(float64(gasUsed))
+	}
 	if receipt.Status != bcostypes.Success {
 		msg := fmt.Sprintf("[InvokeContract] invoke contract [%s %s %s] error: %s\n, abi: %s, args: %v",
 			chainRid, contractName, method, "status error", abiStr, args)
@@ -442,13 +552,294 @@ func (c *ChainClient) GetTxProve(tx *bcostypes.TransactionDetail, chainRid strin
 //	@return bool
 func (c *ChainClient) CheckChain() bool {
 	for _, client := range c.client {
-		if _, err := client.GetBlockNumber(context.Background()); err != nil {
+		ctx, cancel := statusContext()
+		_, err := client.GetBlockNumber(ctx)
+		cancel()
+		if err != nil {
 			return false
 		}
 	}
 	return true
 }
 
+// ChainStatus 查询每条链的当前高度，并附上区块头同步和事件订阅状态，会访问链，每条链单独超时，
+// 不要在请求处理中直接调用
+//
+//	@receiver c
+//	@return []*ChainStatus
+func (c *ChainClient) ChainStatus() []*ChainStatus {
+	statuses := make([]*ChainStatus, 0, len(conf.Config.ChainConfig))
+	for _, chainConfig := range conf.Config.ChainConfig {
+		chainStatus := &ChainStatus{
+			ChainRid:    chainConfig.ChainRid,
+			EventCursor: c.getLaseCrossHeight(chainConfig.ChainRid),
+		}
+		if pending, ok := c.getPendingCrossHeight(chainConfig.ChainRid); ok {
+			chainStatus.EventCursor = pending
+			chainStatus.EventCursorPending = true
+		}
+		c.eventLock.Lock()
+		if state, ok := c.events[chainConfig.ChainRid]; ok {
+			chainStatus.Subscribed = state.subscribed
+			chainStatus.LastEventHeight = state.lastEventHeight
+			chainStatus.Error = state.err
+		}
+		c.eventLock.Unlock()
+		statuses = append(statuses, chainStatus)
+
+		client, err := c.getChainClient(chainConfig.ChainRid)
+		if err != nil {
+			chainStatus.Error = err.Error()
+			continue
+		}
+		// 每条链单独超时，一条链卡住不影响其他链的状态
+		ctx, cancel := statusContext()
+		height, err := client.GetBlockNumber(ctx)
+		cancel()
+		if err != nil {
+			chainStatus.Error = err.Error()
+			continue
+		}
+		chainStatus.Reachable = true
+		chainStatus.Height = height
+		if conf.Config.BaseConfig.TxVerifyType == conf.SpvTxVerify {
+			chainStatus.HeaderSyncHeight = c.getLaseBlockHeaderHeight(chainConfig.ChainRid)
+			chainStatus.HeaderSyncLag = height - chainStatus.HeaderSyncHeight
+		}
+	}
+	return statuses
+}
+
+// statusContext 检查链状态时访问链的超时上下文
+//
+//	@return context.Context
+//	@return context.CancelFunc
+func statusContext() (context.Context, context.CancelFunc) {
+	timeout := defaultStatusTimeout
+	if conf.Config.Health != nil && conf.Config.Health.Timeout > 0 {
+		timeout = time.Duration(conf.Config.Health.Timeout) * time.Second
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// ResyncBlockHeader 从指定高度重新同步区块头，下一轮同步时生效，只在spv验证时可用
+//
+//	@receiver c
+//	@param chainRid
+//	@param fromHeight
+//	@return error
+func (c *ChainClient) ResyncBlockHeader(chainRid string, fromHeight int64) error {
+	if conf.Config.BaseConfig.TxVerifyType != conf.SpvTxVerify {
+		return fmt.Errorf("block header is synced only when tx_verify_type is %s", conf.SpvTxVerify)
+	}
+	if _, err := c.getChainClient(chainRid); err != nil {
+		return err
+	}
+	if fromHeight < 0 {
+		return fmt.Errorf("invalid height %d", fromHeight)
+	}
+	// 同步从保存的高度的下一个块开始，保存的高度为0时从0开始
+	height := fromHeight - 1
+	if height < 0 {
+		height = 0
+	}
+	c.headerLock.Lock()
+	defer c.headerLock.Unlock()
+	if err := c.putHeight(fmt.Sprintf("%s_last_block_header_height", chainRid), height); err != nil {
+		c.log.Errorf("[ResyncBlockHeader] %s", err.Error())
+		return err
+	}
+	c.log.Infof("[ResyncBlockHeader] block header of chain %s will be synced from %d", chainRid, fromHeight)
+	return nil
+}
+
+// SetEventCursor 修改事件游标，订阅不能取消，下次订阅事件（重启）时从这个高度开始，
+// 修改的游标单独保存，不会被正在转发的事件推进游标覆盖
+//
+//	@receiver c
+//	@param chainRid
+//	@param height
+//	@return error
+func (c *ChainClient) SetEventCursor(chainRid string, height int64) error {
+	if _, err := c.getChainClient(chainRid); err != nil {
+		return err
+	}
+	if height < 0 {
+		return fmt.Errorf("invalid height %d", height)
+	}
+	if err := c.putHeight(fmt.Sprintf(pendingCrossHeightKeyFormat, chainRid), height); err != nil {
+		c.log.Errorf("[SetEventCursor] %s", err.Error())
+		return err
+	}
+	c.log.Infof("[SetEventCursor] event cursor of chain %s is set to %d", chainRid, height)
+	return nil
+}
+
+// ReplayEvent 单独订阅一段高度的跨链事件并转发，不影响正在进行的订阅和事件游标
+//
+//	@receiver c
+//	@param chainRid
+//	@param fromHeight
+//	@param toHeight 为0时重放到当前高度
+//	@return error
+func (c *ChainClient) ReplayEvent(chainRid string, fromHeight, toHeight int64) error {
+	client, err := c.getChainClient(chainRid)
+	if err != nil {
+		return err
+	}
+	contractName := ""
+	for _, chainConfig := range conf.Config.ChainConfig {
+		if chainConfig.ChainRid == chainRid {
+			contractName = chainConfig.CrossContractName
+		}
+	}
+	if toHeight == 0 {
+		// 不能使用latest，否则会变成第二个持续的订阅
+		if toHeight, err = client.GetBlockNumber(context.Background()); err != nil {
+			c.log.Errorf("[ReplayEvent] %s", err.Error())
+			return err
+		}
+	}
+	if fromHeight < 0 || fromHeight > toHeight {
+		return fmt.Errorf("invalid height range %d to %d", fromHeight, toHeight)
+	}
+	c.log.Infof("[ReplayEvent] replay events of chain %s from %d to %d", chainRid, fromHeight, toHeight)
+	return c.subscribeEvent(chainRid, contractName, fromHeight, fmt.Sprintf("%d", toHeight), true)
+}
+
+// ScanContractTx 扫描一段高度内调用合约的交易
+//
+//	@receiver c
+//	@param chainRid
+//	@param contractName 合约地址
+//	@param fromHeight
+//	@param toHeight 超过当前高度时只扫描到当前高度
+//	@return []*bcostypes.TransactionDetail
+//	@return int64 实际扫描到的高度，小于fromHeight表示没有新的区块
+//	@return error
+func (c *ChainClient) ScanContractTx(chainRid, contractName string, fromHeight, toHeight int64) (
+	[]*bcostypes.TransactionDetail, int64, error) {
+	client, err := c.getChainClient(chainRid)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fromHeight < 0 || fromHeight > toHeight {
+		return nil, 0, fmt.Errorf("invalid height range %d to %d", fromHeight, toHeight)
+	}
+	lastBlockHeight, err := client.GetBlockNumber(context.Background())
+	if err != nil {
+		c.log.Errorf("[ScanContractTx] %s", err.Error())
+		return nil, 0, err
+	}
+	if toHeight > lastBlockHeight {
+		toHeight = lastBlockHeight
+	}
+	txs := make([]*bcostypes.TransactionDetail, 0)
+	for height := fromHeight; height <= toHeight; height++ {
+		block, err := client.GetBlockByNumber(context.Background(), height, true)
+		if err != nil {
+			c.log.Errorf("[ScanContractTx] %s, height %d", err.Error(), height)
+			return nil, 0, err
+		}
+		// 包含交易时Transactions中是交易详情的json对象
+		txByte, err := json.Marshal(block.Transactions)
+		if err != nil {
+			return nil, 0, err
+		}
+		blockTxs := make([]*bcostypes.TransactionDetail, 0)
+		if err = json.Unmarshal(txByte, &blockTxs); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal transactions of block %d error: %s", height, err.Error())
+		}
+		for _, tx := range blockTxs {
+			if strings.EqualFold(tx.To, contractName) {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	return txs, toHeight, nil
+}
+
+// putHeight 保存高度
+//
+//	@receiver c
+//	@param key
+//	@param height
+//	@return error
+func (c *ChainClient) putHeight(key string, height int64) error {
+	return db.Db.Put([]byte(key), []byte(fmt.Sprintf("%d", height)))
+}
+
+// setEventState 设置事件订阅状态
+//
+//	@receiver c
+//	@param chainRid
+//	@param subscribed
+//	@param height
+//	@param errMsg
+func (c *ChainClient) setEventState(chainRid string, subscribed bool, height int64, errMsg string) {
+	c.eventLock.Lock()
+	defer c.eventLock.Unlock()
+	c.events[chainRid] = &eventState{
+		subscribed:      subscribed,
+		lastEventHeight: height,
+		err:             errMsg,
+	}
+}
+
+// updateEventState 收到订阅推送时更新订阅状态，status不为0说明订阅出错
+//
+//	@receiver c
+//	@param chainRid
+//	@param status
+//	@param logs
+func (c *ChainClient) updateEventState(chainRid string, status int, logs []bcostypes.Log) {
+	c.eventLock.Lock()
+	defer c.eventLock.Unlock()
+	state, ok := c.events[chainRid]
+	if !ok {
+		state = &eventState{}
+		c.events[chainRid] = state
+	}
+	state.subscribed = status == 0
+	state.err = ""
+	if status != 0 {
+		state.err = fmt.Sprintf("event subscription status %d", status)
+	}
+	for _, eventLog := range logs {
+		if int64(eventLog.BlockNumber) > state.lastEventHeight {
+			state.lastEventHeight = int64(eventLog.BlockNumber)
+		}
+	}
+}
+
+// CheckContract 验证合约地址合法并且已经部署
+//
+//	@receiver c
+//	@param chainRid
+//	@param contractName 合约地址
+//	@return error
+func (c *ChainClient) CheckContract(chainRid, contractName string) error {
+	if !bcoscommon.IsHexAddress(contractName) {
+		return fmt.Errorf("invalid contract address: %s", contractName)
+	}
+	client, err := c.getChainClient(chainRid)
+	if err != nil {
+		return err
+	}
+	codeByte, err := client.GetCode(context.Background(), bcoscommon.HexToAddress(contractName))
+	if err != nil {
+		msg := fmt.Sprintf("[CheckContract] get code [%s %s] error: %s", chainRid, contractName, err.Error())
+		c.log.Error(msg)
+		return errors.New(msg)
+	}
+	var code string
+	_ = json.Unmarshal(codeByte, &code)
+	if code == "" || code == "0x" {
+		return fmt.Errorf("contract not deployed: chainRid %s, address %s", chainRid, contractName)
+	}
+	return nil
+}
+
 // TxProve 交易认证
 //
 //	@receiver c
@@ -574,3 +965,47 @@ func (c *ChainClient) getLaseCrossHeight(chainRid string) int64 {
 	}
 	return dbHeight
 }
+
+// getPendingCrossHeight 运维修改的还没有生效的事件游标
+//
+//	@receiver c
+//	@param chainRid
+//	@return int64
+//	@return bool 是否修改过事件游标
+func (c *ChainClient) getPendingCrossHeight(chainRid string) (int64, bool) {
+	height, err := db.Db.Get([]byte(fmt.Sprintf(pendingCrossHeightKeyFormat, chainRid)))
+	if err != nil {
+		c.log.Errorf("[getPendingCrossHeight] %s", err.Error())
+		return 0, false
+	}
+	if len(height) == 0 {
+		return 0, false
+	}
+	dbHeight, err := strconv.ParseInt(string(height), 10, 64)
+	if err != nil {
+		c.log.Errorf("[getPendingCrossHeight] %s", err.Error())
+		return 0, false
+	}
+	return dbHeight, true
+}
+
+// takeEventCursor 订阅事件的起始高度，运维修改过事件游标时使用修改的游标并删除
+//
+//	@receiver c
+//	@param chainRid
+//	@return int64
+func (c *ChainClient) takeEventCursor(chainRid string) int64 {
+	height, ok := c.getPendingCrossHeight(chainRid)
+	if !ok {
+		return c.getLaseCrossHeight(chainRid)
+	}
+	if err := c.putHeight(fmt.Sprintf("%s_last_cross_height", chainRid), height); err != nil {
+		c.log.Errorf("[takeEventCursor] %s", err.Error())
+		return height
+	}
+	if err := db.Db.Delete([]byte(fmt.Sprintf(pendingCrossHeightKeyFormat, chainRid))); err != nil {
+		c.log.Errorf("[takeEventCursor] %s", err.Error())
+	}
+	c.log.Infof("[takeEventCursor] event cursor of chain %s is set to %d", chainRid, height)
+	return height
+}
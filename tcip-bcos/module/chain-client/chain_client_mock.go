@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 )
 
@@ -79,3 +80,79 @@ func (c *ChainClientMock) TxProve(txProve string) bool {
 func (c *ChainClientMock) CheckChain() bool {
 	return true
 }
+
+// CheckContract 验证合约
+//
+//	@receiver c
+//	@param chainRid
+//	@param contractName
+//	@return error
+func (c *ChainClientMock) CheckContract(chainRid, contractName string) error {
+	return nil
+}
+
+// ChainStatus 链状态，全部链都可用
+//
+//	@receiver c
+//	@return []*ChainStatus
+func (c *ChainClientMock) ChainStatus() []*ChainStatus {
+	statuses := make([]*ChainStatus, 0, len(conf.Config.ChainConfig))
+	for _, chainConfig := range conf.Config.ChainConfig {
+		statuses = append(statuses, &ChainStatus{
+			ChainRid:   chainConfig.ChainRid,
+			Reachable:  true,
+			Height:     10,
+			Subscribed: true,
+		})
+	}
+	return statuses
+}
+
+// ResyncBlockHeader 重新同步区块头
+//
+//	@receiver c
+//	@param chainRid
+//	@param fromHeight
+//	@return error
+func (c *ChainClientMock) ResyncBlockHeader(chainRid string, fromHeight int64) error {
+	return nil
+}
+
+// SetEventCursor 修改事件游标
+//
+//	@receiver c
+//	@param chainRid
+//	@param height
+//	@return error
+func (c *ChainClientMock) SetEventCursor(chainRid string, height int64) error {
+	return nil
+}
+
+// ReplayEvent 重放跨链事件
+//
+//	@receiver c
+//	@param chainRid
+//	@param fromHeight
+//	@param toHeight
+//	@return error
+func (c *ChainClientMock) ReplayEvent(chainRid string, fromHeight, toHeight int64) error {
+	return nil
+}
+
+// ScanContractTx 扫描合约交易，没有交易
+//
+//	@receiver c
+//	@param chainRid
+//	@param contractName
+//	@param fromHeight
+//	@param toHeight
+//	@return []*bcostypes.TransactionDetail
+//	@return int64
+//	@return error
+func (c *ChainClientMock) ScanContractTx(chainRid, contractName string, fromHeight, toHeight int64) (
+	[]*bcostypes.TransactionDetail, int64, error) {
+	if toHeight > 10 {
+		toHeight = 10
+	}
+	return []*bcostypes.TransactionDetail{}, toHeight, nil
+}
@@ -6,35 +6,86 @@ SPDX-License-Identifier: Apache-2.0
 package chain_client
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"testing"
 	"time"
 
 	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
-
+	sdk "github.com/FISCO-BCOS/go-sdk/client"
+	sdkconf "github.com/FISCO-BCOS/go-sdk/conf"
 	bcostypes "github.com/FISCO-BCOS/go-sdk/core/types"
-
-	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
-
 	"github.com/stretchr/testify/assert"
 
-	"chainmaker.org/chainmaker/tcip-bcos/v2/module/event"
-
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
-
-	chain_config "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-config"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
-	"chainmaker.org/chainmaker/tcip-go/v2/common"
 )
 
-var (
-	chainConfig *common.BcosConfig
-	eventInfo   *common.CrossChainEvent
+const (
+	chainRid     = "chain1"
+	contractName = "0x0000000000000000000000000000000000000100"
+	// privateKey sdk签名交易使用的私钥，测试中不会发送交易
+	privateKey = "145e247e170ba3afd6ae97e88f00dbc976c2345d511b0f6713355d19d8b80b58"
 )
 
-func initTest() {
+// nodeMock 用http模拟FISCO BCOS节点的json rpc
+type nodeMock struct {
+	// blocks 每个高度的交易，下标就是区块高度
+	blocks [][]*bcostypes.TransactionDetail
+	// delay 查询区块高度前等待的时间，模拟卡住的节点
+	delay time.Duration
+}
+
+// rpcRequest json rpc请求
+type rpcRequest struct {
+	Id     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (n *nodeMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &rpcRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var result interface{}
+	switch req.Method {
+	case "getClientVersion":
+		result = &bcostypes.ClientVersion{
+			ChainId:          "1",
+			FiscoBcosVersion: "2.9.0",
+			SupportedVersion: "2.9.0",
+		}
+	case "getBlockNumber":
+		time.Sleep(n.delay)
+		result = fmt.Sprintf("0x%x", len(n.blocks)-1)
+	case "getBlockByNumber":
+		var number string
+		_ = json.Unmarshal(req.Params[1], &number)
+		var height int
+		_, _ = fmt.Sscanf(number, "%d", &height)
+		txs := make([]interface{}, 0)
+		for _, tx := range n.blocks[height] {
+			txs = append(txs, tx)
+		}
+		result = &bcostypes.Block{Number: number, Transactions: txs}
+	}
+	resultByte, _ := json.Marshal(result)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.Id,
+		"result":  json.RawMessage(resultByte),
+	})
+}
+
+func initTest(t *testing.T, node *nodeMock) *ChainClient {
 	log := []*logger.LogModuleConfig{
 		{
 			ModuleName:   "default",
@@ -43,191 +94,250 @@ func initTest() {
 		},
 	}
 	logger.InitLogConfig(log)
-	chainConfig = &common.BcosConfig{
-		ChainRid:   "chain001",
-		ChainId:    chainID,
-		TlsKey:     sdkKeyText,
-		TlsCert:    sdkCrtText,
-		PrivateKey: accountPem,
-		GroupId:    groupID,
-		Address:    address,
-		Http:       false,
-		IsSmCrypto: false,
-		Ca:         caCrtText,
+	conf.Config.BaseConfig = &conf.BaseConfig{
+		GatewayID:    "0",
+		TxVerifyType: conf.NotNeedTxVerify,
 	}
-	eventInfo = &common.CrossChainEvent{
-		CrossChainEventId: "00001",
-		ChainRid:          "chain001",
-		ContractName:      "ADDRESS1",
-		CrossChainName:    "test",
-		CrossChainFlag:    "test",
-		TriggerCondition:  1,
-		EventName:         "Transfer(address,address,uint256)",
-		Timeout:           1000,
-		CrossType:         1,
-		BcosEventDataType: []common.EventDataType{8, 8, 8, 4},
-		IsCrossChain:      "two == \"0x0000000\"",
-		Abi:               []string{"{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"}", "{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"}", "{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}"},
-		ConfirmInfo: &common.ConfirmInfo{
-			ChainRid:      "chain001",
-			ContractName:  "ADDRESS2",
-			Method:        "transfer",
-			Parameter:     "[\"%s\",\"%s\"]",
-			ParamData:     []int32{1, 3},
-			ParamDataType: []common.EventDataType{8, 4},
-			Abi:           "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"minter\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"name\":\"tokenName\",\"type\":\"string\"},{\"name\":\"tokenSymbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"}]",
-		},
-		CancelInfo: &common.CancelInfo{
-			ChainRid:      "chain001",
-			ContractName:  "ADDRESS2",
-			Method:        "burn",
-			Parameter:     "[\"%s\"]",
-			ParamData:     []int32{3},
-			ParamDataType: []common.EventDataType{4},
-			Abi:           "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"minter\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"name\":\"tokenName\",\"type\":\"string\"},{\"name\":\"tokenSymbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"}]",
-		},
-		CrossChainCreate: []*common.CrossChainMsg{
-			{
-				GatewayId:     "1",
-				ChainRid:      "chain002",
-				ContractName:  "ADDRESS2",
-				Method:        "minter",
-				Parameter:     "[\"%s\"]",
-				ParamData:     []int32{3},
-				ParamDataType: []common.EventDataType{4},
-				Abi:           "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"minter\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"name\":\"tokenName\",\"type\":\"string\"},{\"name\":\"tokenSymbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"}]",
-				ConfirmInfo: &common.ConfirmInfo{
-					ChainRid:      "chain002",
-					ContractName:  "ADDRESS2",
-					Method:        "transfer",
-					Parameter:     "[\"%s\",\"%s\"]",
-					ParamData:     []int32{1, 3},
-					ParamDataType: []common.EventDataType{8, 4},
-					Abi:           "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"minter\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"name\":\"tokenName\",\"type\":\"string\"},{\"name\":\"tokenSymbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"}]",
-				},
-				CancelInfo: &common.CancelInfo{
-					ChainRid:      "chain002",
-					ContractName:  "ADDRESS2",
-					Method:        "burn",
-					Parameter:     "[\"%s\"]",
-					ParamData:     []int32{3},
-					ParamDataType: []common.EventDataType{4},
-					Abi:           "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"balance\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"minter\",\"outputs\":[{\"name\":\"success\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"name\":\"tokenName\",\"type\":\"string\"},{\"name\":\"tokenSymbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"}]",
-				},
-			},
+	conf.Config.ChainConfig = []*conf.ChainConfig{
+		{
+			ChainRid:          chainRid,
+			CrossContractName: contractName,
 		},
 	}
+	conf.Config.Health = nil
 	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
-	logger.InitLogConfig(log)
 	db.NewDbHandle()
-	chain_config.NewChainConfig()
-	event.InitEventManager()
+	t.Cleanup(func() { db.Db.Close() })
+
+	c := &ChainClient{
+		client: make(map[string]*sdk.Client),
+		log:    logger.GetLogger(logger.ModuleChainClient),
+		events: make(map[string]*eventState),
+	}
+	if node != nil {
+		c.client[chainRid] = dialNode(t, node)
+	}
+	return c
 }
 
-func TestInitChainClient(t *testing.T) {
-	initTest()
-	err := InitChainClient()
+// dialNode 连接模拟的节点
+func dialNode(t *testing.T, node *nodeMock) *sdk.Client {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	key, _ := hex.DecodeString(privateKey)
+	client, err := sdk.Dial(&sdkconf.Config{IsHTTP: true, ChainID: 1, GroupID: 1, PrivateKey: key,
+		NodeURL: server.URL})
 	assert.Nil(t, err)
+	return client
+}
 
-	_ = chain_config.ChainConfigManager.Save(chainConfig, common.Operate_SAVE)
-	_ = event.EventManagerV1.SaveEvent(eventInfo, true)
-
-	time.Sleep(time.Second * 5)
+// newNode 高度从0到height的节点，每个区块有一笔调用合约的交易和一笔其他交易
+func newNode(height int) *nodeMock {
+	node := &nodeMock{}
+	for i := 0; i <= height; i++ {
+		node.blocks = append(node.blocks, []*bcostypes.TransactionDetail{
+			{Hash: fmt.Sprintf("tx%d", i), To: contractName},
+			{Hash: fmt.Sprintf("other%d", i), To: "0x0000000000000000000000000000000000000200"},
+		})
+	}
+	return node
 }
 
 func TestGetTxProve(t *testing.T) {
-	initTest()
-	err := InitChainClient()
-	assert.Nil(t, err)
+	c := initTest(t, nil)
 
 	tx := &bcostypes.TransactionDetail{
 		Hash: "123",
 	}
-	res := ChainClientV1.GetTxProve(tx, "chain001")
-	assert.Equal(t, res, "{\"tx_prove\":\"{\\\"chain_rid\\\":\\\"Y2hhaW4wMDE=\\\",\\\"tx_byte\\\":\\\"eyJibG9ja0hhc2giOiIiLCJibG9ja051bWJlciI6IiIsImZyb20iOiIiLCJnYXMiOiIiLCJnYXNQcmljZSI6IiIsImhhc2giOiIxMjMiLCJpbnB1dCI6IiIsIm5vbmNlIjoiIiwidG8iOiIiLCJ0cmFuc2FjdGlvbkluZGV4IjoiIiwidmFsdWUiOiIifQ==\\\",\\\"tx_hash\\\":\\\"MTIz\\\"}\"}")
+	assert.Equal(t, emptyJson, c.GetTxProve(tx, chainRid))
 }
 
 func TestCheckChain(t *testing.T) {
-	initTest()
-	err := InitChainClient()
-	assert.Nil(t, err)
+	c := initTest(t, newNode(3))
+	assert.True(t, c.CheckChain())
 
-	res := ChainClientV1.CheckChain()
-	assert.False(t, res)
+	// 节点卡住时按照超时时间返回
+	c.client[chainRid] = dialNode(t, &nodeMock{blocks: newNode(3).blocks, delay: 2 * time.Second})
+	conf.Config.Health = &conf.HealthConfig{Timeout: 1}
+	start := time.Now()
+	assert.False(t, c.CheckChain())
+	assert.True(t, time.Since(start) < 2*time.Second)
 }
 
 func TestTxProve(t *testing.T) {
-	initTest()
-	err := InitChainClient()
-	assert.Nil(t, err)
+	c := initTest(t, nil)
 
-	tx := &bcostypes.TransactionDetail{
-		Hash: "123",
-	}
-	res := ChainClientV1.GetTxProve(tx, "chain001")
-	verify := ChainClientV1.TxProve(res)
+	txProve, _ := json.Marshal(map[string][]byte{
+		"chain_rid": []byte(chainRid),
+		"tx_hash":   []byte("123"),
+		"tx_byte":   []byte("{}"),
+	})
+	verify := c.TxProve(string(txProve))
 	assert.False(t, verify)
 	var req cross_chain.TxVerifyRequest
-	_ = json.Unmarshal([]byte(res), &req)
-	verify = ChainClientV1.TxProve(req.TxProve)
+	_ = json.Unmarshal(txProve, &req)
+	verify = c.TxProve(req.TxProve)
 	assert.False(t, verify)
 }
 
 func TestInvokeContract(t *testing.T) {
-	initTest()
-	err := InitChainClient()
-	assert.Nil(t, err)
+	c := initTest(t, nil)
 
-	args := []string{
-		"0x12345678",
-		"fjdisaofiewja",
-		"1",
-		"1.01",
-		"true",
-		"{\"a\":\"MTIz\"}",
-		"123",
-		"[\"MTIz\",\"MTIz\",\"MTIz\"]",
-		"123456",
+	args, _ := json.Marshal([]string{"0x12345678", "1"})
+	_, _, err := c.InvokeContract(chainRid, contractName, "method", "abi", string(args), false)
+	assert.NotNil(t, err)
+	_, _, err = c.InvokeContract(chainRid, contractName, "method", "abi", "{\a\"", false)
+	assert.NotNil(t, err)
+}
+
+func TestChainStatus(t *testing.T) {
+	c := initTest(t, newNode(5))
+
+	assert.Nil(t, c.putHeight(fmt.Sprintf("%s_last_cross_height", chainRid), 2))
+	c.setEventState(chainRid, true, 2, "")
+	statuses := c.ChainStatus()
+	assert.Equal(t, 1, len(statuses))
+	assert.True(t, statuses[0].Reachable)
+	assert.Equal(t, int64(5), statuses[0].Height)
+	assert.Equal(t, int64(2), statuses[0].EventCursor)
+	assert.False(t, statuses[0].EventCursorPending)
+	assert.True(t, statuses[0].Subscribed)
+
+	// 修改的事件游标还没有生效
+	assert.Nil(t, c.SetEventCursor(chainRid, 4))
+	statuses = c.ChainStatus()
+	assert.Equal(t, int64(4), statuses[0].EventCursor)
+	assert.True(t, statuses[0].EventCursorPending)
+
+	// 节点卡住时按照超时时间返回
+	c.client[chainRid] = dialNode(t, &nodeMock{blocks: newNode(5).blocks, delay: 2 * time.Second})
+	conf.Config.Health = &conf.HealthConfig{Timeout: 1}
+	start := time.Now()
+	statuses = c.ChainStatus()
+	assert.True(t, time.Since(start) < 2*time.Second)
+	assert.False(t, statuses[0].Reachable)
+	assert.NotEqual(t, "", statuses[0].Error)
+}
+
+func TestScanContractTx(t *testing.T) {
+	c := initTest(t, newNode(5))
+
+	tests := []struct {
+		name        string
+		chainRid    string
+		fromHeight  int64
+		toHeight    int64
+		wantErr     bool
+		wantTxs     []string
+		wantScanned int64
+	}{
+		{
+			name:        "range",
+			chainRid:    chainRid,
+			fromHeight:  1,
+			toHeight:    3,
+			wantTxs:     []string{"tx1", "tx2", "tx3"},
+			wantScanned: 3,
+		},
+		{
+			name:        "beyond current height",
+			chainRid:    chainRid,
+			fromHeight:  4,
+			toHeight:    10,
+			wantTxs:     []string{"tx4", "tx5"},
+			wantScanned: 5,
+		},
+		{
+			name:        "no new block",
+			chainRid:    chainRid,
+			fromHeight:  6,
+			toHeight:    10,
+			wantTxs:     []string{},
+			wantScanned: 5,
+		},
+		{
+			name:       "invalid range",
+			chainRid:   chainRid,
+			fromHeight: 3,
+			toHeight:   1,
+			wantErr:    true,
+		},
+		{
+			name:       "unknown chain",
+			chainRid:   "chain2",
+			fromHeight: 1,
+			toHeight:   3,
+			wantErr:    true,
+		},
 	}
-	argsStr, _ := json.Marshal(args)
-	paramType := []common.EventDataType{
-		common.EventDataType_ADDRESS,
-		common.EventDataType_HASH,
-		common.EventDataType_INT,
-		common.EventDataType_FLOAT,
-		common.EventDataType_BOOL,
-		common.EventDataType_MAP,
-		common.EventDataType_STRING,
-		common.EventDataType_ARRAY,
-		common.EventDataType_BYTE,
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs, scanned, err := c.ScanContractTx(tt.chainRid, contractName, tt.fromHeight, tt.toHeight)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			hashes := make([]string, 0)
+			for _, tx := range txs {
+				hashes = append(hashes, tx.Hash)
+			}
+			assert.Equal(t, tt.wantTxs, hashes)
+			assert.Equal(t, tt.wantScanned, scanned)
+		})
 	}
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+}
 
-	paramType[0] = common.EventDataType_INT
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+func TestReplayEvent(t *testing.T) {
+	c := initTest(t, newNode(5))
 
-	paramType[0] = common.EventDataType_FLOAT
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+	assert.NotNil(t, c.ReplayEvent("chain2", 1, 3))
+	assert.NotNil(t, c.ReplayEvent(chainRid, -1, 3))
+	assert.NotNil(t, c.ReplayEvent(chainRid, 4, 3))
+	// 重放到当前高度
+	assert.NotNil(t, c.ReplayEvent(chainRid, 6, 0))
+}
 
-	paramType[0] = common.EventDataType_BOOL
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+func TestResyncBlockHeader(t *testing.T) {
+	c := initTest(t, newNode(5))
 
-	paramType[0] = common.EventDataType_MAP
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+	// 不需要spv验证时不同步区块头
+	assert.NotNil(t, c.ResyncBlockHeader(chainRid, 1))
 
-	paramType[0] = common.EventDataType_ARRAY
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+	conf.Config.BaseConfig.TxVerifyType = conf.SpvTxVerify
+	assert.NotNil(t, c.ResyncBlockHeader("chain2", 1))
+	assert.NotNil(t, c.ResyncBlockHeader(chainRid, -1))
+	assert.Nil(t, c.ResyncBlockHeader(chainRid, 3))
+	assert.Equal(t, int64(2), c.getLaseBlockHeaderHeight(chainRid))
+	assert.Nil(t, c.ResyncBlockHeader(chainRid, 0))
+	assert.Equal(t, int64(0), c.getLaseBlockHeaderHeight(chainRid))
+}
 
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", "{\a\"", false, paramType)
-	assert.NotNil(t, err)
+func TestSetEventCursor(t *testing.T) {
+	c := initTest(t, newNode(5))
 
-	paramType = append(paramType, common.EventDataType_BYTE)
-	_, _, err = ChainClientV1.InvokeContract("chain001", "contract", "method", "abi", string(argsStr), false, paramType)
-	assert.NotNil(t, err)
+	assert.NotNil(t, c.SetEventCursor("chain2", 1))
+	assert.NotNil(t, c.SetEventCursor(chainRid, -1))
+	assert.Nil(t, c.SetEventCursor(chainRid, 3))
+	// 转发事件推进游标不会覆盖修改的游标
+	assert.Nil(t, c.putHeight(fmt.Sprintf("%s_last_cross_height", chainRid), 4))
+	assert.Equal(t, int64(3), c.takeEventCursor(chainRid))
+}
+
+func TestTakeEventCursor(t *testing.T) {
+	c := initTest(t, nil)
+
+	assert.Nil(t, c.putHeight("chain1_last_cross_height", 10))
+	assert.Equal(t, int64(10), c.takeEventCursor("chain1"))
+
+	// 修改的游标不会被转发事件推进的游标覆盖，下次订阅时生效
+	assert.Nil(t, c.putHeight(fmt.Sprintf(pendingCrossHeightKeyFormat, "chain1"), 5))
+	assert.Nil(t, c.putHeight("chain1_last_cross_height", 20))
+	pending, ok := c.getPendingCrossHeight("chain1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), pending)
+	assert.Equal(t, int64(5), c.takeEventCursor("chain1"))
+	assert.Equal(t, int64(5), c.getLaseCrossHeight("chain1"))
+	_, ok = c.getPendingCrossHeight("chain1")
+	assert.False(t, ok)
 }
@@ -7,10 +7,13 @@ package conf
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 )
@@ -55,12 +58,11 @@ func InitLocalConfig(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	// 处理 log config
-	logModuleConfigs := config.LogConfig
-	for i := 0; i < len(logModuleConfigs); i++ {
-		logModuleConfig := logModuleConfigs[i]
-		logModuleConfig.FilePath = GetAbsPath(logModuleConfig.FilePath)
+	if err = Validate(config); err != nil {
+		return err
 	}
+	// 处理 log config
+	setLogAbsPath(config)
 	// 2. set log config
 	logger.InitLogConfig(config.LogConfig)
 	// 3. set global config and export
@@ -71,21 +73,29 @@ func InitLocalConfig(cmd *cobra.Command) error {
 	return nil
 }
 
+// setLogAbsPath 日志文件使用绝对路径
+//  @param config
+func setLogAbsPath(config *LocalConfig) {
+	logModuleConfigs := config.LogConfig
+	for i := 0; i < len(logModuleConfigs); i++ {
+		logModuleConfig := logModuleConfigs[i]
+		logModuleConfig.FilePath = GetAbsPath(logModuleConfig.FilePath)
+	}
+}
+
 // initLocal 初始化本地配置
 //  @param cmd
 //  @return *LocalConfig
 //  @return error
 func initLocal(cmd *cobra.Command) (*LocalConfig, error) {
-	cmViper := viper.New()
-
 	// 1. load the path of the config files
 	ymlFile := ConfigFilePath
 	ymlFile = GetAbsPath(ymlFile)
 	ConfigFilePath = ymlFile
 
-	// 2. load the config file
-	cmViper.SetConfigFile(ymlFile)
-	if err := cmViper.ReadInConfig(); err != nil {
+	// 2. load the config file and environment variables
+	cmViper, err := newViper(ymlFile)
+	if err != nil {
 		return nil, err
 	}
 
@@ -97,12 +107,65 @@ func initLocal(cmd *cobra.Command) (*LocalConfig, error) {
 	}
 
 	// 3. create new CMConfig instance
-	config := &LocalConfig{}
-	if err := cmViper.Unmarshal(config); err != nil {
+	return unmarshal(cmViper)
+}
+
+// ReadLocalConfig 重新读取配置文件，不处理命令行参数，用于配置文件变化后刷新部分配置
+//  @param ymlFile
+//  @return *LocalConfig
+//  @return error
+func ReadLocalConfig(ymlFile string) (*LocalConfig, error) {
+	cmViper, err := newViper(ymlFile)
+	if err != nil {
 		return nil, err
 	}
+	return unmarshal(cmViper)
+}
 
-	return config, nil
+var (
+	// gatewayIdLine base.gateway_id所在的行，保留行尾的注释
+	gatewayIdLine = regexp.MustCompile(`^(\s+gateway_id:\s*)([^#]*?)(\s*#.*)?$`)
+	// plainScalar 不需要加引号的yaml值
+	plainScalar = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// SetGatewayId 把中继网关分配的网关ID写回配置文件的base.gateway_id，只修改这一行，保留注释和格式
+//  @param configPath
+//  @param gatewayId
+//  @return error
+func SetGatewayId(configPath, gatewayId string) error {
+	content, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	value := gatewayId
+	if !plainScalar.MatchString(value) {
+		value = strconv.Quote(value)
+	}
+	lines := strings.Split(string(content), "\n")
+	baseIndex := -1
+	for i, line := range lines {
+		if baseIndex < 0 {
+			if strings.HasPrefix(line, "base:") {
+				baseIndex = i
+			}
+			continue
+		}
+		// base块结束
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			break
+		}
+		if match := gatewayIdLine.FindStringSubmatch(line); match != nil {
+			lines[i] = strings.TrimRight(match[1], " \t") + " " + value + match[3]
+			return ioutil.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644)
+		}
+	}
+	if baseIndex < 0 {
+		lines = append([]string{"base:", "  gateway_id: " + value}, lines...)
+	} else {
+		lines = append(lines[:baseIndex+1], append([]string{"  gateway_id: " + value}, lines[baseIndex+1:]...)...)
+	}
+	return ioutil.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644)
 }
 
 // GetAbsPath 获取绝对路径
@@ -0,0 +1,67 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSetGatewayId(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		gatewayId string
+		want      string
+	}{
+		{
+			name:      "replace and keep comment",
+			content:   "base:\n  gateway_id: 0      # 网关ID\n  gateway_name: a\nrelay:\n  gateway_id: 9\n",
+			gatewayId: "12",
+			want:      "base:\n  gateway_id: 12      # 网关ID\n  gateway_name: a\nrelay:\n  gateway_id: 9\n",
+		},
+		{
+			name:      "quote",
+			content:   "base:\n  gateway_id:\n",
+			gatewayId: "a b",
+			want:      "base:\n  gateway_id: \"a b\"\n",
+		},
+		{
+			name:      "missing gateway_id",
+			content:   "base:\n  gateway_name: a\nrelay:\n  gateway_id: 9\n",
+			gatewayId: "1",
+			want:      "base:\n  gateway_id: 1\n  gateway_name: a\nrelay:\n  gateway_id: 9\n",
+		},
+		{
+			name:      "missing base",
+			content:   "relay:\n  gateway_id: 9\n",
+			gatewayId: "1",
+			want:      "base:\n  gateway_id: 1\nrelay:\n  gateway_id: 9\n",
+		},
+	}
+	dir, err := ioutil.TempDir("", "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := path.Join(dir, "tcip_bcos.yml")
+			if err := ioutil.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := SetGatewayId(configPath, tt.gatewayId); err != nil {
+				t.Fatalf("SetGatewayId() error = %v", err)
+			}
+			got, _ := ioutil.ReadFile(configPath)
+			if string(got) != tt.want {
+				t.Errorf("SetGatewayId() got = %q, want %q", string(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// EnvPrefix 环境变量前缀，例如relay.access_code对应TCIP_BCOS_RELAY_ACCESS_CODE
+	EnvPrefix = "TCIP_BCOS"
+	// SecretFilePrefix 密钥从文件读取，例如access_code: file:/run/secrets/access_code
+	SecretFilePrefix = "file:"
+	// SecretEnvPrefix 密钥从环境变量读取，例如access_code: env:RELAY_ACCESS_CODE
+	SecretEnvPrefix = "env:"
+	// redacted 打印配置时替换密钥
+	redacted = "******"
+)
+
+// newViper 读取配置文件，每个配置项都可以被带前缀的环境变量覆盖
+//
+//	@param ymlFile
+//	@return *viper.Viper
+//	@return error
+func newViper(ymlFile string) (*viper.Viper, error) {
+	cmViper := viper.New()
+	cmViper.SetConfigFile(ymlFile)
+	if err := cmViper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	cmViper.SetEnvPrefix(EnvPrefix)
+	cmViper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// AutomaticEnv只覆盖配置文件中已有的key，配置文件中没有的key需要逐个绑定
+	if err := bindEnvs(cmViper, reflect.TypeOf(LocalConfig{}), ""); err != nil {
+		return nil, err
+	}
+	return cmViper, nil
+}
+
+// bindEnvs 按mapstructure标签绑定每个配置项的环境变量，
+// 列表按逗号分隔的字符串覆盖，元素是结构体的列表（例如chain_config）不能用环境变量覆盖
+//
+//	@param cmViper
+//	@param t
+//	@param prefix
+//	@return error
+func bindEnvs(cmViper *viper.Viper, t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := prefix + tag
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			if err := bindEnvs(cmViper, fieldType, key+"."); err != nil {
+				return err
+			}
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Ptr &&
+			fieldType.Elem().Kind() != reflect.Struct:
+			if err := cmViper.BindEnv(key); err != nil {
+				return err
+			}
+		case fieldType.Kind() != reflect.Slice:
+			if err := cmViper.BindEnv(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshal 解析配置并读取密钥引用
+//
+//	@param cmViper
+//	@return *LocalConfig
+//	@return error
+func unmarshal(cmViper *viper.Viper) (*LocalConfig, error) {
+	config := &LocalConfig{}
+	if err := cmViper.Unmarshal(config); err != nil {
+		return nil, err
+	}
+	if err := resolveSecrets(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// resolveSecrets 把授权码、私钥密码和token中的file:、env:引用替换为实际的值
+//
+//	@param config
+//	@return error
+func resolveSecrets(config *LocalConfig) error {
+	var err error
+	if config.Relay != nil {
+		if config.Relay.AccessCode, err = resolveSecret("relay.access_code", config.Relay.AccessCode); err != nil {
+			return err
+		}
+		if config.Relay.ClientKeyPassword, err = resolveSecret("relay.client_key_password",
+			config.Relay.ClientKeyPassword); err != nil {
+			return err
+		}
+	}
+	if config.RpcConfig != nil {
+		if config.RpcConfig.TLSConfig.KeyPassword, err = resolveSecret("rpc.tls.key_password",
+			config.RpcConfig.TLSConfig.KeyPassword); err != nil {
+			return err
+		}
+		for i, identity := range config.RpcConfig.Auth.Identities {
+			for j, token := range identity.Tokens {
+				path := fmt.Sprintf("rpc.auth.identities[%d].tokens[%d]", i, j)
+				if identity.Tokens[j], err = resolveSecret(path, token); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveSecret 读取一个密钥引用，文件末尾的换行会被去掉，不是引用时原样返回
+//
+//	@param path yaml中的路径，用于错误信息
+//	@param value
+//	@return string
+//	@return error
+func resolveSecret(path, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretFilePrefix):
+		file := strings.TrimPrefix(value, SecretFilePrefix)
+		content, err := ioutil.ReadFile(GetAbsPath(file))
+		if err != nil {
+			return "", fmt.Errorf("%s: read secret file failed, %s", path, err.Error())
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%s: environment variable %s is not set", path, name)
+		}
+		return secret, nil
+	}
+	return value, nil
+}
+
+// Redacted 生效的配置，授权码、私钥密码和token被替换，用于打印
+//
+//	@param config
+//	@return string
+func Redacted(config *LocalConfig) string {
+	configByte, err := json.Marshal(config)
+	if err != nil {
+		return err.Error()
+	}
+	// 复制一份，不修改正在使用的配置
+	copied := &LocalConfig{}
+	if err = json.Unmarshal(configByte, copied); err != nil {
+		return err.Error()
+	}
+	if copied.Relay != nil {
+		copied.Relay.AccessCode = redact(copied.Relay.AccessCode)
+		copied.Relay.ClientKeyPassword = redact(copied.Relay.ClientKeyPassword)
+	}
+	if copied.RpcConfig != nil {
+		copied.RpcConfig.TLSConfig.KeyPassword = redact(copied.RpcConfig.TLSConfig.KeyPassword)
+		for _, identity := range copied.RpcConfig.Auth.Identities {
+			for i := range identity.Tokens {
+				identity.Tokens[i] = redact(identity.Tokens[i])
+			}
+		}
+	}
+	configByte, err = json.Marshal(copied)
+	if err != nil {
+		return err.Error()
+	}
+	return string(configByte)
+}
+
+// redact 有值时替换为******，保留是否配置的信息
+//
+//	@param secret
+//	@return string
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadLocalConfigEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conf")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	secretFile := path.Join(dir, "access_code")
+	assert.Nil(t, ioutil.WriteFile(secretFile, []byte("fileCode\n"), 0600))
+	ymlFile := path.Join(dir, "tcip_bcos.yml")
+
+	tests := []struct {
+		name    string
+		yml     string
+		env     map[string]string
+		check   func(t *testing.T, config *LocalConfig)
+		wantErr bool
+	}{
+		{
+			name: "override existing and missing keys",
+			yml:  "rpc:\n  port: 19996\nrelay:\n  access_code: code\n",
+			env: map[string]string{
+				"TCIP_BCOS_RPC_PORT":                   "20000",
+				"TCIP_BCOS_RPC_BLACKLIST":              "10.0.0.1,10.1.0.0/16",
+				"TCIP_BCOS_BLOCK_HEADER_SYNC_INTERVAL": "60",
+				"TCIP_BCOS_RPC_TLS_KEY_FILE":           "server.key",
+			},
+			check: func(t *testing.T, config *LocalConfig) {
+				assert.Equal(t, 20000, config.RpcConfig.Port)
+				assert.Equal(t, []string{"10.0.0.1", "10.1.0.0/16"}, config.RpcConfig.BlackList)
+				assert.Equal(t, uint64(60), config.BlockHeaderSync.Interval)
+				assert.Equal(t, "server.key", config.RpcConfig.TLSConfig.KeyFile)
+				assert.Equal(t, "code", config.Relay.AccessCode)
+			},
+		},
+		{
+			name: "secret from file",
+			yml:  "relay:\n  access_code: file:" + secretFile + "\n",
+			check: func(t *testing.T, config *LocalConfig) {
+				assert.Equal(t, "fileCode", config.Relay.AccessCode)
+			},
+		},
+		{
+			name: "secret from env",
+			yml: "relay:\n  client_key_password: env:TEST_KEY_PASSWORD\n" +
+				"rpc:\n  tls:\n    key_password: env:TEST_KEY_PASSWORD\n" +
+				"  auth:\n    identities:\n      - tokens: [env:TEST_TOKEN, plain]\n",
+			env: map[string]string{"TEST_KEY_PASSWORD": "password", "TEST_TOKEN": "token"},
+			check: func(t *testing.T, config *LocalConfig) {
+				assert.Equal(t, "password", config.Relay.ClientKeyPassword)
+				assert.Equal(t, "password", config.RpcConfig.TLSConfig.KeyPassword)
+				assert.Equal(t, []string{"token", "plain"}, config.RpcConfig.Auth.Identities[0].Tokens)
+			},
+		},
+		{
+			name:    "secret env not set",
+			yml:     "relay:\n  access_code: env:TEST_NOT_SET\n",
+			wantErr: true,
+		},
+		{
+			name:    "secret file not found",
+			yml:     "relay:\n  access_code: file:" + path.Join(dir, "missing") + "\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, ioutil.WriteFile(ymlFile, []byte(tt.yml), 0600))
+			for k, v := range tt.env {
+				assert.Nil(t, os.Setenv(k, v))
+			}
+			defer func() {
+				for k := range tt.env {
+					_ = os.Unsetenv(k)
+				}
+			}()
+			config, err := ReadLocalConfig(ymlFile)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	config := &LocalConfig{
+		Relay: &Relay{AccessCode: "secretCode", ClientKeyPassword: "secretPassword", Address: "127.0.0.1:19999"},
+		RpcConfig: &RpcConfig{TLSConfig: TlsConfig{KeyPassword: "secretRpcPassword"}, Auth: AuthConfig{Enable: true,
+			Identities: []*AuthIdentity{{Name: "relay", Tokens: []string{"secretToken"}}}}},
+	}
+	redactedConfig := Redacted(config)
+	for _, secret := range []string{"secretCode", "secretPassword", "secretRpcPassword", "secretToken"} {
+		assert.False(t, strings.Contains(redactedConfig, secret))
+	}
+	assert.True(t, strings.Contains(redactedConfig, "127.0.0.1:19999"))
+	assert.True(t, strings.Contains(redactedConfig, redacted))
+	// 不修改原来的配置
+	assert.Equal(t, "secretCode", config.Relay.AccessCode)
+	assert.Equal(t, "secretToken", config.RpcConfig.Auth.Identities[0].Tokens[0])
+}
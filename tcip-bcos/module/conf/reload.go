@@ -0,0 +1,181 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+)
+
+var (
+	// runtimePaths 不需要重启就可以生效的配置
+	runtimePaths = map[string]bool{
+		"base.default_timeout":          true,
+		"rpc.allowlist":                 true,
+		"rpc.denylist":                  true,
+		"rpc.blacklist":                 true,
+		"rpc.trusted_proxies":           true,
+		"rpc.trust_forwarded_for":       true,
+		"block_header_sync.interval":    true,
+		"block_header_sync.batch_count": true,
+	}
+	// logLevelPath 日志级别的路径，例如log[0].log_level
+	logLevelPath = regexp.MustCompile(`^log\[(\d+)]\.log_level$`)
+	// reloadLock 文件变化和SIGHUP可能同时触发重新加载
+	reloadLock sync.Mutex
+)
+
+// ReloadResult 重新加载配置的结果
+type ReloadResult struct {
+	// Applied 已经生效的配置路径
+	Applied []string
+	// Restart 有变化但是需要重启才能生效的配置路径
+	Restart []string
+	// RpcConfig 检查过的新rpc配置，用来重建访问策略
+	RpcConfig *RpcConfig
+}
+
+// Reload 重新读取并检查配置文件，把可以运行时修改的配置更新到运行时配置和日志模块，不修改Config，
+// 默认超时、区块头同步间隔和批次通过DefaultTimeout等函数生效，访问名单由调用方用返回的RpcConfig更新，
+// 配置有误时不做任何修改
+//
+//	@return *ReloadResult
+//	@return error
+func Reload() (*ReloadResult, error) {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+	config, err := ReadLocalConfig(ConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = Validate(config); err != nil {
+		return nil, err
+	}
+	setLogAbsPath(config)
+	result := &ReloadResult{RpcConfig: config.RpcConfig}
+	for _, path := range diffConfig(reflect.ValueOf(effectiveConfig()).Elem(), reflect.ValueOf(config).Elem(), "") {
+		if (runtimePaths[path] || logLevelPath.MatchString(path)) && apply(config, path) {
+			result.Applied = append(result.Applied, path)
+			continue
+		}
+		result.Restart = append(result.Restart, path)
+	}
+	return result, nil
+}
+
+// apply 把新配置中的一项更新到运行时配置，日志级别同时更新到日志模块
+//
+//	@param config 检查过的新配置
+//	@param path
+//	@return bool 是否生效，日志模块名也变化时需要重启
+func apply(config *LocalConfig, path string) bool {
+	switch path {
+	case "base.default_timeout":
+		setRuntime(func(values *runtimeConfig) { values.defaultTimeout = config.BaseConfig.DefaultTimeout })
+	case "rpc.allowlist":
+		setRuntime(func(values *runtimeConfig) { values.allowList = config.RpcConfig.AllowList })
+	case "rpc.denylist":
+		setRuntime(func(values *runtimeConfig) { values.denyList = config.RpcConfig.DenyList })
+	case "rpc.blacklist":
+		setRuntime(func(values *runtimeConfig) { values.blackList = config.RpcConfig.BlackList })
+	case "rpc.trusted_proxies":
+		setRuntime(func(values *runtimeConfig) { values.trustedProxies = config.RpcConfig.TrustedProxies })
+	case "rpc.trust_forwarded_for":
+		setRuntime(func(values *runtimeConfig) { values.trustForwardedFor = config.RpcConfig.TrustForwardedFor })
+	case "block_header_sync.interval":
+		setRuntime(func(values *runtimeConfig) { values.headerSyncInterval = config.BlockHeaderSync.Interval })
+	case "block_header_sync.batch_count":
+		setRuntime(func(values *runtimeConfig) { values.headerSyncBatchCount = config.BlockHeaderSync.BatchCount })
+	default:
+		index, _ := strconv.Atoi(logLevelPath.FindStringSubmatch(path)[1])
+		logConfig := config.LogConfig[index]
+		if logConfig.ModuleName != Config.LogConfig[index].ModuleName {
+			return false
+		}
+		level := logConfig.LogLevel
+		if level == "" {
+			// 和logger.GetLogLevel保持一致
+			level = logger.DEBUG
+		}
+		if err := logger.SetLogLevel(logConfig.ModuleName, level); err != nil {
+			return false
+		}
+		setRuntime(func(values *runtimeConfig) { values.logLevels[index] = logConfig.LogLevel })
+	}
+	return true
+}
+
+// diffConfig 按mapstructure标签比较两份配置，返回有变化的配置路径，
+// 列表长度变化或者section新增、删除时返回整个列表或者section的路径
+//
+//	@param old
+//	@param current
+//	@param prefix
+//	@return []string
+func diffConfig(old, current reflect.Value, prefix string) []string {
+	switch old.Kind() {
+	case reflect.Ptr:
+		if old.IsNil() || current.IsNil() {
+			if old.IsNil() != current.IsNil() {
+				return []string{strings.TrimSuffix(prefix, ".")}
+			}
+			return nil
+		}
+		return diffConfig(old.Elem(), current.Elem(), prefix)
+	case reflect.Struct:
+		paths := make([]string, 0)
+		for i := 0; i < old.NumField(); i++ {
+			tag := strings.Split(old.Type().Field(i).Tag.Get("mapstructure"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			paths = append(paths, diffConfig(old.Field(i), current.Field(i), prefix+tag+".")...)
+		}
+		return paths
+	case reflect.Slice:
+		path := strings.TrimSuffix(prefix, ".")
+		if old.Len() != current.Len() {
+			return []string{path}
+		}
+		elemKind := old.Type().Elem().Kind()
+		if elemKind != reflect.Ptr && elemKind != reflect.Struct {
+			if !reflect.DeepEqual(old.Interface(), current.Interface()) {
+				return []string{path}
+			}
+			return nil
+		}
+		paths := make([]string, 0)
+		for i := 0; i < old.Len(); i++ {
+			paths = append(paths, diffConfig(old.Index(i), current.Index(i), fmt.Sprintf("%s[%d].", path, i))...)
+		}
+		return paths
+	}
+	if !reflect.DeepEqual(old.Interface(), current.Interface()) {
+		return []string{strings.TrimSuffix(prefix, ".")}
+	}
+	return nil
+}
+
+// WatchConfig 监听配置文件变化，变化后调用onChange
+//
+//	@param onChange
+func WatchConfig(onChange func()) {
+	cmViper := viper.New()
+	cmViper.SetConfigFile(ConfigFilePath)
+	cmViper.OnConfigChange(func(event fsnotify.Event) {
+		onChange()
+	})
+	cmViper.WatchConfig()
+}
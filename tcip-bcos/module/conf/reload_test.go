@@ -0,0 +1,102 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	// 示例配置中的文件路径相对于项目根目录
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir("../.."))
+	defer func() { _ = os.Chdir(wd) }()
+	dir, err := ioutil.TempDir("", "reload")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	sample, err := ioutil.ReadFile("config/tcip_bcos.yml")
+	assert.Nil(t, err)
+	base := strings.Replace(string(sample), "file_path: logs/default.log", "file_path: "+path.Join(dir, "default.log"), 1)
+	ConfigFilePath = path.Join(dir, "tcip_bcos.yml")
+
+	tests := []struct {
+		name        string
+		replace     []string
+		wantErr     bool
+		wantApplied []string
+		wantRestart []string
+		wantLevel   string
+	}{
+		{
+			name: "nothing changed",
+		},
+		{
+			name: "runtime settings",
+			replace: []string{"default_timeout: 1000", "default_timeout: 30", "interval: 300", "interval: 60",
+				"log_level: DEBUG", "log_level: INFO", "denylist: []", "denylist: [\"10.0.0.1\"]"},
+			wantApplied: []string{"base.default_timeout", "rpc.denylist", "block_header_sync.interval",
+				"log[0].log_level"},
+			wantLevel: logger.INFO,
+		},
+		{
+			name:        "restart required",
+			replace:     []string{"port: 19998", "port: 19997", "default_timeout: 1000", "default_timeout: 30"},
+			wantApplied: []string{"base.default_timeout"},
+			wantRestart: []string{"rpc.port"},
+		},
+		{
+			name:    "invalid config",
+			replace: []string{"default_timeout: 1000", "default_timeout: 30", "batch_count: 1000", "batch_count: 0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, ioutil.WriteFile(ConfigFilePath, []byte(base), 0600))
+			Config, err = ReadLocalConfig(ConfigFilePath)
+			assert.Nil(t, err)
+			setLogAbsPath(Config)
+			logger.InitLogConfig(Config.LogConfig)
+
+			assert.Nil(t, ioutil.WriteFile(ConfigFilePath,
+				[]byte(strings.NewReplacer(tt.replace...).Replace(base)), 0600))
+			result, err := Reload()
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, uint32(1000), DefaultTimeout())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantApplied, result.Applied)
+			assert.Equal(t, tt.wantRestart, result.Restart)
+			if len(tt.wantApplied) != 0 {
+				assert.Equal(t, uint32(30), DefaultTimeout())
+				// 重新加载的值不写回Config，避免和读取Config的协程竞争
+				assert.Equal(t, uint32(1000), Config.BaseConfig.DefaultTimeout)
+				assert.Equal(t, 0, len(Config.RpcConfig.DenyList))
+				assert.Equal(t, result.RpcConfig.DenyList, effectiveConfig().RpcConfig.DenyList)
+			}
+			if tt.wantLevel != "" {
+				assert.Equal(t, tt.wantLevel, logger.GetLogLevels()[logger.ModuleDefault])
+				assert.Equal(t, "DEBUG", Config.LogConfig[0].LogLevel)
+				assert.Equal(t, tt.wantLevel, effectiveConfig().LogConfig[0].LogLevel)
+				// 和生效的值比较，再次加载同样的配置没有变化
+				result, err = Reload()
+				assert.Nil(t, err)
+				assert.Equal(t, 0, len(result.Applied)+len(result.Restart))
+			}
+		})
+	}
+	assert.Equal(t, uint64(300), HeaderSyncInterval())
+}
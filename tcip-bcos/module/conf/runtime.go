@@ -0,0 +1,135 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"sync"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+)
+
+// runtimeConfig 运行时重新加载的配置，Reload在其他协程读取时修改，所以不写回Config，
+// 默认超时、区块头同步间隔和批次通过DefaultTimeout等函数读取，访问名单和日志级别只用于和新配置比较
+type runtimeConfig struct {
+	// config 这些值所属的配置，Config被整体替换后使用新配置中的值
+	config               *LocalConfig
+	defaultTimeout       uint32
+	headerSyncInterval   uint64
+	headerSyncBatchCount int64
+	// allowList 生效的rpc访问名单和代理配置，访问策略由rpcserver.ReloadAccessPolicy更新
+	allowList         []string
+	denyList          []string
+	blackList         []string
+	trustedProxies    []string
+	trustForwardedFor bool
+	// logLevels 生效的日志级别，下标和LogConfig相同
+	logLevels []string
+}
+
+var (
+	runtimeLock   sync.RWMutex
+	runtimeValues *runtimeConfig
+)
+
+// DefaultTimeout 生效的默认全局超时时间, s
+//
+//	@return uint32
+func DefaultTimeout() uint32 {
+	return getRuntime().defaultTimeout
+}
+
+// HeaderSyncInterval 生效的区块头同步间隔, s
+//
+//	@return uint64
+func HeaderSyncInterval() uint64 {
+	return getRuntime().headerSyncInterval
+}
+
+// HeaderSyncBatchCount 生效的每次同步区块头的个数
+//
+//	@return int64
+func HeaderSyncBatchCount() int64 {
+	return getRuntime().headerSyncBatchCount
+}
+
+// getRuntime 当前生效的运行时配置，没有重新加载过时使用Config中的值
+//
+//	@return runtimeConfig
+func getRuntime() runtimeConfig {
+	runtimeLock.RLock()
+	defer runtimeLock.RUnlock()
+	if runtimeValues != nil && runtimeValues.config == Config {
+		return *runtimeValues
+	}
+	values := runtimeConfig{config: Config}
+	if Config.BaseConfig != nil {
+		values.defaultTimeout = Config.BaseConfig.DefaultTimeout
+	}
+	if Config.BlockHeaderSync != nil {
+		values.headerSyncInterval = Config.BlockHeaderSync.Interval
+		values.headerSyncBatchCount = Config.BlockHeaderSync.BatchCount
+	}
+	if Config.RpcConfig != nil {
+		values.allowList = Config.RpcConfig.AllowList
+		values.denyList = Config.RpcConfig.DenyList
+		values.blackList = Config.RpcConfig.BlackList
+		values.trustedProxies = Config.RpcConfig.TrustedProxies
+		values.trustForwardedFor = Config.RpcConfig.TrustForwardedFor
+	}
+	values.logLevels = make([]string, len(Config.LogConfig))
+	for i, logConfig := range Config.LogConfig {
+		values.logLevels[i] = logConfig.LogLevel
+	}
+	return values
+}
+
+// setRuntime 修改运行时配置，只在持有reloadLock时调用
+//
+//	@param update
+func setRuntime(update func(values *runtimeConfig)) {
+	values := getRuntime()
+	// 日志级别是切片，复制后再修改，不影响读取中的旧值
+	values.logLevels = append([]string{}, values.logLevels...)
+	update(&values)
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	runtimeValues = &values
+}
+
+// effectiveConfig 生效的配置，Config的浅拷贝，运行时重新加载的值替换为当前生效的值，用于和新配置比较
+//
+//	@return *LocalConfig
+func effectiveConfig() *LocalConfig {
+	values := getRuntime()
+	config := *Config
+	if config.BaseConfig != nil {
+		baseConfig := *config.BaseConfig
+		baseConfig.DefaultTimeout = values.defaultTimeout
+		config.BaseConfig = &baseConfig
+	}
+	if config.BlockHeaderSync != nil {
+		blockHeaderSync := *config.BlockHeaderSync
+		blockHeaderSync.Interval = values.headerSyncInterval
+		blockHeaderSync.BatchCount = values.headerSyncBatchCount
+		config.BlockHeaderSync = &blockHeaderSync
+	}
+	if config.RpcConfig != nil {
+		rpcConfig := *config.RpcConfig
+		rpcConfig.AllowList = values.allowList
+		rpcConfig.DenyList = values.denyList
+		rpcConfig.BlackList = values.blackList
+		rpcConfig.TrustedProxies = values.trustedProxies
+		rpcConfig.TrustForwardedFor = values.trustForwardedFor
+		config.RpcConfig = &rpcConfig
+	}
+	config.LogConfig = make([]*logger.LogModuleConfig, len(Config.LogConfig))
+	for i, logConfig := range Config.LogConfig {
+		moduleConfig := *logConfig
+		moduleConfig.LogLevel = values.logLevels[i]
+		config.LogConfig[i] = &moduleConfig
+	}
+	return &config
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeConfig(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir("../.."))
+	defer func() { _ = os.Chdir(wd) }()
+	dir, err := ioutil.TempDir("", "runtime")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	sample, err := ioutil.ReadFile("config/tcip_bcos.yml")
+	assert.Nil(t, err)
+	base := strings.Replace(string(sample), "file_path: logs/default.log", "file_path: "+path.Join(dir, "default.log"), 1)
+	ConfigFilePath = path.Join(dir, "tcip_bcos.yml")
+	assert.Nil(t, ioutil.WriteFile(ConfigFilePath, []byte(base), 0600))
+	Config, err = ReadLocalConfig(ConfigFilePath)
+	assert.Nil(t, err)
+	setLogAbsPath(Config)
+	logger.InitLogConfig(Config.LogConfig)
+
+	// 没有重新加载过时使用Config中的值
+	assert.Equal(t, Config.BaseConfig.DefaultTimeout, DefaultTimeout())
+	assert.Equal(t, Config.BlockHeaderSync.Interval, HeaderSyncInterval())
+	assert.Equal(t, Config.BlockHeaderSync.BatchCount, HeaderSyncBatchCount())
+
+	changed := strings.NewReplacer("default_timeout: 1000", "default_timeout: 30",
+		"batch_count: 1000", "batch_count: 10").Replace(base)
+	assert.Nil(t, ioutil.WriteFile(ConfigFilePath, []byte(changed), 0600))
+
+	// 重新加载时其他协程一直在读取
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = DefaultTimeout()
+				_ = HeaderSyncBatchCount()
+			}
+		}
+	}()
+	result, err := Reload()
+	close(stop)
+	wg.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"base.default_timeout", "block_header_sync.batch_count"}, result.Applied)
+	assert.Equal(t, uint32(30), DefaultTimeout())
+	assert.Equal(t, int64(10), HeaderSyncBatchCount())
+
+	// 和生效的值比较，再次加载同样的配置没有变化
+	result, err = Reload()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(result.Applied))
+	assert.Equal(t, 0, len(result.Restart))
+
+	// Config被整体替换后使用新配置中的值
+	Config, err = ReadLocalConfig(ConfigFilePath)
+	assert.Nil(t, err)
+	Config.BaseConfig.DefaultTimeout = 5
+	assert.Equal(t, uint32(5), DefaultTimeout())
+}
@@ -17,6 +17,12 @@ type LocalConfig struct {
 	DbPath          string                    `mapstructure:"db_path"`
 	ChainConfig     []*ChainConfig            `mapstructure:"chain_config"`
 	BlockHeaderSync *BlockHeaderSyncConfig    `mapstructure:"block_header_sync"`
+	EventSync       *EventSyncConfig          `mapstructure:"event_sync"`
+	Retry           *RetryConfig              `mapstructure:"retry"`
+	Dispatch        *DispatchConfig           `mapstructure:"dispatch"`
+	Health          *HealthConfig             `mapstructure:"health"`
+	TxRecord        *TxRecordConfig           `mapstructure:"tx_record"`
+	CertMonitor     *CertMonitorConfig        `mapstructure:"cert_monitor"`
 	LogConfig       []*logger.LogModuleConfig `mapstructure:"log"` // 日志配置
 }
 
@@ -33,6 +39,47 @@ type BlockHeaderSyncConfig struct {
 	BatchCount int64  `mapstructure:"batch_count"` // 每次更新多少个
 }
 
+// EventSyncConfig 跨链事件配置缓存同步配置
+type EventSyncConfig struct {
+	Interval uint64 `mapstructure:"interval"` // 多久和配置合约对账一次, s
+}
+
+// RetryConfig 调用中继网关失败时的重试配置
+type RetryConfig struct {
+	InitialInterval uint64  `mapstructure:"initial_interval"` // 第一次重试的间隔, ms
+	MaxInterval     uint64  `mapstructure:"max_interval"`     // 重试间隔的上限, ms
+	Multiplier      float64 `mapstructure:"multiplier"`       // 每次重试间隔的增长倍数
+	Jitter          float64 `mapstructure:"jitter"`           // 重试间隔的随机抖动比例, 0~1
+	MaxAttempts     int     `mapstructure:"max_attempts"`     // 最多调用多少次, 0使用默认值20
+	MaxAge          uint64  `mapstructure:"max_age"`          // 第一次调用后最多重试多久, s, 0使用默认值3600
+	RetryableCodes  []int32 `mapstructure:"retryable_codes"`  // 中继网关返回哪些错误码时重试
+}
+
+// DispatchConfig 跨链请求转发配置
+type DispatchConfig struct {
+	Workers    int  `mapstructure:"workers"`     // 每条源链的转发协程数
+	QueueSize  int  `mapstructure:"queue_size"`  // 每条源链的待转发队列长度，队列满时暂停接收事件
+	StrictFifo bool `mapstructure:"strict_fifo"` // 同一个源合约的跨链请求严格按照触发顺序转发
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	Interval     uint64 `mapstructure:"interval"`       // 后台刷新健康状态的间隔, s, 默认10
+	MaxHeaderLag int64  `mapstructure:"max_header_lag"` // 区块头同步落后超过多少个块时报告为未就绪, 0不检查
+	Timeout      uint64 `mapstructure:"timeout"`        // 检查每条链时访问链的超时时间, s, 默认3
+}
+
+// TxRecordConfig 跨链交易记录配置
+type TxRecordConfig struct {
+	Retention uint64 `mapstructure:"retention"` // 保存多少天, 默认30
+}
+
+// CertMonitorConfig 证书有效期监控配置
+type CertMonitorConfig struct {
+	Interval uint64 `mapstructure:"interval"`  // 检查间隔, s, 默认3600
+	WarnDays []int  `mapstructure:"warn_days"` // 距离过期多少天时告警, 默认30、7、1
+}
+
 // BaseConfig 跨链网关基本配置
 type BaseConfig struct {
 	GatewayID   string `mapstructure:"gateway_id"`   // 跨链网关ID，这里需要等待注册以后才能填写
@@ -40,16 +87,97 @@ type BaseConfig struct {
 	// 交易的验证方式，支持spv验证和rpc验证两种方式
 	TxVerifyType   string `mapstructure:"tx_verify_type"`
 	DefaultTimeout uint32 `mapstructure:"default_timeout"` // 默认的全局超时时间
+	// 以下为注册到中继网关的信息，中继网关通过这些信息调用本网关
+	Address           string             `mapstructure:"address"`             // 本网关对中继网关暴露的地址
+	ServerName        string             `mapstructure:"server_name"`         // 本网关证书中的域名
+	Tlsca             string             `mapstructure:"tls_ca"`              // 中继网关访问本网关使用的ca证书路径
+	ClientCert        string             `mapstructure:"client_cert"`         // 中继网关访问本网关使用的客户端证书路径
+	CallType          string             `mapstructure:"call_type"`           // 中继网关调用本网关的方式，grpc/restful
+	ToGatewayList     []string           `mapstructure:"to_gateway_list"`     // 允许跨链到的网关，为空不限制
+	FromGatewayList   []string           `mapstructure:"from_gateway_list"`   // 允许跨链过来的网关，为空不限制
+	TxVerifyInterface *TxVerifyInterface `mapstructure:"tx_verify_interface"` // tx_verify_type为rpc时的交易验证接口
 }
 
 // RpcConfig rpc配置
 type RpcConfig struct {
+	Address        string       `mapstructure:"address"`   // 服务监听的地址，默认0.0.0.0
 	Port           int          `mapstructure:"port"`      // 服务监听的端口号
 	TLSConfig      TlsConfig    `mapstructure:"tls"`       // tls相关配置
-	BlackList      []string     `mapstructure:"blacklist"` // 黑名单
+	BlackList      []string     `mapstructure:"blacklist"` // 黑名单，兼容旧配置，和denylist相同
 	RestfulConfig  RstfulConfig `mapstructure:"restful"`   // resultful api 网关
 	MaxSendMsgSize int          `mapstructure:"max_send_msg_size"`
 	MaxRecvMsgSize int          `mapstructure:"max_recv_msg_size"`
+	Auth           AuthConfig   `mapstructure:"auth"` // 调用方认证和授权
+	// 允许访问的客户端ip或者cidr，为空时不限制
+	AllowList []string `mapstructure:"allowlist"`
+	// 禁止访问的客户端ip或者cidr，优先于allowlist
+	DenyList []string `mapstructure:"denylist"`
+	// restful请求是否使用X-Forwarded-For中的客户端地址
+	TrustForwardedFor bool `mapstructure:"trust_forwarded_for"`
+	// 可信的代理ip或者cidr，只跳过这些代理添加的地址，为空时不信任任何代理
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// 按调用方和接口限流
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	// prometheus指标接口
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	// 运维管理接口
+	Admin AdminConfig `mapstructure:"admin"`
+	// 不使用tls，只用于由sidecar负责加密的部署，此时只能使用token认证
+	Plaintext bool `mapstructure:"plaintext"`
+	// 额外监听的unix socket路径，不使用tls，供本机的运维工具访问，为空时不监听
+	UnixSocket string `mapstructure:"unix_socket"`
+	// 连接保活
+	Keepalive KeepaliveConfig `mapstructure:"keepalive"`
+}
+
+// KeepaliveConfig 连接保活配置，单位都是秒
+type KeepaliveConfig struct {
+	MinTime             int   `mapstructure:"min_time"`              // 客户端ping的最小间隔，更频繁时断开连接，默认2
+	PermitWithoutStream *bool `mapstructure:"permit_without_stream"` // 没有请求时是否允许客户端ping，默认允许
+	Time                int   `mapstructure:"time"`                  // 连接空闲多久后ping客户端，默认5
+	Timeout             int   `mapstructure:"timeout"`               // 等待ping响应的时间，默认1
+	MaxConnectionIdle   int   `mapstructure:"max_connection_idle"`   // 连接没有请求多久后关闭，0表示不关闭
+}
+
+// AdminConfig 运维管理接口配置
+type AdminConfig struct {
+	Enable bool `mapstructure:"enable"` // 是否开启，开启时必须同时开启auth，只有admin角色可以调用
+}
+
+// MetricsConfig prometheus指标接口配置，和rpc服务使用同一个端口
+type MetricsConfig struct {
+	Enable bool   `mapstructure:"enable"` // 是否开启
+	Path   string `mapstructure:"path"`   // 接口路径，默认/metrics
+}
+
+// RateLimitConfig 限流配置，每个调用方的每个接口单独计算
+type RateLimitConfig struct {
+	Enable  bool           `mapstructure:"enable"`  // 是否开启
+	Rate    float64        `mapstructure:"rate"`    // 每秒允许的请求数
+	Burst   int            `mapstructure:"burst"`   // 允许的突发请求数，默认和rate相同
+	Methods []*MethodLimit `mapstructure:"methods"` // 单独限流的接口
+}
+
+// MethodLimit 单个接口的限流配置
+type MethodLimit struct {
+	Method string  `mapstructure:"method"` // 接口名，例如CrossChainTry
+	Rate   float64 `mapstructure:"rate"`   // 每秒允许的请求数
+	Burst  int     `mapstructure:"burst"`  // 允许的突发请求数
+}
+
+// AuthConfig 调用方认证配置
+type AuthConfig struct {
+	Enable     bool            `mapstructure:"enable"`     // 是否开启，开启后只有identities中的调用方可以访问
+	Identities []*AuthIdentity `mapstructure:"identities"` // 允许访问的调用方
+}
+
+// AuthIdentity 调用方身份，证书和token都配置时需要同时满足
+type AuthIdentity struct {
+	Name         string   `mapstructure:"name"`         // 名称，用于日志
+	Role         string   `mapstructure:"role"`         // 角色，relay/admin
+	Subjects     []string `mapstructure:"subjects"`     // 允许的证书subject或者CN
+	Fingerprints []string `mapstructure:"fingerprints"` // 允许的证书sha256指纹，十六进制
+	Tokens       []string `mapstructure:"tokens"`       // 允许的x-token
 }
 
 // TlsConfig tls配置
@@ -58,6 +186,8 @@ type TlsConfig struct {
 	KeyFile    string `mapstructure:"key_file"`
 	CertFile   string `mapstructure:"cert_file"`
 	ServerName string `mapstructure:"server_name"`
+	// KeyPassword key_file是openssl加密的pem时的密码
+	KeyPassword string `mapstructure:"key_password"`
 }
 
 // RstfulConfig rest服务配置
@@ -68,13 +198,34 @@ type RstfulConfig struct {
 
 // Relay 中继网关配置
 type Relay struct {
-	AccessCode string `mapstructure:"access_code"` // 授权码
+	AccessCode string `mapstructure:"access_code"` // 授权码，可以使用file:或者env:引用
 	Address    string `mapstructure:"address"`     // 中继网关地址
 	ServerName string `mapstructure:"server_name"` // 中继网关的server name
 	Tlsca      string `mapstructure:"tls_ca"`      // 中继网关的ca证书路径
 	ClientCert string `mapstructure:"client_cert"` // 中继网关的客户端证书路径
 	ClientKey  string `mapstructure:"client_key"`  // 中继网关的客户端私钥
 	CallType   string `mapstructure:"call_type"`   // 调用类型
+	// 客户端私钥是加密的pem时的密码，可以使用file:或者env:引用
+	ClientKeyPassword string `mapstructure:"client_key_password"`
+	// 到中继网关的grpc长连接数，默认1
+	ConnPoolSize int `mapstructure:"conn_pool_size"`
+	// 网关被禁用后探测是否恢复的间隔, s, 默认60
+	DisabledProbeInterval uint64 `mapstructure:"disabled_probe_interval"`
+	// 多个中继网关实例，为空时使用address
+	Endpoints []*RelayEndpoint `mapstructure:"endpoints"`
+	// 实例选择方式，priority/round_robin，默认priority
+	Selection string `mapstructure:"selection"`
+	// 实例连续失败多少次后熔断，默认3
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// 熔断多久之后探测实例是否恢复, s, 默认30
+	OpenTimeout uint64 `mapstructure:"open_timeout"`
+}
+
+// RelayEndpoint 中继网关实例
+type RelayEndpoint struct {
+	Address    string `mapstructure:"address"`     // 实例地址
+	ServerName string `mapstructure:"server_name"` // 实例证书中的域名，为空时使用relay.server_name
+	Priority   int    `mapstructure:"priority"`    // 优先级，数字越小越优先
 }
 
 // TxVerifyInterface 交易验证接口配置
@@ -0,0 +1,425 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+)
+
+const (
+	// RoleRelay 中继网关，可以调用跨链接口
+	RoleRelay = "relay"
+	// RoleAdmin 管理员，可以管理跨链事件
+	RoleAdmin = "admin"
+
+	// PrioritySelection 优先使用优先级高的中继网关实例
+	PrioritySelection = "priority"
+	// RoundRobinSelection 轮流使用全部可用的中继网关实例
+	RoundRobinSelection = "round_robin"
+)
+
+// Problem 配置中的一个问题
+type Problem struct {
+	// Path yaml中的路径，例如chain_config[0].sdk_config_path
+	Path    string
+	Message string
+}
+
+// ValidationError 配置检查发现的全部问题
+type ValidationError struct {
+	Problems []*Problem
+}
+
+// Error 每个问题一行
+//
+//	@receiver e
+//	@return string
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("config check found %d problems:", len(e.Problems)))
+	for _, problem := range e.Problems {
+		lines = append(lines, fmt.Sprintf("  %s: %s", problem.Path, problem.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate 检查配置的每个部分，一次返回全部问题，没有问题时返回nil
+//
+//	@param config
+//	@return error *ValidationError
+func Validate(config *LocalConfig) error {
+	c := &checker{}
+	c.checkBase(config.BaseConfig)
+	c.checkRpc(config.RpcConfig)
+	c.checkRelay(config.Relay)
+	c.required("db_path", config.DbPath)
+	c.checkChains(config.ChainConfig)
+	c.checkBlockHeaderSync(config)
+	c.checkRetry(config.Retry)
+	if config.Dispatch != nil {
+		c.nonNegative("dispatch.workers", int64(config.Dispatch.Workers))
+		c.nonNegative("dispatch.queue_size", int64(config.Dispatch.QueueSize))
+	}
+	if config.Health != nil {
+		c.nonNegative("health.max_header_lag", config.Health.MaxHeaderLag)
+	}
+	if config.CertMonitor != nil {
+		for i, days := range config.CertMonitor.WarnDays {
+			c.positive(fmt.Sprintf("cert_monitor.warn_days[%d]", i), int64(days))
+		}
+	}
+	for i, log := range config.LogConfig {
+		path := fmt.Sprintf("log[%d]", i)
+		c.required(path+".module", log.ModuleName)
+		c.oneOf(path+".log_level", log.LogLevel, "", logger.DEBUG, logger.INFO, logger.WARN, logger.ERROR)
+	}
+	if len(c.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: c.problems}
+}
+
+// checker 收集配置中的问题
+type checker struct {
+	problems []*Problem
+}
+
+// add 记录一个问题
+//
+//	@receiver c
+//	@param path
+//	@param format
+//	@param args
+func (c *checker) add(path, format string, args ...interface{}) {
+	c.problems = append(c.problems, &Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// required 字符串不能为空
+//
+//	@receiver c
+//	@param path
+//	@param value
+//	@return bool 是否有值
+func (c *checker) required(path, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		c.add(path, "is required")
+		return false
+	}
+	return true
+}
+
+// oneOf 取值必须是values中的一个
+//
+//	@receiver c
+//	@param path
+//	@param value
+//	@param values
+func (c *checker) oneOf(path, value string, values ...string) {
+	for _, v := range values {
+		if value == v {
+			return
+		}
+	}
+	c.add(path, "unknown value %q, must be one of %s", value, strings.Join(values, "/"))
+}
+
+// positive 数值必须大于0
+//
+//	@receiver c
+//	@param path
+//	@param value
+func (c *checker) positive(path string, value int64) {
+	if value <= 0 {
+		c.add(path, "must be greater than 0, got %d", value)
+	}
+}
+
+// nonNegative 数值不能小于0
+//
+//	@receiver c
+//	@param path
+//	@param value
+func (c *checker) nonNegative(path string, value int64) {
+	if value < 0 {
+		c.add(path, "can not be negative, got %d", value)
+	}
+}
+
+// readable 文件必须配置并且可以读取
+//
+//	@receiver c
+//	@param path
+//	@param file
+func (c *checker) readable(path, file string) {
+	if !c.required(path, file) {
+		return
+	}
+	c.readableIfSet(path, file)
+}
+
+// readableIfSet 配置了文件时必须可以读取
+//
+//	@receiver c
+//	@param path
+//	@param file
+func (c *checker) readableIfSet(path, file string) {
+	if file == "" {
+		return
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		c.add(path, "can not read %s: %s", file, err.Error())
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		c.add(path, "can not read %s: %s", file, err.Error())
+		return
+	}
+	if info.IsDir() {
+		c.add(path, "%s is a directory", file)
+	}
+}
+
+// nets 每一项都必须是ip或者cidr
+//
+//	@receiver c
+//	@param path
+//	@param items
+func (c *checker) nets(path string, items []string) {
+	for i, item := range items {
+		item = strings.TrimSpace(item)
+		if strings.Contains(item, "/") {
+			if _, _, err := net.ParseCIDR(item); err != nil {
+				c.add(fmt.Sprintf("%s[%d]", path, i), "invalid cidr %q", item)
+			}
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			c.add(fmt.Sprintf("%s[%d]", path, i), "invalid ip %q", item)
+		}
+	}
+}
+
+// checkBase 检查网关基本配置
+//
+//	@receiver c
+//	@param base
+func (c *checker) checkBase(base *BaseConfig) {
+	if base == nil {
+		c.add("base", "section is required")
+		return
+	}
+	c.required("base.gateway_name", base.GatewayName)
+	if c.required("base.tx_verify_type", base.TxVerifyType) {
+		c.oneOf("base.tx_verify_type", base.TxVerifyType, SpvTxVerify, RpcTxVerify, NotNeedTxVerify)
+	}
+	c.positive("base.default_timeout", int64(base.DefaultTimeout))
+	c.oneOf("base.call_type", base.CallType, "", GrpcCallType, RestCallType)
+	c.readableIfSet("base.tls_ca", base.Tlsca)
+	c.readableIfSet("base.client_cert", base.ClientCert)
+	if base.TxVerifyType != RpcTxVerify {
+		return
+	}
+	if base.TxVerifyInterface == nil {
+		c.add("base.tx_verify_interface", "is required when tx_verify_type is %s", RpcTxVerify)
+		return
+	}
+	c.required("base.tx_verify_interface.address", base.TxVerifyInterface.Address)
+	if base.TxVerifyInterface.TlsEnable {
+		c.readable("base.tx_verify_interface.tls_ca", base.TxVerifyInterface.Tlsca)
+		c.readableIfSet("base.tx_verify_interface.client_cert", base.TxVerifyInterface.ClientCert)
+	}
+}
+
+// checkRpc 检查rpc服务配置
+//
+//	@receiver c
+//	@param rpc
+func (c *checker) checkRpc(rpc *RpcConfig) {
+	if rpc == nil {
+		c.add("rpc", "section is required")
+		return
+	}
+	if rpc.Port <= 0 || rpc.Port > 65535 {
+		c.add("rpc.port", "must be between 1 and 65535, got %d", rpc.Port)
+	}
+	if !rpc.Plaintext {
+		c.readable("rpc.tls.ca_file", rpc.TLSConfig.CaFile)
+		c.readable("rpc.tls.cert_file", rpc.TLSConfig.CertFile)
+		c.readable("rpc.tls.key_file", rpc.TLSConfig.KeyFile)
+	}
+	if rpc.TLSConfig.KeyPassword != "" && rpc.TLSConfig.KeyFile == "" {
+		c.add("rpc.tls.key_password", "requires key_file")
+	}
+	if rpc.UnixSocket != "" {
+		if info, err := os.Stat(filepath.Dir(rpc.UnixSocket)); err != nil || !info.IsDir() {
+			c.add("rpc.unix_socket", "directory of %s does not exist", rpc.UnixSocket)
+		}
+	}
+	c.positive("rpc.max_send_msg_size", int64(rpc.MaxSendMsgSize))
+	c.positive("rpc.max_recv_msg_size", int64(rpc.MaxRecvMsgSize))
+	if rpc.RestfulConfig.Enable {
+		c.positive("rpc.restful.max_resp_body_size", int64(rpc.RestfulConfig.MaxRespBodySize))
+	}
+	c.nets("rpc.allowlist", rpc.AllowList)
+	c.nets("rpc.denylist", rpc.DenyList)
+	c.nets("rpc.blacklist", rpc.BlackList)
+	c.nets("rpc.trusted_proxies", rpc.TrustedProxies)
+	if rpc.TrustForwardedFor && len(rpc.TrustedProxies) == 0 {
+		c.add("rpc.trust_forwarded_for", "requires rpc.trusted_proxies, otherwise any client can spoof X-Forwarded-For")
+	}
+	if rpc.RateLimit.Enable {
+		if rpc.RateLimit.Rate <= 0 {
+			c.add("rpc.rate_limit.rate", "must be greater than 0, got %v", rpc.RateLimit.Rate)
+		}
+		c.nonNegative("rpc.rate_limit.burst", int64(rpc.RateLimit.Burst))
+		for i, method := range rpc.RateLimit.Methods {
+			path := fmt.Sprintf("rpc.rate_limit.methods[%d]", i)
+			c.required(path+".method", method.Method)
+			if method.Rate <= 0 {
+				c.add(path+".rate", "must be greater than 0, got %v", method.Rate)
+			}
+			c.nonNegative(path+".burst", int64(method.Burst))
+		}
+	}
+	if rpc.Metrics.Enable && rpc.Metrics.Path != "" && !strings.HasPrefix(rpc.Metrics.Path, "/") {
+		c.add("rpc.metrics.path", "must start with /, got %q", rpc.Metrics.Path)
+	}
+	if rpc.Admin.Enable && !rpc.Auth.Enable {
+		c.add("rpc.admin.enable", "requires rpc.auth.enable")
+	}
+	c.checkAuth(&rpc.Auth)
+	c.nonNegative("rpc.keepalive.min_time", int64(rpc.Keepalive.MinTime))
+	c.nonNegative("rpc.keepalive.time", int64(rpc.Keepalive.Time))
+	c.nonNegative("rpc.keepalive.timeout", int64(rpc.Keepalive.Timeout))
+	c.nonNegative("rpc.keepalive.max_connection_idle", int64(rpc.Keepalive.MaxConnectionIdle))
+}
+
+// checkAuth 开启认证时每个身份都需要有合法的角色和认证方式
+//
+//	@receiver c
+//	@param auth
+func (c *checker) checkAuth(auth *AuthConfig) {
+	if !auth.Enable {
+		return
+	}
+	if len(auth.Identities) == 0 {
+		c.add("rpc.auth.identities", "at least one identity is required when auth is enabled")
+	}
+	for i, identity := range auth.Identities {
+		path := fmt.Sprintf("rpc.auth.identities[%d]", i)
+		c.oneOf(path+".role", identity.Role, RoleRelay, RoleAdmin)
+		if len(identity.Subjects) == 0 && len(identity.Fingerprints) == 0 && len(identity.Tokens) == 0 {
+			c.add(path, "one of subjects, fingerprints or tokens is required")
+		}
+	}
+}
+
+// checkRelay 检查中继网关配置
+//
+//	@receiver c
+//	@param relay
+func (c *checker) checkRelay(relay *Relay) {
+	if relay == nil {
+		c.add("relay", "section is required")
+		return
+	}
+	c.required("relay.access_code", relay.AccessCode)
+	c.oneOf("relay.call_type", relay.CallType, GrpcCallType, RestCallType)
+	if len(relay.Endpoints) == 0 {
+		c.required("relay.address", relay.Address)
+	}
+	for i, endpoint := range relay.Endpoints {
+		c.required(fmt.Sprintf("relay.endpoints[%d].address", i), endpoint.Address)
+	}
+	c.oneOf("relay.selection", relay.Selection, "", PrioritySelection, RoundRobinSelection)
+	c.nonNegative("relay.conn_pool_size", int64(relay.ConnPoolSize))
+	c.nonNegative("relay.failure_threshold", int64(relay.FailureThreshold))
+	if relay.ClientKeyPassword != "" && relay.ClientKey == "" {
+		c.add("relay.client_key_password", "requires client_key")
+	}
+	if relay.CallType == GrpcCallType {
+		c.readable("relay.tls_ca", relay.Tlsca)
+		c.readable("relay.client_cert", relay.ClientCert)
+		c.readable("relay.client_key", relay.ClientKey)
+		return
+	}
+	c.readableIfSet("relay.tls_ca", relay.Tlsca)
+	c.readableIfSet("relay.client_cert", relay.ClientCert)
+	c.readableIfSet("relay.client_key", relay.ClientKey)
+	if (relay.ClientCert == "") != (relay.ClientKey == "") {
+		c.add("relay.client_key", "client_cert and client_key must be configured together")
+	}
+}
+
+// checkChains 检查链配置，chain_rid不能重复
+//
+//	@receiver c
+//	@param chains
+func (c *checker) checkChains(chains []*ChainConfig) {
+	if len(chains) == 0 {
+		c.add("chain_config", "at least one chain is required")
+		return
+	}
+	seen := make(map[string]int)
+	for i, chain := range chains {
+		path := fmt.Sprintf("chain_config[%d]", i)
+		if c.required(path+".chain_rid", chain.ChainRid) {
+			if first, ok := seen[chain.ChainRid]; ok {
+				c.add(path+".chain_rid", "duplicate chain_rid %s, already used by chain_config[%d]",
+					chain.ChainRid, first)
+			} else {
+				seen[chain.ChainRid] = i
+			}
+		}
+		c.readable(path+".sdk_config_path", chain.SdkConfigPath)
+		c.required(path+".cross_contract_name", chain.CrossContractName)
+	}
+}
+
+// checkBlockHeaderSync spv验证时必须配置区块头同步
+//
+//	@receiver c
+//	@param config
+func (c *checker) checkBlockHeaderSync(config *LocalConfig) {
+	if config.BlockHeaderSync == nil {
+		if config.BaseConfig != nil && config.BaseConfig.TxVerifyType == SpvTxVerify {
+			c.add("block_header_sync", "section is required when tx_verify_type is %s", SpvTxVerify)
+		}
+		return
+	}
+	c.positive("block_header_sync.interval", int64(config.BlockHeaderSync.Interval))
+	c.positive("block_header_sync.batch_count", config.BlockHeaderSync.BatchCount)
+}
+
+// checkRetry 检查重试策略
+//
+//	@receiver c
+//	@param retry
+func (c *checker) checkRetry(retry *RetryConfig) {
+	if retry == nil {
+		return
+	}
+	if retry.MaxInterval != 0 && retry.MaxInterval < retry.InitialInterval {
+		c.add("retry.max_interval", "must not be less than initial_interval %d, got %d",
+			retry.InitialInterval, retry.MaxInterval)
+	}
+	if retry.Multiplier != 0 && retry.Multiplier < 1 {
+		c.add("retry.multiplier", "must not be less than 1, got %v", retry.Multiplier)
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		c.add("retry.jitter", "must be between 0 and 1, got %v", retry.Jitter)
+	}
+	c.nonNegative("retry.max_attempts", int64(retry.MaxAttempts))
+}
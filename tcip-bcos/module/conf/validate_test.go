@@ -0,0 +1,101 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	// 示例配置中的文件路径相对于项目根目录
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir("../.."))
+	defer func() { _ = os.Chdir(wd) }()
+
+	tests := []struct {
+		name      string
+		modify    func(config *LocalConfig)
+		wantPaths []string
+	}{
+		{
+			name:   "sample config",
+			modify: func(config *LocalConfig) {},
+		},
+		{
+			name:      "missing relay",
+			modify:    func(config *LocalConfig) { config.Relay = nil },
+			wantPaths: []string{"relay"},
+		},
+		{
+			name:      "zero batch count",
+			modify:    func(config *LocalConfig) { config.BlockHeaderSync.BatchCount = 0 },
+			wantPaths: []string{"block_header_sync.batch_count"},
+		},
+		{
+			name:      "unknown tx verify type",
+			modify:    func(config *LocalConfig) { config.BaseConfig.TxVerifyType = "foo" },
+			wantPaths: []string{"base.tx_verify_type"},
+		},
+		{
+			name: "duplicate chain rid and missing sdk config",
+			modify: func(config *LocalConfig) {
+				chain := *config.ChainConfig[0]
+				chain.SdkConfigPath = "./config/missing.toml"
+				config.ChainConfig = append(config.ChainConfig, &chain)
+			},
+			wantPaths: []string{"chain_config[1].chain_rid", "chain_config[1].sdk_config_path"},
+		},
+		{
+			name: "trust forwarded for without trusted proxies",
+			modify: func(config *LocalConfig) {
+				config.RpcConfig.TrustForwardedFor = true
+				config.RpcConfig.TrustedProxies = nil
+			},
+			wantPaths: []string{"rpc.trust_forwarded_for"},
+		},
+		{
+			name: "key password without key file",
+			modify: func(config *LocalConfig) {
+				config.RpcConfig.Plaintext = true
+				config.RpcConfig.TLSConfig.KeyFile = ""
+				config.RpcConfig.TLSConfig.KeyPassword = "password"
+			},
+			wantPaths: []string{"rpc.tls.key_password"},
+		},
+		{
+			name: "all problems at once",
+			modify: func(config *LocalConfig) {
+				config.RpcConfig.Port = 0
+				config.RpcConfig.BlackList = []string{"1.2.3"}
+				config.LogConfig[0].LogLevel = "info"
+			},
+			wantPaths: []string{"rpc.port", "rpc.blacklist[0]", "log[0].log_level"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ReadLocalConfig("config/tcip_bcos.yml")
+			assert.Nil(t, err)
+			tt.modify(config)
+			err = Validate(config)
+			if len(tt.wantPaths) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			assert.True(t, ok)
+			paths := make([]string, 0, len(validationErr.Problems))
+			for _, problem := range validationErr.Problems {
+				paths = append(paths, problem.Path)
+			}
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 
@@ -18,6 +19,7 @@ import (
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
 	"github.com/syndtr/goleveldb/leveldb"
 	"go.uber.org/zap"
 )
@@ -53,6 +55,7 @@ func NewDbHandle() {
 //  @return []byte
 //  @return error
 func (d *DbHandle) Get(key []byte) ([]byte, error) {
+	defer metrics.ObserveSince(metrics.DbDuration.WithLabelValues("get"), time.Now())
 	value, err := d.db.Get(key, nil)
 	if err == leveldb.ErrNotFound {
 		value = nil
@@ -73,6 +76,7 @@ func (d *DbHandle) Get(key []byte) ([]byte, error) {
 //  @param value
 //  @return error
 func (d *DbHandle) Put(key []byte, value []byte) error {
+	defer metrics.ObserveSince(metrics.DbDuration.WithLabelValues("put"), time.Now())
 	msg := fmt.Sprintf("[Put] writing leveldbprovider key [%#v] with nil value", key)
 	if value == nil {
 		d.log.Warn(msg)
@@ -94,6 +98,7 @@ func (d *DbHandle) Put(key []byte, value []byte) error {
 //  @return bool
 //  @return error
 func (d *DbHandle) Has(key []byte) (bool, error) {
+	defer metrics.ObserveSince(metrics.DbDuration.WithLabelValues("has"), time.Now())
 	exist, err := d.db.Has(key, nil)
 	if err != nil {
 		d.log.Errorf("getting leveldbprovider key [%#v], err:%s", key, err.Error())
@@ -107,6 +112,7 @@ func (d *DbHandle) Has(key []byte) (bool, error) {
 //  @param key
 //  @return error
 func (d *DbHandle) Delete(key []byte) error {
+	defer metrics.ObserveSince(metrics.DbDuration.WithLabelValues("delete"), time.Now())
 	wo := &opt.WriteOptions{Sync: true}
 	err := d.db.Delete(key, wo)
 	if err != nil {
@@ -0,0 +1,120 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+)
+
+const (
+	// KindBeginCrossChain 跨链请求
+	KindBeginCrossChain = "begin_cross_chain"
+	// KindSyncBlockHeader 区块头同步请求，只有旧版本会放入，区块头同步失败后由下一轮同步重试
+	KindSyncBlockHeader = "sync_block_header"
+	// KindCrossChainEvent 重试耗尽仍然没有加载到跨链事件配置的跨链事件，Request是序列化后的事件
+	KindCrossChainEvent = "cross_chain_event"
+
+	// keyFormat 死信的key: deadletter#id
+	keyFormat = "deadletter#%s"
+	// keyPrefix 全部死信的前缀
+	keyPrefix = "deadletter#"
+	// keyLimit 全部死信迭代的上界，'$'是'#'的下一个字符
+	keyLimit = "deadletter$"
+)
+
+// Letter 重试耗尽或者遇到不可重试错误的中继网关请求
+type Letter struct {
+	Id          string `json:"id"`
+	Kind        string `json:"kind"`
+	ChainRid    string `json:"chain_rid"`
+	TxId        string `json:"tx_id,omitempty"`
+	BlockHeight int64  `json:"block_height"`
+	// Request 序列化后的请求，重新发送时使用
+	Request   []byte `json:"request"`
+	Error     string `json:"error"`
+	Attempts  int    `json:"attempts"`
+	FirstTime int64  `json:"first_time"`
+	LastTime  int64  `json:"last_time"`
+}
+
+// Put 保存死信，Id为空时生成新的Id，按照时间排序
+//
+//	@param letter
+//	@return error
+func Put(letter *Letter) error {
+	if letter.Id == "" {
+		letter.Id = fmt.Sprintf("%d-%s", time.Now().UnixNano(), letter.Kind)
+	}
+	letterByte, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("marshal dead letter error: %s", err.Error())
+	}
+	return db.Db.Put(letterKey(letter.Id), letterByte)
+}
+
+// Get 获取死信，不存在返回nil
+//
+//	@param id
+//	@return *Letter
+//	@return error
+func Get(id string) (*Letter, error) {
+	letterByte, err := db.Db.Get(letterKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(letterByte) == 0 {
+		return nil, nil
+	}
+	letter := &Letter{}
+	if err = json.Unmarshal(letterByte, letter); err != nil {
+		return nil, fmt.Errorf("unmarshal dead letter error: %s", err.Error())
+	}
+	return letter, nil
+}
+
+// List 列出全部死信
+//
+//	@return []*Letter
+//	@return error
+func List() ([]*Letter, error) {
+	iter, err := db.Db.NewIteratorWithRange([]byte(keyPrefix), []byte(keyLimit))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+	letters := make([]*Letter, 0)
+	for iter.Next() {
+		letter := &Letter{}
+		if err = json.Unmarshal(iter.Value(), letter); err != nil {
+			return nil, fmt.Errorf("unmarshal dead letter error: %s", err.Error())
+		}
+		letters = append(letters, letter)
+	}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
+
+// Delete 删除死信
+//
+//	@param id
+//	@return error
+func Delete(id string) error {
+	return db.Db.Delete(letterKey(id))
+}
+
+// letterKey 死信的key
+//
+//	@param id
+//	@return []byte
+func letterKey(id string) []byte {
+	return []byte(fmt.Sprintf(keyFormat, id))
+}
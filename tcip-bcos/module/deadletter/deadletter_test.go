@@ -0,0 +1,56 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	logger.InitLogConfig(log)
+	db.NewDbHandle()
+}
+
+func TestPutGetListDelete(t *testing.T) {
+	initTest()
+	first := &Letter{Kind: KindBeginCrossChain, ChainRid: "chain1", Request: []byte("req1"), Attempts: 3}
+	assert.Nil(t, Put(first))
+	assert.NotEmpty(t, first.Id)
+	second := &Letter{Kind: KindSyncBlockHeader, ChainRid: "chain1", Request: []byte("req2")}
+	assert.Nil(t, Put(second))
+
+	got, err := Get(first.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, first, got)
+
+	letters, err := List()
+	assert.Nil(t, err)
+	assert.Equal(t, []*Letter{first, second}, letters)
+
+	assert.Nil(t, Delete(first.Id))
+	got, err = Get(first.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+	letters, err = List()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(letters))
+}
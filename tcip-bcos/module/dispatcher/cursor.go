@@ -0,0 +1,95 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import "sync"
+
+// Cursor 多个协程并发转发时的事件游标，后面的事件可能先转发完成，
+// 游标只推进到之前的事件全部转发完成的高度，重启后从游标高度开始订阅不会漏掉事件
+type Cursor struct {
+	lock   sync.Mutex
+	chains map[string]*chainCursor
+}
+
+// chainCursor 一条源链的事件游标
+type chainCursor struct {
+	// pending 每个高度还没有转发完成的事件数
+	pending map[int64]int
+	// finished 转发完成的事件的最大高度
+	finished int64
+	// saved 已经保存的游标高度
+	saved int64
+}
+
+// NewCursor 新建事件游标
+//
+//	@return *Cursor
+func NewCursor() *Cursor {
+	return &Cursor{
+		chains: make(map[string]*chainCursor),
+	}
+}
+
+// Begin 事件放入转发队列前调用，事件按照区块高度的顺序到达
+//
+//	@receiver c
+//	@param chainRid
+//	@param height 事件所在的区块高度
+func (c *Cursor) Begin(chainRid string, height int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	chain, ok := c.chains[chainRid]
+	if !ok {
+		chain = &chainCursor{pending: make(map[int64]int), finished: -1, saved: -1}
+		c.chains[chainRid] = chain
+	}
+	chain.pending[height]++
+}
+
+// Done 事件转发完成后调用，游标推进时在锁内调用save保存新的游标，保证保存的游标不会回退
+//
+//	@receiver c
+//	@param chainRid
+//	@param height 事件所在的区块高度
+//	@param save
+func (c *Cursor) Done(chainRid string, height int64, save func(height int64)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	chain, ok := c.chains[chainRid]
+	if !ok || chain.pending[height] == 0 {
+		return
+	}
+	chain.pending[height]--
+	if chain.pending[height] == 0 {
+		delete(chain.pending, height)
+	}
+	if height > chain.finished {
+		chain.finished = height
+	}
+	cursor := chain.cursor()
+	if cursor > chain.saved {
+		chain.saved = cursor
+		save(cursor)
+	}
+}
+
+// cursor 可以保存的游标：有未完成的事件时是其中最小的高度，这个高度之前的事件都已经转发完成，
+// 重启后从这个高度开始订阅；没有未完成的事件时是转发完成的最大高度
+//
+//	@receiver c
+//	@return int64
+func (c *chainCursor) cursor() int64 {
+	if len(c.pending) == 0 {
+		return c.finished
+	}
+	cursor := int64(-1)
+	for height := range c.pending {
+		if cursor < 0 || height < cursor {
+			cursor = height
+		}
+	}
+	return cursor
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Done(t *testing.T) {
+	tests := []struct {
+		name  string
+		begin []int64
+		done  []int64
+		want  []int64
+	}{
+		{
+			name:  "in order",
+			begin: []int64{1, 2, 3},
+			done:  []int64{1, 2, 3},
+			want:  []int64{2, 3},
+		},
+		{
+			name:  "later finished first",
+			begin: []int64{1, 2, 3},
+			done:  []int64{3, 2, 1},
+			want:  []int64{1, 3},
+		},
+		{
+			name:  "same block",
+			begin: []int64{5, 5, 7},
+			done:  []int64{5, 7, 5},
+			want:  []int64{5, 7},
+		},
+		{
+			name:  "slow event holds the cursor",
+			begin: []int64{1, 4, 6, 9},
+			done:  []int64{1, 6, 9},
+			want:  []int64{4},
+		},
+		{
+			name:  "unknown height",
+			begin: []int64{1},
+			done:  []int64{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCursor()
+			for _, height := range tt.begin {
+				c.Begin("chain1", height)
+			}
+			var saved []int64
+			for _, height := range tt.done {
+				c.Done("chain1", height, func(height int64) {
+					saved = append(saved, height)
+				})
+			}
+			assert.Equal(t, tt.want, saved)
+		})
+	}
+}
+
+func TestCursor_Concurrent(t *testing.T) {
+	c := NewCursor()
+	for height := int64(0); height < 100; height++ {
+		c.Begin("chain1", height)
+	}
+	var (
+		wg    sync.WaitGroup
+		saved []int64
+	)
+	for height := int64(99); height >= 0; height-- {
+		wg.Add(1)
+		go func(height int64) {
+			defer wg.Done()
+			c.Done("chain1", height, func(height int64) {
+				saved = append(saved, height)
+			})
+		}(height)
+	}
+	wg.Wait()
+	// 保存的游标单调递增，全部完成后是最大的高度
+	for i := 1; i < len(saved); i++ {
+		assert.True(t, saved[i] > saved[i-1])
+	}
+	assert.Equal(t, int64(99), saved[len(saved)-1])
+}
@@ -0,0 +1,153 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWorkers 每条链默认的转发协程数
+	defaultWorkers = 4
+	// defaultQueueSize 每条链默认的待转发队列长度
+	defaultQueueSize = 1000
+)
+
+// Dispatcher 按照源链转发跨链请求，每条链固定数量的协程和有界队列，队列满时阻塞调用方
+type Dispatcher struct {
+	log        *zap.SugaredLogger
+	workers    int
+	queueSize  int
+	strictFifo bool
+	lock       sync.Mutex
+	chains     map[string][]chan func()
+}
+
+// NewDispatcher 根据配置新建转发器，没有配置的项使用默认值
+//
+//	@param log
+//	@param config
+//	@return *Dispatcher
+func NewDispatcher(log *zap.SugaredLogger, config *conf.DispatchConfig) *Dispatcher {
+	d := &Dispatcher{
+		log:       log,
+		workers:   defaultWorkers,
+		queueSize: defaultQueueSize,
+		chains:    make(map[string][]chan func()),
+	}
+	if config == nil {
+		return d
+	}
+	if config.Workers > 0 {
+		d.workers = config.Workers
+	}
+	if config.QueueSize > 0 {
+		d.queueSize = config.QueueSize
+	}
+	d.strictFifo = config.StrictFifo
+	return d
+}
+
+// Dispatch 把任务放入源链的队列，strict_fifo时相同key的任务由同一个协程按顺序执行
+//
+//	@receiver d
+//	@param chainRid 源链资源id
+//	@param key 顺序相关的key，一般是源合约
+//	@param job
+func (d *Dispatcher) Dispatch(chainRid, key string, job func()) {
+	queue := d.getQueue(chainRid, key)
+	depth := metrics.RelayQueueDepth.WithLabelValues(chainRid)
+	depth.Inc()
+	queued := func() {
+		depth.Dec()
+		job()
+	}
+	select {
+	case queue <- queued:
+	default:
+		d.log.Warnf("[Dispatch] queue of chain %s is full, wait for workers: key %s", chainRid, key)
+		queue <- queued
+	}
+}
+
+// Depth 每条源链队列中等待转发的任务数
+//
+//	@receiver d
+//	@return map[string]int
+func (d *Dispatcher) Depth() map[string]int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	depth := make(map[string]int, len(d.chains))
+	for chainRid, queues := range d.chains {
+		for _, queue := range queues {
+			depth[chainRid] += len(queue)
+		}
+	}
+	return depth
+}
+
+// getQueue 获取任务所在的队列，第一次使用时启动源链的转发协程
+//
+//	@receiver d
+//	@param chainRid
+//	@param key
+//	@return chan func()
+func (d *Dispatcher) getQueue(chainRid, key string) chan func() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	queues, ok := d.chains[chainRid]
+	if !ok {
+		queues = d.startChain(chainRid)
+		d.chains[chainRid] = queues
+	}
+	if len(queues) == 1 {
+		return queues[0]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return queues[h.Sum32()%uint32(len(queues))]
+}
+
+// startChain 启动源链的转发协程，strict_fifo时每个协程一个队列，否则全部协程共用一个队列
+//
+//	@receiver d
+//	@param chainRid
+//	@return []chan func()
+func (d *Dispatcher) startChain(chainRid string) []chan func() {
+	d.log.Infof("[startChain] start %d workers for chain %s, queue size %d, strict fifo %v",
+		d.workers, chainRid, d.queueSize, d.strictFifo)
+	if !d.strictFifo {
+		queue := make(chan func(), d.queueSize)
+		for i := 0; i < d.workers; i++ {
+			go work(queue)
+		}
+		return []chan func(){queue}
+	}
+	size := d.queueSize / d.workers
+	if size == 0 {
+		size = 1
+	}
+	queues := make([]chan func(), d.workers)
+	for i := range queues {
+		queues[i] = make(chan func(), size)
+		go work(queues[i])
+	}
+	return queues
+}
+
+// work 按顺序执行队列中的任务
+//
+//	@param queue
+func work(queue chan func()) {
+	for job := range queue {
+		job()
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	logger.InitLogConfig(log)
+}
+
+func TestDispatcher_StrictFifo(t *testing.T) {
+	initTest()
+	d := NewDispatcher(logger.GetLogger(logger.ModuleRequest),
+		&conf.DispatchConfig{Workers: 4, QueueSize: 8, StrictFifo: true})
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+		got  = make(map[string][]int)
+	)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("contract%d", i%5)
+		index := i
+		wg.Add(1)
+		d.Dispatch("chain1", key, func() {
+			defer wg.Done()
+			lock.Lock()
+			defer lock.Unlock()
+			got[key] = append(got[key], index)
+		})
+	}
+	wg.Wait()
+	for key, indexes := range got {
+		assert.Equal(t, 20, len(indexes))
+		for i := 1; i < len(indexes); i++ {
+			assert.True(t, indexes[i-1] < indexes[i], "%s not in order: %v", key, indexes)
+		}
+	}
+}
+
+func TestDispatcher_Bounded(t *testing.T) {
+	initTest()
+	d := NewDispatcher(logger.GetLogger(logger.ModuleRequest), &conf.DispatchConfig{Workers: 2, QueueSize: 2})
+	var (
+		running int32
+		maxRun  int32
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go d.Dispatch("chain1", "", func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRun)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRun, m, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&running))
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRun))
+	assert.Equal(t, 1, len(d.chains))
+}
@@ -6,15 +6,23 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
-	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
-	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/gogo/protobuf/proto"
+	"strings"
+	"sync"
+	"time"
 
-	//tbis_event "chainmaker.org/chainmaker/tcip-chainmaker/v2/module/event"
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/eventcache"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
+	"github.com/gogo/protobuf/proto"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
@@ -25,11 +33,52 @@ import (
 const (
 	eventKey    = "event"
 	maxParamLen = 11
+	// eventIndexPrefixFormat 跨链事件本地索引前缀: event#index#chainRid#configContractName#
+	eventIndexPrefixFormat = "event#index#%s#%s#"
+	// eventIndexLimitFormat 跨链事件本地索引迭代的上界，'$'是'#'的下一个字符
+	eventIndexLimitFormat = "event#index#%s#%s$"
+	// defaultPageSize 默认分页大小
+	defaultPageSize = 20
+	// maxPageSize 最大分页大小
+	maxPageSize = 100
+	// defaultSyncInterval 默认的跨链事件配置缓存对账间隔, s
+	defaultSyncInterval = 600
 )
 
+// errEventNotFound 配置合约中不存在该跨链事件
+var errEventNotFound = errors.New("cross chain config not found")
+
+// OperateListExtension 列出配置合约中的全部跨链事件，tcip-go的Operate枚举中没有list，
+// 这是本网关私有的扩展值，只在本网关的CrossChainEvent接口中识别，不能转发给其他网关或者中继网关
+const OperateListExtension = common.Operate(100)
+
+// eventIndex 跨链事件在本地的索引信息
+type eventIndex struct {
+	CrossId string `json:"cross_id"`
+	// Version 配置的版本号，本网关修改或者对账时发现合约中的配置变化时加一
+	Version uint64 `json:"version"`
+	// Digest 合约中配置的摘要，用来发现其他网关对配置的修改
+	Digest     string `json:"digest"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+// EventPage 跨链事件分页查询结果
+type EventPage struct {
+	Total            int                     `json:"total"`
+	Page             int                     `json:"page"`
+	PageSize         int                     `json:"page_size"`
+	CrossChainEvents []*common.NewCrossChain `json:"cross_chain_events"`
+	// Versions CrossId到配置版本号
+	Versions map[string]uint64 `json:"versions,omitempty"`
+}
+
 // EventManager 跨链触发器结构体
 type EventManager struct {
 	log *zap.SugaredLogger
+	// lock 保证修改配置时检查版本和写入合约之间没有本网关的其他修改
+	lock sync.Mutex
+	// scanLock 配置合约交易的扫描互斥
+	scanLock sync.Mutex
 }
 
 // EventManagerV1 跨链触发器
@@ -40,6 +89,7 @@ func InitEventManager() {
 	EventManagerV1 = &EventManager{
 		log: logger.GetLogger(logger.ModuleDb),
 	}
+	eventcache.SetLoader(EventManagerV1.loadEvent)
 }
 
 // SaveEvent 保存event
@@ -49,7 +99,9 @@ func InitEventManager() {
 //	@param isNew
 //	@return error
 func (e *EventManager) SaveEvent(event *common.NewCrossChain) error {
-	err := checkEvent(event)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	err := e.validateEvent(event)
 	if err != nil {
 		e.log.Errorf("[SaveEvent] %s", err.Error())
 		return err
@@ -70,15 +122,108 @@ func (e *EventManager) SaveEvent(event *common.NewCrossChain) error {
 		e.log.Errorf("[SaveEvent] %s", err.Error())
 		return err
 	}
+	// 覆盖已有的配置时版本号继续增加，不能回到1
+	version := uint64(1)
+	index, err := e.getEventIndex(event.SrcChainRid, event.ConfigConstractName, event.CrossId)
+	if err != nil {
+		e.log.Errorf("[SaveEvent] %s", err.Error())
+		return err
+	}
+	if index != nil {
+		version = index.Version + 1
+	}
+	if err = e.putEventIndex(event.SrcChainRid, event.ConfigConstractName, event.CrossId, version,
+		eventDigest(event)); err != nil {
+		e.log.Errorf("[SaveEvent] %s", err.Error())
+		return err
+	}
+	if err = putEventCache(event, saveReqString); err != nil {
+		e.log.Errorf("[SaveEvent] %s", err.Error())
+		return err
+	}
 	return nil
 }
 
+// UpdateEvent 更新event，以配置合约为准检查CrossId对应的配置存在，每次更新版本号加一。
+// 配置合约不支持比较后写入，expectedVersion只能保证和本网关以及已经对账到的修改不冲突
+//
+//	@receiver e
+//	@param event
+//	@param expectedVersion 期望的当前版本号，为0时不检查
+//	@return uint64 更新后的版本号
+//	@return error
+func (e *EventManager) UpdateEvent(event *common.NewCrossChain, expectedVersion uint64) (uint64, error) {
+	if event.CrossId == "" {
+		err := fmt.Errorf("CrossId is required")
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	// 缓存中的配置可能已经在链上删除，不能用缓存判断是否存在，否则更新会重新创建已经删除的配置
+	oldEvent, err := e.queryEvent(event.CrossId, event.SrcChainRid, event.ConfigConstractName, event.SrcAbi)
+	if err == errEventNotFound {
+		err = fmt.Errorf("cross chain config not found: CrossId %s", event.CrossId)
+	}
+	if err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	index, err := e.refreshEventIndex(event.SrcChainRid, event.ConfigConstractName, oldEvent)
+	if err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	if expectedVersion != 0 && expectedVersion != index.Version {
+		err = fmt.Errorf("cross chain config version conflict: CrossId %s, expected version %d, current version %d",
+			event.CrossId, expectedVersion, index.Version)
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	err = e.validateEvent(event)
+	if err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	saveEventString, saveReqString, err := newCrossChain(event)
+	if err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	argsArr := make([]string, 0)
+	argsArr = append(argsArr, saveEventString)
+	argsArr = append(argsArr, saveReqString)
+	argsArr = append(argsArr, event.CrossId)
+	argsStr, _ := json.Marshal(argsArr)
+	_, _, err = chain_client.ChainClientV1.InvokeContract(event.SrcChainRid, event.ConfigConstractName,
+		cross_chain.CrossContractFuncName_newCrossChain.String(), event.SrcAbi, string(argsStr), false)
+	if err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	version := index.Version + 1
+	if err = e.putEventIndex(event.SrcChainRid, event.ConfigConstractName, event.CrossId, version,
+		eventDigest(event)); err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	if err = putEventCache(event, saveReqString); err != nil {
+		e.log.Errorf("[UpdateEvent] %s", err.Error())
+		return 0, err
+	}
+	e.log.Infof("[UpdateEvent] update cross chain config success: CrossId %s, version %d",
+		event.CrossId, version)
+	return version, nil
+}
+
 // DeleteEvent 删除event
 //
 //	@receiver e
 //	@param event
 //	@return error
 func (e *EventManager) DeleteEvent(crossId, chainRid, configConstractName, abi string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	argsArr := make([]string, 0)
 	argsArr = append(argsArr, crossId)
 	argsStr, _ := json.Marshal(argsArr)
@@ -88,54 +233,448 @@ func (e *EventManager) DeleteEvent(crossId, chainRid, configConstractName, abi s
 		e.log.Errorf("[SaveEvent] %s", err.Error())
 		return err
 	}
+	if err = db.Db.Delete(eventIndexKey(chainRid, configConstractName, crossId)); err != nil {
+		e.log.Errorf("[DeleteEvent] %s", err.Error())
+		return err
+	}
+	if err = eventcache.Delete(chainRid, crossId); err != nil {
+		e.log.Errorf("[DeleteEvent] %s", err.Error())
+		return err
+	}
 	return nil
 }
 
-// GetEvent 获取跨链事件
+// GetEvent 获取跨链事件，优先从本地缓存获取，缓存中没有时查询配置合约并写入缓存
 //
 //	@receiver e
 //	@param crossChainEventId
 //	@return []*common.CrossChainEvent
 //	@return error
 func (e *EventManager) GetEvent(crossId, chainRid, configConstractName, abi string) (*common.NewCrossChain, error) {
+	event, err := eventcache.Get(chainRid, crossId)
+	if err != nil {
+		e.log.Errorf("[GetEvent] %s", err.Error())
+		return nil, err
+	}
+	if event != nil {
+		return event, nil
+	}
+	event, err = e.queryEvent(crossId, chainRid, configConstractName, abi)
+	if err != nil {
+		e.log.Errorf("[GetEvent] %s: CrossId %s", err.Error(), crossId)
+		return nil, fmt.Errorf("%s: CrossId %s", err.Error(), crossId)
+	}
+	_, saveReqString, err := newCrossChain(event)
+	if err != nil {
+		e.log.Errorf("[GetEvent] %s", err.Error())
+		return nil, err
+	}
+	if err = putEventCache(event, saveReqString); err != nil {
+		e.log.Errorf("[GetEvent] %s", err.Error())
+		return nil, err
+	}
+	return event, nil
+}
+
+// SyncEvent 用配置合约中的数据对账本地索引和缓存，扫描配置合约的交易发现其他网关保存的配置，
+// 合约中已经不存在的配置从索引和缓存中删除
+//
+//	@receiver e
+//	@return error
+func (e *EventManager) SyncEvent() error {
+	for _, chainConfig := range conf.Config.ChainConfig {
+		chainRid, contractName := chainConfig.ChainRid, chainConfig.CrossContractName
+		err := e.discoverEvent(chainRid, contractName, func(event *common.NewCrossChain) error {
+			configConstractName := event.ConfigConstractName
+			if configConstractName == "" {
+				configConstractName = contractName
+			}
+			return e.syncEvent(event.CrossId, chainRid, configConstractName, event.SrcAbi)
+		})
+		if err != nil {
+			// 没有处理成功的一批区块不保存扫描进度，下次对账时重新扫描
+			e.log.Errorf("[SyncEvent] scan config contract of chain %s error: %s", chainRid, err.Error())
+		}
+	}
+	events, err := eventcache.List()
+	if err != nil {
+		e.log.Errorf("[SyncEvent] %s", err.Error())
+		return err
+	}
+	for _, cached := range events {
+		// 单个配置对账失败不影响其他配置的对账，下次对账时重试
+		if err = e.syncEvent(cached.CrossId, cached.SrcChainRid, cached.ConfigConstractName,
+			cached.SrcAbi); err != nil {
+			e.log.Errorf("[SyncEvent] %s: CrossId %s", err.Error(), cached.CrossId)
+		}
+	}
+	return nil
+}
+
+// syncEvent 用配置合约中的数据更新一个跨链事件的本地索引和缓存，合约中不存在时删除
+//
+//	@receiver e
+//	@param crossId
+//	@param chainRid
+//	@param configConstractName
+//	@param abi
+//	@return error
+func (e *EventManager) syncEvent(crossId, chainRid, configConstractName, abi string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	event, err := e.queryEvent(crossId, chainRid, configConstractName, abi)
+	if err == errEventNotFound {
+		if err = eventcache.Delete(chainRid, crossId); err != nil {
+			return err
+		}
+		index, err := e.getEventIndex(chainRid, configConstractName, crossId)
+		if err != nil || index == nil {
+			return err
+		}
+		e.log.Warnf("[SyncEvent] cross chain config removed from contract: CrossId %s", crossId)
+		return db.Db.Delete(eventIndexKey(chainRid, configConstractName, crossId))
+	}
+	if err != nil {
+		return err
+	}
+	// 配置合约中的配置可能不是通过本网关修改的，以合约为准覆盖索引和缓存
+	if _, err = e.refreshEventIndex(chainRid, configConstractName, event); err != nil {
+		return err
+	}
+	_, saveReqString, err := newCrossChain(event)
+	if err != nil {
+		return err
+	}
+	return putEventCache(event, saveReqString)
+}
+
+// loadEvent 触发跨链时缓存中没有对应的配置或者配置不一致，从配置合约中重新加载，
+// 查询合约需要的abi从同一个配置合约的其他配置中获取
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName 发出事件的配置合约
+//	@param crossId
+//	@return *common.NewCrossChain 合约中不存在时返回nil
+//	@return error
+func (e *EventManager) loadEvent(chainRid, configConstractName, crossId string) (*common.NewCrossChain, error) {
+	events, err := eventcache.List()
+	if err != nil {
+		return nil, err
+	}
+	var known *common.NewCrossChain
+	for _, event := range events {
+		if event.SrcChainRid == chainRid && strings.EqualFold(event.ConfigConstractName, configConstractName) {
+			known = event
+			break
+		}
+	}
+	if known == nil {
+		return nil, fmt.Errorf("no abi of config contract %s found in cached cross chain configs",
+			configConstractName)
+	}
+	if err = e.syncEvent(crossId, chainRid, known.ConfigConstractName, known.SrcAbi); err != nil {
+		e.log.Errorf("[loadEvent] %s: CrossId %s", err.Error(), crossId)
+		return nil, err
+	}
+	return eventcache.Get(chainRid, crossId)
+}
+
+// StartSync 启动时对账一次，之后定时对账跨链事件配置
+//
+//	@receiver e
+func (e *EventManager) StartSync() {
+	interval := time.Duration(defaultSyncInterval) * time.Second
+	if conf.Config.EventSync != nil && conf.Config.EventSync.Interval > 0 {
+		interval = time.Duration(conf.Config.EventSync.Interval) * time.Second
+	}
+
+	// 升级前保存的、其他网关保存的或者本地数据库清空后丢失的配置在启动时补齐
+	if err := e.SyncEvent(); err != nil {
+		e.log.Errorf("[StartSync] %s", err.Error())
+	}
+	timer := time.NewTimer(interval)
+
+	for {
+		select {
+		case <-timer.C:
+			err := e.SyncEvent()
+			if err != nil {
+				e.log.Errorf("[StartSync] %s", err.Error())
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// queryEvent 从配置合约中查询跨链事件
+//
+//	@receiver e
+//	@param crossId
+//	@param chainRid
+//	@param configConstractName
+//	@param abi
+//	@return *common.NewCrossChain
+//	@return error
+func (e *EventManager) queryEvent(crossId, chainRid, configConstractName, abi string) (*common.NewCrossChain, error) {
 	argsArr := make([]string, 0)
 	argsArr = append(argsArr, crossId)
 	argsStr, _ := json.Marshal(argsArr)
 	res, _, err := chain_client.ChainClientV1.InvokeContract(chainRid, configConstractName,
 		cross_chain.CrossContractFuncName_queryCrossChain.String(), abi, string(argsStr), false)
 	if err != nil {
-		e.log.Errorf("[SaveEvent] %s", err.Error())
 		return nil, err
 	}
+	if len(res) == 0 {
+		return nil, errEventNotFound
+	}
 	event := &common.NewCrossChain{}
 	err = proto.Unmarshal([]byte(res[0]), event)
 	if err != nil {
-		e.log.Errorf("[GetEvent] json.Unmarshal error %s", err.Error())
-		return nil, err
+		return nil, fmt.Errorf("unmarshal cross chain config error: %s", err.Error())
+	}
+	if event.CrossId != crossId {
+		return nil, errEventNotFound
+	}
+	if event.ConfigConstractName == "" {
+		event.ConfigConstractName = configConstractName
 	}
 	return event, nil
 }
 
+// ListEvent 分页列出配置合约中的跨链事件，合约不支持遍历，只读取后台对账维护的本地索引和缓存，不访问链，
+// 其他网关新保存的配置在下次对账后才能列出
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@param page 页码，从1开始
+//	@param pageSize
+//	@return *EventPage
+//	@return error
+func (e *EventManager) ListEvent(chainRid, configConstractName string, page, pageSize int) (*EventPage, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	indexes, err := e.listEventIndex(chainRid, configConstractName)
+	if err != nil {
+		e.log.Errorf("[ListEvent] %s", err.Error())
+		return nil, err
+	}
+	eventPage := &EventPage{
+		Total:            len(indexes),
+		Page:             page,
+		PageSize:         pageSize,
+		CrossChainEvents: make([]*common.NewCrossChain, 0),
+	}
+	start := (page - 1) * pageSize
+	if start >= len(indexes) {
+		return eventPage, nil
+	}
+	end := start + pageSize
+	if end > len(indexes) {
+		end = len(indexes)
+	}
+	eventPage.Versions = make(map[string]uint64)
+	for _, index := range indexes[start:end] {
+		event, err := eventcache.Get(chainRid, index.CrossId)
+		if err != nil {
+			e.log.Errorf("[ListEvent] %s", err.Error())
+			return nil, err
+		}
+		if event == nil {
+			// 索引和缓存一起写入，缓存缺失时等待下次对账补齐
+			e.log.Warnf("[ListEvent] cross chain config not cached: CrossId %s", index.CrossId)
+			continue
+		}
+		eventPage.CrossChainEvents = append(eventPage.CrossChainEvents, event)
+		eventPage.Versions[index.CrossId] = index.Version
+	}
+	return eventPage, nil
+}
+
+// EventVersion 获取跨链事件配置的版本号，本地没有索引时返回0
+//
+//	@receiver e
+//	@param crossId
+//	@param chainRid
+//	@param configConstractName
+//	@return uint64
+//	@return error
+func (e *EventManager) EventVersion(crossId, chainRid, configConstractName string) (uint64, error) {
+	index, err := e.getEventIndex(chainRid, configConstractName, crossId)
+	if err != nil || index == nil {
+		return 0, err
+	}
+	return index.Version, nil
+}
+
+// putEventIndex 写入跨链事件的本地索引
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@param crossId
+//	@param version
+//	@param digest 合约中配置的摘要
+//	@return error
+func (e *EventManager) putEventIndex(chainRid, configConstractName, crossId string, version uint64,
+	digest string) error {
+	indexByte, _ := json.Marshal(&eventIndex{
+		CrossId:    crossId,
+		Version:    version,
+		Digest:     digest,
+		UpdateTime: time.Now().Unix(),
+	})
+	return db.Db.Put(eventIndexKey(chainRid, configConstractName, crossId), indexByte)
+}
+
+// refreshEventIndex 用合约中的配置更新本地索引，本地没有索引时从版本1开始，
+// 配置和上次记录的不一致时说明被其他网关修改过，版本号加一
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@param event 合约中的配置
+//	@return *eventIndex
+//	@return error
+func (e *EventManager) refreshEventIndex(chainRid, configConstractName string,
+	event *common.NewCrossChain) (*eventIndex, error) {
+	index, err := e.getEventIndex(chainRid, configConstractName, event.CrossId)
+	if err != nil {
+		return nil, err
+	}
+	digest := eventDigest(event)
+	if index != nil && index.Digest == digest {
+		return index, nil
+	}
+	version := uint64(1)
+	if index != nil {
+		version = index.Version + 1
+	}
+	if err = e.putEventIndex(chainRid, configConstractName, event.CrossId, version, digest); err != nil {
+		return nil, err
+	}
+	return &eventIndex{CrossId: event.CrossId, Version: version, Digest: digest}, nil
+}
+
+// getEventIndex 获取跨链事件的本地索引，不存在返回nil
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@param crossId
+//	@return *eventIndex
+//	@return error
+func (e *EventManager) getEventIndex(chainRid, configConstractName, crossId string) (*eventIndex, error) {
+	indexByte, err := db.Db.Get(eventIndexKey(chainRid, configConstractName, crossId))
+	if err != nil {
+		return nil, err
+	}
+	if len(indexByte) == 0 {
+		return nil, nil
+	}
+	index := &eventIndex{}
+	if err = json.Unmarshal(indexByte, index); err != nil {
+		return nil, fmt.Errorf("unmarshal event index error: %s", err.Error())
+	}
+	return index, nil
+}
+
+// listEventIndex 按照CrossId的顺序列出本地索引
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@return []*eventIndex
+//	@return error
+func (e *EventManager) listEventIndex(chainRid, configConstractName string) ([]*eventIndex, error) {
+	iter, err := db.Db.NewIteratorWithRange(
+		[]byte(fmt.Sprintf(eventIndexPrefixFormat, chainRid, configConstractName)),
+		[]byte(fmt.Sprintf(eventIndexLimitFormat, chainRid, configConstractName)))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+	indexes := make([]*eventIndex, 0)
+	for iter.Next() {
+		index := &eventIndex{}
+		if err = json.Unmarshal(iter.Value(), index); err != nil {
+			return nil, fmt.Errorf("unmarshal event index error: %s", err.Error())
+		}
+		indexes = append(indexes, index)
+	}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// eventIndexKey 跨链事件本地索引的key
+//
+//	@param chainRid
+//	@param configConstractName
+//	@param crossId
+//	@return []byte
+func eventIndexKey(chainRid, configConstractName, crossId string) []byte {
+	return []byte(fmt.Sprintf(eventIndexPrefixFormat, chainRid, configConstractName) + crossId)
+}
+
+// eventDigest 跨链事件配置的摘要
+//
+//	@param event
+//	@return string
+func eventDigest(event *common.NewCrossChain) string {
+	eventByte, _ := proto.Marshal(event)
+	digest := sha256.Sum256(eventByte)
+	return hex.EncodeToString(digest[:])
+}
+
+// putEventCache 缓存跨链事件配置
+//
+//	@param event
+//	@param saveReqString 保存到配置合约中的跨链请求，base64编码
+//	@return error
+func putEventCache(event *common.NewCrossChain, saveReqString string) error {
+	saveReqByte, err := base64.StdEncoding.DecodeString(saveReqString)
+	if err != nil {
+		return fmt.Errorf("decode saveReqString error: %s", err.Error())
+	}
+	return eventcache.Put(event, saveReqByte)
+}
+
 func newCrossChain(crossConfig *common.NewCrossChain) (string, string, error) {
-	crossChainMsg := make([]*common.CrossChainMsg, 1)
-	crossChainMsg[0] = &common.CrossChainMsg{
-		GatewayId:    crossConfig.DestGatewayId,
-		ChainRid:     crossConfig.DestChainRid,
-		ContractName: crossConfig.DestContractName,
-		Method:       crossConfig.DestTryMethod,
-		Abi:          crossConfig.DestAbi,
-		ConfirmInfo: &common.ConfirmInfo{
-			ChainRid:     crossConfig.DestChainRid,
-			ContractName: crossConfig.DestContractName,
-			Method:       crossConfig.DestConfirmMethod,
-			Abi:          crossConfig.DestAbi,
-		},
-		CancelInfo: &common.CancelInfo{
-			ChainRid:     crossConfig.DestChainRid,
-			ContractName: crossConfig.DestContractName,
-			Method:       crossConfig.DestCancelMethod,
-			Abi:          crossConfig.DestAbi,
-		},
+	destinations, err := utils.GetDestinations(crossConfig)
+	if err != nil {
+		return "", "", err
+	}
+	crossChainMsg := make([]*common.CrossChainMsg, len(destinations))
+	for i, dest := range destinations {
+		crossChainMsg[i] = &common.CrossChainMsg{
+			GatewayId:    dest.GatewayId,
+			ChainRid:     dest.ChainRid,
+			ContractName: dest.ContractName,
+			Method:       dest.TryMethod,
+			Abi:          dest.Abi,
+			ConfirmInfo: &common.ConfirmInfo{
+				ChainRid:     dest.ChainRid,
+				ContractName: dest.ContractName,
+				Method:       dest.ConfirmMethod,
+				Abi:          dest.Abi,
+			},
+			CancelInfo: &common.CancelInfo{
+				ChainRid:     dest.ChainRid,
+				ContractName: dest.ContractName,
+				Method:       dest.CancelMethod,
+				Abi:          dest.Abi,
+			},
+		}
 	}
 	saveReqData := &relay_chain.BeginCrossChainRequest{
 		Version:        common.Version_V1_0_0,
@@ -168,29 +707,3 @@ func newCrossChain(crossConfig *common.NewCrossChain) (string, string, error) {
 	saveReqString := base64.StdEncoding.EncodeToString(saveReqByte)
 	return saveEventString, saveReqString, nil
 }
-
-// checkEvent 检查事件配置是否合法
-//
-//	@param event
-//	@return error
-func checkEvent(event *common.NewCrossChain) error {
-	if event.CrossId == "" {
-		return fmt.Errorf("CrossId is required")
-	}
-	if event.DestGatewayId == "" {
-		return fmt.Errorf("DestGatewayId is required")
-	}
-	if event.DestContractName == "" {
-		return fmt.Errorf("DestTryMethod is required")
-	}
-	if event.DestTryMethod == "" {
-		return fmt.Errorf("DestTryMethod is required")
-	}
-	if event.DestGatewayId == common.MainGateway_MAIN_GATEWAY_ID.String() {
-		return nil
-	}
-	if event.DestChainRid == "" {
-		return fmt.Errorf("DestChainRid is required")
-	}
-	return nil
-}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// eventScanKeyFormat 配置合约交易的扫描进度: event#scan#chainRid#configContractName
+	eventScanKeyFormat = "event#scan#%s#%s"
+	// scanBatchSize 每批扫描的区块数，每批扫描完保存一次进度
+	scanBatchSize = 100
+)
+
+// discoverEvent 扫描配置合约上还没有扫描过的交易，找出newCrossChain保存过的跨链事件配置交给handle处理，
+// 配置合约不支持遍历，这是获得合约中全部CrossId的唯一途径。会访问链，只在后台对账时调用。
+// 一批区块中的配置全部处理成功后才保存扫描进度，处理失败时下次从这一批重新扫描，不会漏掉CrossId
+//
+//	@receiver e
+//	@param chainRid
+//	@param configConstractName
+//	@param handle 处理交易中保存的配置，合约中的配置可能已经修改或者删除
+//	@return error
+func (e *EventManager) discoverEvent(chainRid, configConstractName string,
+	handle func(event *common.NewCrossChain) error) error {
+	e.scanLock.Lock()
+	defer e.scanLock.Unlock()
+	key := []byte(fmt.Sprintf(eventScanKeyFormat, chainRid, configConstractName))
+	fromHeight := int64(0)
+	heightByte, err := db.Db.Get(key)
+	if err != nil {
+		return err
+	}
+	if len(heightByte) != 0 {
+		height, err := strconv.ParseInt(string(heightByte), 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse scan height error: %s", err.Error())
+		}
+		fromHeight = height + 1
+	}
+	for {
+		toHeight := fromHeight + scanBatchSize - 1
+		txs, scannedHeight, err := chain_client.ChainClientV1.ScanContractTx(chainRid, configConstractName,
+			fromHeight, toHeight)
+		if err != nil {
+			return err
+		}
+		if scannedHeight < fromHeight {
+			return nil
+		}
+		// 同一批中多次保存的配置只处理一次
+		handled := make(map[string]bool)
+		for _, tx := range txs {
+			event := savedEvent(tx.Input)
+			if event == nil || handled[event.CrossId] {
+				continue
+			}
+			if err = handle(event); err != nil {
+				return fmt.Errorf("%s: CrossId %s", err.Error(), event.CrossId)
+			}
+			handled[event.CrossId] = true
+		}
+		if err = db.Db.Put(key, []byte(fmt.Sprintf("%d", scannedHeight))); err != nil {
+			return err
+		}
+		if scannedHeight < toHeight {
+			return nil
+		}
+		fromHeight = scannedHeight + 1
+	}
+}
+
+// savedEvent 从newCrossChain(saveEvent, saveReq, crossId)交易的输入中解析出保存的跨链事件配置，
+// 其他方法的交易返回nil
+//
+//	@param input
+//	@return *common.NewCrossChain
+func savedEvent(input string) *common.NewCrossChain {
+	args := utils.InputStrings(input)
+	for _, arg := range args {
+		eventByte, err := base64.StdEncoding.DecodeString(arg)
+		if err != nil || len(eventByte) == 0 {
+			continue
+		}
+		event := &common.NewCrossChain{}
+		if err = proto.Unmarshal(eventByte, event); err != nil || event.CrossId == "" {
+			continue
+		}
+		// crossId参数和配置中的CrossId一致才是newCrossChain的交易
+		for _, crossId := range args {
+			if crossId == event.CrossId {
+				return event
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"encoding/json"
+)
+
+const (
+	// TbisFlag tbis格式跨链的CrossChainFlag
+	TbisFlag = "tbis_event"
+	// SubSuccess 子交易执行成功
+	SubSuccess = 0
+	// SubFailed 子交易执行失败
+	SubFailed = 1
+)
+
+// tbisCommitParam tbis格式的commit参数
+type tbisCommitParam struct {
+	ChainRid       string `json:"chain_rid"`
+	ProveStatus    int    `json:"prove_status"`
+	ContractStatus int    `json:"contract_status"`
+	ContractResult string `json:"contract_result"`
+}
+
+// GetCommitParam 获取tbis格式的commit参数
+//
+//	@param chainRid 执行try的链资源id
+//	@param proveStatus 交易证明状态
+//	@param contractStatus 合约执行状态
+//	@param contractResult 合约执行结果
+//	@return string
+func GetCommitParam(chainRid string, proveStatus, contractStatus int, contractResult string) string {
+	res, _ := json.Marshal(&tbisCommitParam{
+		ChainRid:       chainRid,
+		ProveStatus:    proveStatus,
+		ContractStatus: contractStatus,
+		ContractResult: contractResult,
+	})
+	return string(res)
+}
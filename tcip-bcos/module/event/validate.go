@@ -0,0 +1,212 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"fmt"
+	"strings"
+
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
+)
+
+// abiMethod abi中需要存在的方法
+type abiMethod struct {
+	name   string
+	inputs int
+}
+
+// configContractMethods 配置合约中网关会调用的方法和参数个数
+var configContractMethods = []abiMethod{
+	{cross_chain.CrossContractFuncName_newCrossChain.String(), 3},
+	{cross_chain.CrossContractFuncName_deleteCrossChain.String(), 1},
+	{cross_chain.CrossContractFuncName_queryCrossChain.String(), 1},
+}
+
+// localContract 需要检查部署情况的本地合约
+type localContract struct {
+	name     string
+	chainRid string
+	address  string
+}
+
+// validateEvent 检查跨链事件配置，所有不合法的地方一起返回
+//
+//	@receiver e
+//	@param event
+//	@return error
+func (e *EventManager) validateEvent(event *common.NewCrossChain) error {
+	violations := checkEvent(event)
+	violations = append(violations, checkEventAbi(event)...)
+	violations = append(violations, checkEventContract(event)...)
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid cross chain config: %s", strings.Join(violations, "; "))
+}
+
+// checkEvent 检查事件配置的必填字段
+//
+//	@param event
+//	@return []string 不合法的地方
+func checkEvent(event *common.NewCrossChain) []string {
+	violations := make([]string, 0)
+	if event.CrossId == "" {
+		violations = append(violations, "CrossId is required")
+	}
+	if event.SrcChainRid == "" {
+		violations = append(violations, "SrcChainRid is required")
+	}
+	if event.ConfigConstractName == "" {
+		violations = append(violations, "ConfigConstractName is required")
+	}
+	if event.SrcAbi == "" {
+		violations = append(violations, "SrcAbi is required")
+	}
+	destinations, err := utils.GetDestinations(event)
+	if err != nil {
+		return append(violations, err.Error())
+	}
+	for i, dest := range destinations {
+		for _, violation := range checkDestination(dest) {
+			if len(destinations) > 1 {
+				violation = fmt.Sprintf("destination %d: %s", i, violation)
+			}
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// checkDestination 检查跨链目标的必填字段
+//
+//	@param dest
+//	@return []string 不合法的地方
+func checkDestination(dest *utils.Destination) []string {
+	violations := make([]string, 0)
+	if dest.GatewayId == "" {
+		violations = append(violations, "DestGatewayId is required")
+	}
+	if dest.ContractName == "" {
+		violations = append(violations, "DestContractName is required")
+	}
+	if dest.TryMethod == "" {
+		violations = append(violations, "DestTryMethod is required")
+	}
+	if dest.GatewayId != common.MainGateway_MAIN_GATEWAY_ID.String() && dest.ChainRid == "" {
+		violations = append(violations, "DestChainRid is required")
+	}
+	return violations
+}
+
+// checkEventAbi 检查配置中引用的方法在abi中存在并且参数个数兼容
+//
+// SrcAbi同时用于调用配置合约和源链业务合约，tbis格式的confirm和cancel方法第一个参数固定为commit参数
+//
+//	@param event
+//	@return []string 不合法的地方
+func checkEventAbi(event *common.NewCrossChain) []string {
+	violations := make([]string, 0)
+	minInputs := 0
+	if event.Desc == TbisFlag {
+		minInputs = 1
+	}
+	if event.SrcAbi != "" {
+		srcAbi, err := utils.ParseAbi(event.SrcAbi)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("SrcAbi: %s", err.Error()))
+		} else {
+			for _, method := range configContractMethods {
+				if err = utils.CheckAbiMethod(srcAbi, method.name, method.inputs, method.inputs); err != nil {
+					violations = append(violations, fmt.Sprintf("SrcAbi: %s", err.Error()))
+				}
+			}
+			for _, method := range []string{event.SrcConfirmMethod, event.SrcCancelMethod} {
+				if method == "" {
+					continue
+				}
+				if err = utils.CheckAbiMethod(srcAbi, method, minInputs, -1); err != nil {
+					violations = append(violations, fmt.Sprintf("SrcAbi: %s", err.Error()))
+				}
+			}
+		}
+	}
+	destinations, err := utils.GetDestinations(event)
+	if err != nil {
+		// checkEvent中已经报告过
+		return violations
+	}
+	for i, dest := range destinations {
+		// 目标链不一定是evm合约，没有abi时不检查
+		if dest.Abi == "" {
+			continue
+		}
+		name := "DestAbi"
+		if len(destinations) > 1 {
+			name = fmt.Sprintf("destination %d: DestAbi", i)
+		}
+		destAbi, err := utils.ParseAbi(dest.Abi)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+		if dest.TryMethod != "" {
+			if err = utils.CheckAbiMethod(destAbi, dest.TryMethod, 0, -1); err != nil {
+				violations = append(violations, fmt.Sprintf("%s: %s", name, err.Error()))
+			}
+		}
+		for _, method := range []string{dest.ConfirmMethod, dest.CancelMethod} {
+			if method == "" {
+				continue
+			}
+			if err = utils.CheckAbiMethod(destAbi, method, minInputs, -1); err != nil {
+				violations = append(violations, fmt.Sprintf("%s: %s", name, err.Error()))
+			}
+		}
+	}
+	return violations
+}
+
+// checkEventContract 检查本网关管理的合约地址合法并且已经部署
+//
+//	@param event
+//	@return []string 不合法的地方
+func checkEventContract(event *common.NewCrossChain) []string {
+	violations := make([]string, 0)
+	if event.SrcChainRid == "" {
+		return violations
+	}
+	contracts := []*localContract{
+		{"SrcContractName", event.SrcChainRid, event.SrcContractName},
+		{"ConfigConstractName", event.SrcChainRid, event.ConfigConstractName},
+	}
+	destinations, err := utils.GetDestinations(event)
+	if err == nil {
+		for i, dest := range destinations {
+			// 目标网关是本网关时，目标合约也在本地链上
+			if dest.GatewayId != conf.Config.BaseConfig.GatewayID || dest.ContractName == "" {
+				continue
+			}
+			name := "DestContractName"
+			if len(destinations) > 1 {
+				name = fmt.Sprintf("destination %d: DestContractName", i)
+			}
+			contracts = append(contracts, &localContract{name, dest.ChainRid, dest.ContractName})
+		}
+	}
+	for _, contract := range contracts {
+		if contract.address == "" {
+			continue
+		}
+		if err = chain_client.ChainClientV1.CheckContract(contract.chainRid, contract.address); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", contract.name, err.Error()))
+		}
+	}
+	return violations
+}
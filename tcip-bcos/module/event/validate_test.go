@@ -0,0 +1,118 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+)
+
+func TestValidateEvent(t *testing.T) {
+	mock := initTest()
+	defer db.Db.Close()
+	mock.undeployed["undeployed"] = true
+
+	tests := []struct {
+		name  string
+		event func() *common.NewCrossChain
+		want  []string
+	}{
+		{
+			name:  "valid",
+			event: func() *common.NewCrossChain { return newTestEvent("cross1") },
+		},
+		{
+			name:  "required",
+			event: func() *common.NewCrossChain { return &common.NewCrossChain{} },
+			want: []string{
+				"CrossId is required",
+				"SrcChainRid is required",
+				"ConfigConstractName is required",
+				"SrcAbi is required",
+				"DestGatewayId is required",
+				"DestContractName is required",
+				"DestTryMethod is required",
+				"DestChainRid is required",
+			},
+		},
+		{
+			name: "abi",
+			event: func() *common.NewCrossChain {
+				event := newTestEvent("cross1")
+				event.SrcAbi = destAbi
+				event.DestTryMethod = "transfer"
+				return event
+			},
+			want: []string{
+				"SrcAbi: method newCrossChain not found in abi",
+				"SrcAbi: method deleteCrossChain not found in abi",
+				"SrcAbi: method queryCrossChain not found in abi",
+				"DestAbi: method transfer not found in abi",
+			},
+		},
+		{
+			name: "invalid abi",
+			event: func() *common.NewCrossChain {
+				event := newTestEvent("cross1")
+				event.SrcAbi = "not abi"
+				event.DestAbi = "not abi"
+				return event
+			},
+			want: []string{"SrcAbi: abi read error", "DestAbi: abi read error"},
+		},
+		{
+			name: "tbis commit param",
+			event: func() *common.NewCrossChain {
+				event := newTestEvent("cross1")
+				event.Desc = TbisFlag
+				return event
+			},
+			want: []string{"DestAbi: method cancel has 0 inputs, need at least 1"},
+		},
+		{
+			name: "multiple destinations",
+			event: func() *common.NewCrossChain {
+				event := newTestEvent("cross1")
+				event.DestGatewayId = `["1","2"]`
+				event.DestChainRid = `["chain2","chain3"]`
+				event.DestContractName = `["dest",""]`
+				return event
+			},
+			want: []string{"destination 1: DestContractName is required"},
+		},
+		{
+			name: "contract",
+			event: func() *common.NewCrossChain {
+				event := newTestEvent("cross1")
+				event.SrcContractName = "undeployed"
+				event.ConfigConstractName = "undeployed"
+				return event
+			},
+			want: []string{
+				"SrcContractName: contract undeployed not deployed",
+				"ConfigConstractName: contract undeployed not deployed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EventManagerV1.validateEvent(tt.event())
+			if len(tt.want) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			// 所有不合法的地方在一个错误中一起返回
+			assert.NotNil(t, err)
+			for _, violation := range tt.want {
+				assert.Contains(t, err.Error(), violation)
+			}
+		})
+	}
+}
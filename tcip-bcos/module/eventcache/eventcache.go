@@ -0,0 +1,310 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// cacheKeyFormat 跨链事件配置缓存: event#cache#chainRid#crossId
+	cacheKeyFormat = "event#cache#%s#%s"
+	// cachePrefix 全部跨链事件配置缓存的前缀
+	cachePrefix = "event#cache#"
+	// cacheLimit 全部跨链事件配置缓存迭代的上界，'$'是'#'的下一个字符
+	cacheLimit = "event#cache$"
+	// reqKeyFormat 跨链请求摘要到CrossId的索引: event#req#chainRid#sha256(req)
+	reqKeyFormat = "event#req#%s#%s"
+)
+
+// ErrLoadConfig 没有加载到跨链事件配置，可能是链或者本地数据库暂时不可用，可以重试，和配置不一致不同
+var ErrLoadConfig = errors.New("load cross chain config failed")
+
+// Loader 从配置合约中重新加载跨链事件配置并写入缓存，合约中不存在时返回nil
+type Loader func(chainRid, configConstractName, crossId string) (*common.NewCrossChain, error)
+
+// loader 缓存未命中时加载配置，eventcache不能依赖event模块，由event模块初始化时设置
+var loader Loader
+
+// SetLoader 设置缓存未命中时加载配置的方法
+//
+//	@param l
+func SetLoader(l Loader) {
+	loader = l
+}
+
+// Put 缓存跨链事件配置，saveReq是保存到配置合约中的跨链请求，触发跨链时会原样出现在事件中
+//
+//	@param event
+//	@param saveReq
+//	@return error
+func Put(event *common.NewCrossChain, saveReq []byte) error {
+	old, err := Get(event.SrcChainRid, event.CrossId)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		if err = deleteReqIndex(old); err != nil {
+			return err
+		}
+	}
+	eventByte, err := proto.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal cross chain config error: %s", err.Error())
+	}
+	if err = db.Db.Put([]byte(fmt.Sprintf(reqKeyFormat, event.SrcChainRid, reqDigest(saveReq))),
+		[]byte(event.CrossId)); err != nil {
+		return err
+	}
+	return db.Db.Put(cacheKey(event.SrcChainRid, event.CrossId), eventByte)
+}
+
+// Get 获取缓存的跨链事件配置，不存在返回nil
+//
+//	@param chainRid
+//	@param crossId
+//	@return *common.NewCrossChain
+//	@return error
+func Get(chainRid, crossId string) (*common.NewCrossChain, error) {
+	eventByte, err := db.Db.Get(cacheKey(chainRid, crossId))
+	if err != nil {
+		return nil, err
+	}
+	if len(eventByte) == 0 {
+		return nil, nil
+	}
+	event := &common.NewCrossChain{}
+	if err = proto.Unmarshal(eventByte, event); err != nil {
+		return nil, fmt.Errorf("unmarshal cross chain config error: %s", err.Error())
+	}
+	return event, nil
+}
+
+// GetByRequest 根据事件中携带的跨链请求获取对应的跨链事件配置，不存在返回nil
+//
+//	@param chainRid
+//	@param req
+//	@return *common.NewCrossChain
+//	@return error
+func GetByRequest(chainRid string, req []byte) (*common.NewCrossChain, error) {
+	crossId, err := db.Db.Get([]byte(fmt.Sprintf(reqKeyFormat, chainRid, reqDigest(req))))
+	if err != nil {
+		return nil, err
+	}
+	if len(crossId) == 0 {
+		return nil, nil
+	}
+	return Get(chainRid, string(crossId))
+}
+
+// CheckRequest 根据触发交易中的CrossId找到跨链事件配置，检查事件中携带的跨链请求和配置一致，
+// 缓存中没有或者不一致时从配置合约重新加载，合约中也没有一致的配置时才拒绝，
+// 没有加载到配置时返回ErrLoadConfig，调用方应该重试而不是拒绝
+//
+//	@param chainRid 触发跨链的链资源id
+//	@param configConstractName 发出事件的配置合约
+//	@param txInput 触发跨链的交易输入
+//	@param reqData 事件中携带的跨链请求原文
+//	@param req 反序列化后的跨链请求
+//	@return error
+func CheckRequest(chainRid, configConstractName, txInput string, reqData []byte,
+	req *relay_chain.BeginCrossChainRequest) error {
+	crossIds, err := candidateCrossIds(chainRid, txInput, reqData)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrLoadConfig, err.Error())
+	}
+	if len(crossIds) == 0 {
+		return fmt.Errorf("no CrossId found in trigger tx")
+	}
+	for _, crossId := range crossIds {
+		event, err := Get(chainRid, crossId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrLoadConfig, err.Error())
+		}
+		if event != nil && checkRequest(event, req) == nil {
+			return nil
+		}
+	}
+	err = fmt.Errorf("no registered cross chain config matches the request")
+	if loader == nil {
+		return err
+	}
+	// 一个CrossId加载失败时继续检查其他的，都没有一致的配置时才返回加载失败
+	var loadErr error
+	for _, crossId := range crossIds {
+		event, e := loader(chainRid, configConstractName, crossId)
+		if e != nil {
+			loadErr = fmt.Errorf("%w: CrossId %s: %s", ErrLoadConfig, crossId, e.Error())
+			continue
+		}
+		if event == nil {
+			continue
+		}
+		if err = checkRequest(event, req); err == nil {
+			return nil
+		}
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	return err
+}
+
+// candidateCrossIds 触发跨链的CrossId，配置合约触发跨链时CrossId是交易的string参数之一，
+// 业务合约间接调用时交易中没有CrossId，再用跨链请求摘要索引查找
+//
+//	@param chainRid
+//	@param txInput
+//	@param reqData
+//	@return []string
+//	@return error
+func candidateCrossIds(chainRid, txInput string, reqData []byte) ([]string, error) {
+	crossIds := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, arg := range utils.InputStrings(txInput) {
+		if arg != "" && !seen[arg] {
+			seen[arg] = true
+			crossIds = append(crossIds, arg)
+		}
+	}
+	crossId, err := db.Db.Get([]byte(fmt.Sprintf(reqKeyFormat, chainRid, reqDigest(reqData))))
+	if err != nil {
+		return nil, err
+	}
+	if len(crossId) != 0 && !seen[string(crossId)] {
+		crossIds = append(crossIds, string(crossId))
+	}
+	return crossIds, nil
+}
+
+// checkRequest 逐个字段比较跨链请求的源链和目标链是否和跨链事件配置一致
+//
+//	@param event
+//	@param req
+//	@return error
+func checkRequest(event *common.NewCrossChain, req *relay_chain.BeginCrossChainRequest) error {
+	if req.ConfirmInfo == nil || req.CancelInfo == nil ||
+		req.ConfirmInfo.ChainRid != event.SrcChainRid || req.ConfirmInfo.ContractName != event.SrcContractName ||
+		req.CancelInfo.ChainRid != event.SrcChainRid || req.CancelInfo.ContractName != event.SrcContractName {
+		return fmt.Errorf("source not match cross chain config: CrossId %s", event.CrossId)
+	}
+	destinations, err := utils.GetDestinations(event)
+	if err != nil {
+		return err
+	}
+	if len(req.CrossChainMsg) != len(destinations) {
+		return fmt.Errorf("destination count not match cross chain config: CrossId %s", event.CrossId)
+	}
+	for i, dest := range destinations {
+		msg := req.CrossChainMsg[i]
+		if msg.ConfirmInfo == nil || msg.CancelInfo == nil ||
+			msg.GatewayId != dest.GatewayId || msg.ChainRid != dest.ChainRid ||
+			msg.ContractName != dest.ContractName || msg.Method != dest.TryMethod ||
+			msg.ConfirmInfo.Method != dest.ConfirmMethod || msg.CancelInfo.Method != dest.CancelMethod {
+			return fmt.Errorf("destination %d not match cross chain config: CrossId %s", i, event.CrossId)
+		}
+	}
+	return nil
+}
+
+// Delete 删除缓存的跨链事件配置
+//
+//	@param chainRid
+//	@param crossId
+//	@return error
+func Delete(chainRid, crossId string) error {
+	old, err := Get(chainRid, crossId)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return nil
+	}
+	if err = deleteReqIndex(old); err != nil {
+		return err
+	}
+	return db.Db.Delete(cacheKey(chainRid, crossId))
+}
+
+// List 列出全部缓存的跨链事件配置
+//
+//	@return []*common.NewCrossChain
+//	@return error
+func List() ([]*common.NewCrossChain, error) {
+	iter, err := db.Db.NewIteratorWithRange([]byte(cachePrefix), []byte(cacheLimit))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+	events := make([]*common.NewCrossChain, 0)
+	for iter.Next() {
+		event := &common.NewCrossChain{}
+		if err = proto.Unmarshal(iter.Value(), event); err != nil {
+			return nil, fmt.Errorf("unmarshal cross chain config error: %s", err.Error())
+		}
+		events = append(events, event)
+	}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// deleteReqIndex 删除跨链请求摘要索引
+//
+//	@param event
+//	@return error
+func deleteReqIndex(event *common.NewCrossChain) error {
+	iter, err := db.Db.NewIteratorWithRange(
+		[]byte(fmt.Sprintf(reqKeyFormat, event.SrcChainRid, "")),
+		[]byte(fmt.Sprintf("event#req#%s$", event.SrcChainRid)))
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+	keys := make([][]byte, 0)
+	for iter.Next() {
+		if string(iter.Value()) == event.CrossId {
+			keys = append(keys, append([]byte{}, iter.Key()...))
+		}
+	}
+	if err = iter.Error(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err = db.Db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cacheKey 跨链事件配置缓存的key
+//
+//	@param chainRid
+//	@param crossId
+//	@return []byte
+func cacheKey(chainRid, crossId string) []byte {
+	return []byte(fmt.Sprintf(cacheKeyFormat, chainRid, crossId))
+}
+
+// reqDigest 跨链请求的摘要
+//
+//	@param req
+//	@return string
+func reqDigest(req []byte) string {
+	digest := sha256.Sum256(req)
+	return hex.EncodeToString(digest[:])
+}
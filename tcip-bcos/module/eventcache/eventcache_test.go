@@ -0,0 +1,252 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventcache
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+	bcosabi "github.com/FISCO-BCOS/go-sdk/abi"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+const chainRid = "chain1"
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	logger.InitLogConfig(log)
+	db.NewDbHandle()
+}
+
+func newEvent(crossId, destChainRid string) *common.NewCrossChain {
+	return &common.NewCrossChain{
+		CrossId:           crossId,
+		SrcChainRid:       chainRid,
+		SrcContractName:   "src",
+		DestGatewayId:     "1",
+		DestChainRid:      destChainRid,
+		DestContractName:  "dest",
+		DestTryMethod:     "try",
+		DestConfirmMethod: "confirm",
+		DestCancelMethod:  "cancel",
+	}
+}
+
+func newRequest(event *common.NewCrossChain) (*relay_chain.BeginCrossChainRequest, []byte) {
+	req := &relay_chain.BeginCrossChainRequest{
+		CrossChainMsg: []*common.CrossChainMsg{newMsg(event)},
+		ConfirmInfo:   &common.ConfirmInfo{ChainRid: event.SrcChainRid, ContractName: event.SrcContractName},
+		CancelInfo:    &common.CancelInfo{ChainRid: event.SrcChainRid, ContractName: event.SrcContractName},
+	}
+	reqData, _ := proto.Marshal(req)
+	return req, reqData
+}
+
+func newMsg(event *common.NewCrossChain) *common.CrossChainMsg {
+	return &common.CrossChainMsg{
+		GatewayId:    event.DestGatewayId,
+		ChainRid:     event.DestChainRid,
+		ContractName: event.DestContractName,
+		Method:       event.DestTryMethod,
+		ConfirmInfo:  &common.ConfirmInfo{Method: event.DestConfirmMethod},
+		CancelInfo:   &common.CancelInfo{Method: event.DestCancelMethod},
+	}
+}
+
+// newTxInput 配置合约触发跨链的交易输入，参数是CrossId和触发参数
+func newTxInput(crossId string) string {
+	stringType, _ := bcosabi.NewType("string", "", nil)
+	args := bcosabi.Arguments{{Type: stringType}, {Type: stringType}}
+	packed, _ := args.Pack(crossId, "param")
+	return "0x12345678" + hex.EncodeToString(packed)
+}
+
+func TestPutGetDelete(t *testing.T) {
+	initTest()
+	defer db.Db.Close()
+
+	event := newEvent("cross1", "chain2")
+	_, reqData := newRequest(event)
+	assert.Nil(t, Put(event, reqData))
+
+	got, err := Get(chainRid, "cross1")
+	assert.Nil(t, err)
+	assert.Equal(t, "chain2", got.DestChainRid)
+
+	got, err = GetByRequest(chainRid, reqData)
+	assert.Nil(t, err)
+	assert.Equal(t, "cross1", got.CrossId)
+
+	// 更新后旧请求的索引失效
+	updated := newEvent("cross1", "chain3")
+	_, updatedReqData := newRequest(updated)
+	assert.Nil(t, Put(updated, updatedReqData))
+	got, err = GetByRequest(chainRid, reqData)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+	got, err = GetByRequest(chainRid, updatedReqData)
+	assert.Nil(t, err)
+	assert.Equal(t, "chain3", got.DestChainRid)
+
+	events, err := List()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(events))
+
+	assert.Nil(t, Delete(chainRid, "cross1"))
+	got, err = Get(chainRid, "cross1")
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+	got, err = GetByRequest(chainRid, updatedReqData)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+	assert.Nil(t, Delete(chainRid, "cross1"))
+}
+
+func TestCheckRequest(t *testing.T) {
+	initTest()
+	defer db.Db.Close()
+
+	event := newEvent("cross1", "chain2")
+	req, reqData := newRequest(event)
+	assert.Nil(t, Put(event, reqData))
+
+	multiEvent := newEvent("cross2", `["chain2","chain3"]`)
+	multiEvent.DestGatewayId = `["1","2"]`
+	multiReq, _ := newRequest(newEvent("cross2", "chain2"))
+	multiReq.CrossChainMsg = append(multiReq.CrossChainMsg, newMsg(newEvent("cross2", "chain3")))
+	multiReq.CrossChainMsg[1].GatewayId = "2"
+	multiReqData, _ := proto.Marshal(multiReq)
+	assert.Nil(t, Put(multiEvent, multiReqData))
+
+	tamperedReq, tamperedReqData := newRequest(newEvent("cross1", "evil"))
+	noDestReq, _ := newRequest(event)
+	noDestReq.CrossChainMsg = nil
+	otherDestReq, _ := newRequest(newEvent("cross1", "evil"))
+
+	// 只在合约中存在的配置，缓存中没有
+	contractEvent := newEvent("cross3", "chain4")
+	contractReq, contractReqData := newRequest(contractEvent)
+	SetLoader(func(chainRid, configConstractName, crossId string) (*common.NewCrossChain, error) {
+		if crossId == "unavailable" {
+			return nil, fmt.Errorf("chain unavailable")
+		}
+		if crossId != contractEvent.CrossId {
+			return nil, nil
+		}
+		return contractEvent, nil
+	})
+	defer SetLoader(nil)
+
+	tests := []struct {
+		name    string
+		txInput string
+		reqData []byte
+		req     *relay_chain.BeginCrossChainRequest
+		wantErr bool
+		// wantLoadErr 没有加载到配置，可以重试
+		wantLoadErr bool
+	}{
+		{
+			name:    "registered",
+			reqData: reqData,
+			req:     req,
+			wantErr: false,
+		},
+		{
+			name:    "CrossId in tx",
+			txInput: newTxInput("cross1"),
+			reqData: tamperedReqData,
+			req:     req,
+			wantErr: false,
+		},
+		{
+			name:    "loaded from contract",
+			txInput: newTxInput("cross3"),
+			reqData: contractReqData,
+			req:     contractReq,
+			wantErr: false,
+		},
+		{
+			name:    "not in contract",
+			txInput: newTxInput("cross4"),
+			reqData: contractReqData,
+			req:     contractReq,
+			wantErr: true,
+		},
+		{
+			name:        "load failed",
+			txInput:     newTxInput("unavailable"),
+			reqData:     contractReqData,
+			req:         contractReq,
+			wantErr:     true,
+			wantLoadErr: true,
+		},
+		{
+			name:    "tampered with CrossId in tx",
+			txInput: newTxInput("cross1"),
+			reqData: tamperedReqData,
+			req:     tamperedReq,
+			wantErr: true,
+		},
+		{
+			name:    "multiple destinations",
+			reqData: multiReqData,
+			req:     multiReq,
+			wantErr: false,
+		},
+		{
+			name:    "multiple destinations count not match",
+			reqData: multiReqData,
+			req:     req,
+			wantErr: true,
+		},
+		{
+			name:    "not registered",
+			reqData: tamperedReqData,
+			req:     tamperedReq,
+			wantErr: true,
+		},
+		{
+			name:    "destination count not match",
+			reqData: reqData,
+			req:     noDestReq,
+			wantErr: true,
+		},
+		{
+			name:    "destination not match",
+			reqData: reqData,
+			req:     otherDestReq,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRequest(chainRid, "config", tt.txInput, tt.reqData, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantLoadErr, errors.Is(err, ErrLoadConfig))
+		})
+	}
+}
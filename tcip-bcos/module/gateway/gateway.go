@@ -0,0 +1,148 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+)
+
+// disabledKey 网关被中继网关禁用的状态，重启后保持禁用
+const disabledKey = "gateway#disabled"
+
+// Status 网关状态
+type Status struct {
+	Disabled bool   `json:"disabled"`
+	Reason   string `json:"reason,omitempty"`
+	Since    int64  `json:"since,omitempty"`
+}
+
+var (
+	lock      sync.Mutex
+	status    = &Status{}
+	enabledC  = make(chan struct{})
+	lastProbe time.Time
+)
+
+// Load 从数据库中加载网关状态，需要在数据库初始化之后调用
+//
+//	@return error
+func Load() error {
+	statusByte, err := db.Db.Get([]byte(disabledKey))
+	if err != nil {
+		return err
+	}
+	if len(statusByte) == 0 {
+		return nil
+	}
+	loaded := &Status{}
+	if err = json.Unmarshal(statusByte, loaded); err != nil {
+		return fmt.Errorf("unmarshal gateway status error: %s", err.Error())
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	status = loaded
+	return nil
+}
+
+// Disable 禁用网关，暂停向中继网关转发请求，已经是禁用状态时返回false
+//
+//	@param reason
+//	@return bool
+func Disable(reason string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if status.Disabled {
+		return false
+	}
+	status = &Status{
+		Disabled: true,
+		Reason:   reason,
+		Since:    time.Now().Unix(),
+	}
+	lastProbe = time.Now()
+	enabledC = make(chan struct{})
+	if statusByte, err := json.Marshal(status); err == nil {
+		_ = db.Db.Put([]byte(disabledKey), statusByte)
+	}
+	return true
+}
+
+// Enable 恢复网关，唤醒所有等待的请求，已经是启用状态时返回false
+//
+//	@return bool
+func Enable() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if !status.Disabled {
+		return false
+	}
+	status = &Status{}
+	close(enabledC)
+	_ = db.Db.Delete([]byte(disabledKey))
+	return true
+}
+
+// IsDisabled 网关是否被禁用
+//
+//	@return bool
+func IsDisabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return status.Disabled
+}
+
+// GetStatus 获取网关状态
+//
+//	@return Status
+func GetStatus() Status {
+	lock.Lock()
+	defer lock.Unlock()
+	return *status
+}
+
+// WaitEnabled 等待网关恢复，最多等待timeout，返回网关是否已经恢复
+//
+//	@param timeout
+//	@return bool
+func WaitEnabled(timeout time.Duration) bool {
+	lock.Lock()
+	if !status.Disabled {
+		lock.Unlock()
+		return true
+	}
+	c := enabledC
+	lock.Unlock()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-c:
+		return true
+	case <-timer.C:
+		return !IsDisabled()
+	}
+}
+
+// TryProbe 禁用状态下每个interval只允许一个请求发送到中继网关，用来探测网关是否被重新启用
+//
+//	@param interval
+//	@return bool
+func TryProbe(interval time.Duration) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	if !status.Disabled {
+		return true
+	}
+	if time.Since(lastProbe) < interval {
+		return false
+	}
+	lastProbe = time.Now()
+	return true
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	logger.InitLogConfig(log)
+	db.NewDbHandle()
+}
+
+func TestDisableEnable(t *testing.T) {
+	initTest()
+	assert.False(t, IsDisabled())
+	assert.True(t, WaitEnabled(time.Millisecond))
+	assert.True(t, TryProbe(time.Hour))
+
+	assert.True(t, Disable("disabled by relay"))
+	assert.False(t, Disable("again"))
+	assert.Equal(t, "disabled by relay", GetStatus().Reason)
+	assert.False(t, WaitEnabled(time.Millisecond))
+	assert.False(t, TryProbe(time.Hour))
+	assert.True(t, TryProbe(0))
+
+	// 重启后保持禁用
+	status = &Status{}
+	assert.Nil(t, Load())
+	assert.True(t, IsDisabled())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		Enable()
+	}()
+	assert.True(t, WaitEnabled(time.Minute))
+	assert.False(t, Enable())
+	status = &Status{}
+	assert.Nil(t, Load())
+	assert.False(t, IsDisabled())
+}
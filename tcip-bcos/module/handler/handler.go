@@ -15,18 +15,21 @@ import (
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
 
-	//tbis_event "chainmaker.org/chainmaker/tcip-chainmaker/v2/module/event"
-
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 
 	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/event"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/health"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/txrecord"
 	"go.uber.org/zap"
 
 	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
@@ -38,7 +41,15 @@ type Handler struct {
 	log *zap.SugaredLogger
 }
 
-const nilParam = "{}"
+const (
+	nilParam    = "{}"
+	pageKey     = "page"
+	pageSizeKey = "page_size"
+	// expectedVersionKey 更新跨链事件时期望的当前版本号
+	expectedVersionKey = "expected_version"
+	// versionKey 响应header中跨链事件配置的版本号
+	versionKey = "version"
+)
 
 // NewHandler 初始化handler模块
 //
@@ -68,24 +79,27 @@ func (h *Handler) CrossChainTry(ctx context.Context,
 		if err != nil {
 			h.log.Errorf("[CrossChainTry] Failed to execute cross-chain transaction: cross chain id: %s",
 				req.CrossChainId)
+			h.recordTx(txrecord.PhaseTry, req.CrossChainId, req.CrossChainMsg.ChainRid, nil, err)
 			return getCrossChainTryReturn(common.Code_INTERNAL_ERROR,
 				req.CrossChainId, req.CrossChainName, req.CrossChainFlag,
 				err.Error(), nil, nil)
 		}
 		txByte, _ := json.Marshal(tx)
 		blockNumber := strings.Replace(tx.BlockNumber, "0x", "", -1)
-		h, _ := strconv.ParseUint(blockNumber, 16, 64)
+		height, _ := strconv.ParseUint(blockNumber, 16, 64)
+		txContent := &common.TxContent{
+			TxId:        tx.Hash,
+			Tx:          txByte,
+			TxResult:    common.TxResultValue_TX_SUCCESS,
+			GatewayId:   conf.Config.BaseConfig.GatewayID,
+			ChainRid:    req.CrossChainMsg.ChainRid,
+			TxProve:     chain_client.ChainClientV1.GetTxProve(tx, req.CrossChainMsg.ChainRid),
+			BlockHeight: int64(height),
+		}
+		h.recordTx(txrecord.PhaseTry, req.CrossChainId, req.CrossChainMsg.ChainRid, txContent, nil)
 		return getCrossChainTryReturn(common.Code_GATEWAY_SUCCESS,
 			req.CrossChainId, req.CrossChainName,
-			req.CrossChainFlag, common.Code_GATEWAY_SUCCESS.String(), &common.TxContent{
-				TxId:        tx.Hash,
-				Tx:          txByte,
-				TxResult:    common.TxResultValue_TX_SUCCESS,
-				GatewayId:   conf.Config.BaseConfig.GatewayID,
-				ChainRid:    req.CrossChainMsg.ChainRid,
-				TxProve:     chain_client.ChainClientV1.GetTxProve(tx, req.CrossChainMsg.ChainRid),
-				BlockHeight: int64(h),
-			}, tryResult)
+			req.CrossChainFlag, common.Code_GATEWAY_SUCCESS.String(), txContent, tryResult)
 	default:
 		return getCrossChainTryReturn(common.Code_INVALID_PARAMETER,
 			req.CrossChainId, req.CrossChainName,
@@ -115,28 +129,28 @@ func (h *Handler) CrossChainConfirm(ctx context.Context,
 		var (
 			param string
 			err   error
-			err1  error
 		)
-		if req.CrossChainFlag == "tbis_event" {
-			//param, err1 = fillTbisResult(req.ConfirmInfo.Parameter, req.ConfirmInfo.ChainRid,
-			//	tbis_event.SubSuccess, tbis_event.SubSuccess, req.TryResult[0])
-			//if err1 != nil {
-			//	h.log.Errorf("[CrossChainConfirm] %s", err1.Error())
-			//	return &cross_chain.CrossChainConfirmResponse{
-			//		Code:    common.Code_INTERNAL_ERROR,
-			//		Message: err1.Error(),
-			//	}, nil
-			//}
-			h.log.Errorf("not support")
-		} else {
-			param, err1 = fillTryResult(req.ConfirmInfo.Parameter, req.TryResult, req.CrossType)
-			if err1 != nil {
-				h.log.Errorf("[CrossChainConfirm] %s", err1.Error())
-				return &cross_chain.CrossChainConfirmResponse{
-					Code:    common.Code_INTERNAL_ERROR,
-					Message: err.Error(),
-				}, nil
+		tc := newTemplateContext(req.TryResult, req.CrossChainId, req.CrossChainName, req.CrossChainFlag,
+			req.ConfirmInfo.ChainRid, req.ConfirmInfo.ContractName, req.ConfirmInfo.Method)
+		if req.CrossChainFlag == event.TbisFlag {
+			tryResult := ""
+			if len(req.TryResult) != 0 {
+				tryResult = req.TryResult[0]
 			}
+			param, err = fillTemplate(req.ConfirmInfo.Parameter, tc)
+			if err == nil {
+				param, err = fillTbisResult(param, req.ConfirmInfo.ChainRid,
+					event.SubSuccess, event.SubSuccess, tryResult)
+			}
+		} else {
+			param, err = fillTryResult(req.ConfirmInfo.Parameter, req.CrossType, tc)
+		}
+		if err != nil {
+			h.log.Errorf("[CrossChainConfirm] %s", err.Error())
+			return &cross_chain.CrossChainConfirmResponse{
+				Code:    common.Code_INTERNAL_ERROR,
+				Message: err.Error(),
+			}, nil
 		}
 		_, tx, err := chain_client.ChainClientV1.InvokeContract(req.ConfirmInfo.ChainRid,
 			req.ConfirmInfo.ContractName, req.ConfirmInfo.Method, req.ConfirmInfo.Abi,
@@ -144,6 +158,7 @@ func (h *Handler) CrossChainConfirm(ctx context.Context,
 		if err != nil {
 			h.log.Errorf("[CrossChainTry] Failed to execute cross-chain transaction: cross chain id: %s",
 				req.CrossChainId)
+			h.recordTx(txrecord.PhaseConfirm, req.CrossChainId, req.ConfirmInfo.ChainRid, nil, err)
 			return &cross_chain.CrossChainConfirmResponse{
 				Code:    common.Code_INTERNAL_ERROR,
 				Message: err.Error(),
@@ -151,19 +166,21 @@ func (h *Handler) CrossChainConfirm(ctx context.Context,
 		}
 		txByte, _ := json.Marshal(tx)
 		blockHeight, _ := strconv.Atoi(tx.BlockNumber)
+		txContent := &common.TxContent{
+			TxId:      tx.Hash,
+			Tx:        txByte,
+			TxResult:  common.TxResultValue_TX_SUCCESS,
+			GatewayId: conf.Config.BaseConfig.GatewayID,
+			ChainRid:  req.ConfirmInfo.ChainRid,
+			// 这里不验证不需要填
+			TxProve:     "",
+			BlockHeight: int64(blockHeight),
+		}
+		h.recordTx(txrecord.PhaseConfirm, req.CrossChainId, req.ConfirmInfo.ChainRid, txContent, nil)
 		return &cross_chain.CrossChainConfirmResponse{
-			Code:    common.Code_GATEWAY_SUCCESS,
-			Message: common.Code_GATEWAY_SUCCESS.String(),
-			TxContent: &common.TxContent{
-				TxId:      tx.Hash,
-				Tx:        txByte,
-				TxResult:  common.TxResultValue_TX_SUCCESS,
-				GatewayId: conf.Config.BaseConfig.GatewayID,
-				ChainRid:  req.ConfirmInfo.ChainRid,
-				// 这里不验证不需要填
-				TxProve:     "",
-				BlockHeight: int64(blockHeight),
-			},
+			Code:      common.Code_GATEWAY_SUCCESS,
+			Message:   common.Code_GATEWAY_SUCCESS.String(),
+			TxContent: txContent,
 		}, nil
 	default:
 		return &cross_chain.CrossChainConfirmResponse{
@@ -196,18 +213,20 @@ func (h *Handler) CrossChainCancel(
 		if req.CancelInfo.Parameter != "" {
 			param = req.CancelInfo.Parameter
 		}
-		//var err1 error
-		if req.CrossChainFlag == "tbis_event.TbisFlag" {
-			//param, err1 = fillTbisResult(req.CancelInfo.Parameter, req.CancelInfo.ChainRid,
-			//	tbis_event.SubFailed, tbis_event.SubFailed, "failed")
-			//if err1 != nil {
-			//	h.log.Errorf("[CrossChainConfirm] %s", err1.Error())
-			//	return &cross_chain.CrossChainCancelResponse{
-			//		Code:    common.Code_INTERNAL_ERROR,
-			//		Message: err1.Error(),
-			//	}, nil
-			//}
-			h.log.Errorf("not support")
+		// cancel时没有try结果，模板中只能引用事件字段
+		tc := newTemplateContext(nil, req.CrossChainId, req.CrossChainName, req.CrossChainFlag,
+			req.CancelInfo.ChainRid, req.CancelInfo.ContractName, req.CancelInfo.Method)
+		param, err := fillTemplate(param, tc)
+		if err == nil && req.CrossChainFlag == event.TbisFlag {
+			param, err = fillTbisResult(param, req.CancelInfo.ChainRid,
+				event.SubFailed, event.SubFailed, "failed")
+		}
+		if err != nil {
+			h.log.Errorf("[CrossChainCancel] %s", err.Error())
+			return &cross_chain.CrossChainCancelResponse{
+				Code:    common.Code_INTERNAL_ERROR,
+				Message: err.Error(),
+			}, nil
 		}
 		_, tx, err := chain_client.ChainClientV1.InvokeContract(req.CancelInfo.ChainRid,
 			req.CancelInfo.ContractName, req.CancelInfo.Method,
@@ -215,6 +234,7 @@ func (h *Handler) CrossChainCancel(
 		if err != nil {
 			h.log.Errorf("[CrossChainTry] Failed to execute cross-chain transaction: cross chain id: %s",
 				req.CrossChainId)
+			h.recordTx(txrecord.PhaseCancel, req.CrossChainId, req.CancelInfo.ChainRid, nil, err)
 			return &cross_chain.CrossChainCancelResponse{
 				Code:    common.Code_INTERNAL_ERROR,
 				Message: err.Error(),
@@ -222,19 +242,21 @@ func (h *Handler) CrossChainCancel(
 		}
 		txByte, _ := json.Marshal(tx)
 		blockHeight, _ := strconv.Atoi(tx.BlockNumber)
+		txContent := &common.TxContent{
+			TxId:      tx.Hash,
+			Tx:        txByte,
+			TxResult:  common.TxResultValue_TX_SUCCESS,
+			GatewayId: conf.Config.BaseConfig.GatewayID,
+			ChainRid:  req.CancelInfo.ChainRid,
+			// 这里不验证不需要填
+			TxProve:     "",
+			BlockHeight: int64(blockHeight),
+		}
+		h.recordTx(txrecord.PhaseCancel, req.CrossChainId, req.CancelInfo.ChainRid, txContent, nil)
 		return &cross_chain.CrossChainCancelResponse{
-			Code:    common.Code_GATEWAY_SUCCESS,
-			Message: common.Code_GATEWAY_SUCCESS.String(),
-			TxContent: &common.TxContent{
-				TxId:      tx.Hash,
-				Tx:        txByte,
-				TxResult:  common.TxResultValue_TX_SUCCESS,
-				GatewayId: conf.Config.BaseConfig.GatewayID,
-				ChainRid:  req.CancelInfo.ChainRid,
-				// 这里不验证不需要填
-				TxProve:     "",
-				BlockHeight: int64(blockHeight),
-			},
+			Code:      common.Code_GATEWAY_SUCCESS,
+			Message:   common.Code_GATEWAY_SUCCESS.String(),
+			TxContent: txContent,
 		}, nil
 	default:
 		return &cross_chain.CrossChainCancelResponse{
@@ -257,6 +279,12 @@ func (h *Handler) CrossChainEvent(ctx context.Context,
 
 	switch req.Version {
 	case common.Version_V1_0_0:
+		if req.CrossChainEvent == nil {
+			return &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_INVALID_PARAMETER,
+				Message: "CrossChainEvent is required",
+			}, nil
+		}
 		switch req.Operate {
 		case common.Operate_GET:
 			crossChainEvent, err := event.EventManagerV1.GetEvent(req.CrossChainEvent.CrossId,
@@ -268,6 +296,11 @@ func (h *Handler) CrossChainEvent(ctx context.Context,
 					Message: err.Error(),
 				}, nil
 			}
+			version, err := event.EventManagerV1.EventVersion(req.CrossChainEvent.CrossId,
+				req.CrossChainEvent.SrcChainRid, req.CrossChainEvent.ConfigConstractName)
+			if err == nil && version != 0 {
+				setVersionHeader(ctx, version)
+			}
 			return &cross_chain.CrossChainEventResponse{
 				Code:            common.Code_GATEWAY_SUCCESS,
 				Message:         common.Code_GATEWAY_SUCCESS.String(),
@@ -298,18 +331,35 @@ func (h *Handler) CrossChainEvent(ctx context.Context,
 				Code:    common.Code_GATEWAY_SUCCESS,
 				Message: common.Code_GATEWAY_SUCCESS.String(),
 			}, nil
-		//case common.Operate_UPDATE:
-		//err := event.EventManagerV1.SaveEvent(req.CrossChainEvent)
-		//if err != nil {
-		//	return &cross_chain.CrossChainEventResponse{
-		//		Code:    common.Code_INTERNAL_ERROR,
-		//		Message: err.Error(),
-		//	}, nil
-		//}
-		//return &cross_chain.CrossChainEventResponse{
-		//	Code:    common.Code_GATEWAY_SUCCESS,
-		//	Message: common.Code_GATEWAY_SUCCESS.String(),
-		//}, nil
+		case common.Operate_UPDATE:
+			version, err := event.EventManagerV1.UpdateEvent(req.CrossChainEvent, getExpectedVersion(ctx))
+			if err != nil {
+				return &cross_chain.CrossChainEventResponse{
+					Code:    common.Code_INTERNAL_ERROR,
+					Message: err.Error(),
+				}, nil
+			}
+			setVersionHeader(ctx, version)
+			return &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_GATEWAY_SUCCESS,
+				Message: common.Code_GATEWAY_SUCCESS.String(),
+			}, nil
+		case event.OperateListExtension:
+			// 响应中只有一个CrossChainEvent字段，分页结果以json的形式放在Message中
+			page, pageSize := getPageParam(ctx)
+			eventPage, err := event.EventManagerV1.ListEvent(req.CrossChainEvent.SrcChainRid,
+				req.CrossChainEvent.ConfigConstractName, page, pageSize)
+			if err != nil {
+				return &cross_chain.CrossChainEventResponse{
+					Code:    common.Code_INTERNAL_ERROR,
+					Message: err.Error(),
+				}, nil
+			}
+			eventPageByte, _ := json.Marshal(eventPage)
+			return &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_GATEWAY_SUCCESS,
+				Message: string(eventPageByte),
+			}, nil
 		default:
 			return &cross_chain.CrossChainEventResponse{
 				Code:    common.Code_INVALID_PARAMETER,
@@ -379,11 +429,39 @@ func (h *Handler) IsCrossChainSuccess(
 func (h *Handler) PingPong(ctx context.Context, req *emptypb.Empty) (*cross_chain.PingPongResponse, error) {
 	//h.printRequest(ctx, "PingPong", fmt.Sprintf("%+v", req))
 
+	// 网关被中继网关禁用时暂停转发跨链请求，对外报告为不可用，链的状态使用后台刷新的缓存
 	return &cross_chain.PingPongResponse{
-		ChainOk: chain_client.ChainClientV1.CheckChain(),
+		ChainOk: !gateway.IsDisabled() && health.Get().ChainOk,
 	}, nil
 }
 
+// recordTx 保存跨链交易记录，保存失败不影响跨链
+//
+//	@receiver h
+//	@param phase
+//	@param crossChainId
+//	@param chainRid
+//	@param txContent 交易失败时为nil
+//	@param err
+func (h *Handler) recordTx(phase, crossChainId, chainRid string, txContent *common.TxContent, err error) {
+	record := &txrecord.Record{
+		CrossChainId: crossChainId,
+		Phase:        phase,
+		ChainRid:     chainRid,
+		Success:      err == nil,
+	}
+	if err != nil {
+		record.Message = err.Error()
+	}
+	if txContent != nil {
+		record.TxId = txContent.TxId
+		record.BlockHeight = txContent.BlockHeight
+	}
+	if err = txrecord.Put(record); err != nil {
+		h.log.Warnf("[recordTx] save tx record error: %s", err.Error())
+	}
+}
+
 // printRequest 打印请求信息
 //
 //	@receiver h
@@ -403,6 +481,50 @@ func (h *Handler) printRequest(ctx context.Context, method, request string) {
 	h.log.Infof("[%s]: |%s|%s", method, addr, request)
 }
 
+// getPageParam 从请求的metadata中获取分页参数，restful接口通过Grpc-Metadata-Page请求头传递
+//
+//	@param ctx
+//	@return int
+//	@return int
+func getPageParam(ctx context.Context) (int, int) {
+	var page, pageSize int
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return page, pageSize
+	}
+	if values := md.Get(pageKey); len(values) != 0 {
+		page, _ = strconv.Atoi(values[0])
+	}
+	if values := md.Get(pageSizeKey); len(values) != 0 {
+		pageSize, _ = strconv.Atoi(values[0])
+	}
+	return page, pageSize
+}
+
+// getExpectedVersion 从请求的metadata中获取期望的跨链事件配置版本号，没有时返回0
+//
+//	@param ctx
+//	@return uint64
+func getExpectedVersion(ctx context.Context) uint64 {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	var version uint64
+	if values := md.Get(expectedVersionKey); len(values) != 0 {
+		version, _ = strconv.ParseUint(values[0], 10, 64)
+	}
+	return version
+}
+
+// setVersionHeader 在响应header中返回跨链事件配置的版本号，不是grpc请求时忽略
+//
+//	@param ctx
+//	@param version
+func setVersionHeader(ctx context.Context, version uint64) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(versionKey, strconv.FormatUint(version, 10)))
+}
+
 // getCrossChainTryReturn 创建crosschaintry返回值
 //
 //	@param code
@@ -430,57 +552,96 @@ func getCrossChainTryReturn(
 	}, nil
 }
 
-// fillTryResult 填充跨链查询内容
+// fillTryResult 填充跨链查询内容，参数是模板时按模板渲染，否则按顺序替换TRY_RESULT
 //
 //	@param param
-//	@param tryResult
 //	@param crossType
+//	@param tc 模板可以引用的值
 //	@return string
 //	@return error
-func fillTryResult(param string, tryResult []string, crossType common.CrossType) (string, error) {
+func fillTryResult(param string, crossType common.CrossType, tc *templateContext) (string, error) {
 	if param == "" {
 		return nilParam, nil
 	}
+	if isTemplate(param) {
+		return tc.renderTemplate(param)
+	}
 	if crossType == common.CrossType_INVOKE {
 		return param, nil
 	}
 	tryResultCount := strings.Count(param, common.TryResult_TRY_RESULT.String())
-	if len(tryResult) == 0 || tryResultCount == 0 {
+	if len(tc.tryResult) == 0 || tryResultCount == 0 {
 		return param, nil
 	}
-	if len(tryResult) != tryResultCount {
+	if len(tc.tryResult) != tryResultCount {
 		return "", fmt.Errorf("\"%s\" count != len(TryResult), please update event config",
 			common.TryResult_TRY_RESULT.String())
 	}
-	param = strings.Replace(param, common.TryResult_TRY_RESULT.String(), "%s", -1)
-	paramData := make([]interface{}, len(tryResult))
-	for j, v := range tryResult {
-		paramData[j] = v
+	return replaceTryResult(param, common.TryResult_TRY_RESULT.String(), tc.tryResult), nil
+}
+
+// fillTemplate 参数是模板时按模板渲染
+//
+//	@param param
+//	@param tc
+//	@return string
+//	@return error
+func fillTemplate(param string, tc *templateContext) (string, error) {
+	if !isTemplate(param) {
+		return param, nil
+	}
+	return tc.renderTemplate(param)
+}
+
+// newTemplateContext 构建参数模板可以引用的值
+//
+//	@param tryResult
+//	@param crossChainId
+//	@param crossChainName
+//	@param crossChainFlag
+//	@param chainRid
+//	@param contractName
+//	@param method
+//	@return *templateContext
+func newTemplateContext(tryResult []string, crossChainId, crossChainName, crossChainFlag,
+	chainRid, contractName, method string) *templateContext {
+	return &templateContext{
+		tryResult: tryResult,
+		eventFields: map[string]string{
+			"cross_chain_id":   crossChainId,
+			"cross_chain_name": crossChainName,
+			"cross_chain_flag": crossChainFlag,
+			"chain_rid":        chainRid,
+			"contract_name":    contractName,
+			"method":           method,
+		},
 	}
-	param = fmt.Sprintf(param, paramData...)
-	return param, nil
 }
 
-// fillTbisResult 填充tbis执行结果
-//  @param kvJsonStr
-//  @param chainRid
-//  @param proveStatus
-//  @param contractStatus
-//  @param contractResult
-//  @return string
-//  @return error
-//func fillTbisResult(param, chainRid string,
-//	proveStatus, contractStatus int, contractResult string) (string, error) {
-//	res := tbis_event.GetCommitParam(chainRid, proveStatus, contractStatus, contractResult)
-//	if param == "" {
-//		return fmt.Sprintf("[\"%s\"]", res), nil
-//	}
-//	paramArr := make([]interface{}, 0)
-//	err := json.Unmarshal([]byte(param), &paramArr)
-//	if err != nil {
-//		return "", fmt.Errorf("unmarshal param error: %s", err.Error())
-//	}
-//	paramArr[0] = res
-//	resStr, _ := json.Marshal(paramArr)
-//	return string(resStr), nil
-//}
+// fillTbisResult 填充tbis执行结果，tbis的commit参数固定放在参数列表的第一个
+//
+//	@param param
+//	@param chainRid
+//	@param proveStatus
+//	@param contractStatus
+//	@param contractResult
+//	@return string
+//	@return error
+func fillTbisResult(param, chainRid string,
+	proveStatus, contractStatus int, contractResult string) (string, error) {
+	res := event.GetCommitParam(chainRid, proveStatus, contractStatus, contractResult)
+	paramArr := make([]interface{}, 0)
+	if param != "" && param != nilParam {
+		err := json.Unmarshal([]byte(param), &paramArr)
+		if err != nil {
+			return "", fmt.Errorf("unmarshal param error: %s", err.Error())
+		}
+	}
+	if len(paramArr) == 0 {
+		paramArr = append(paramArr, res)
+	} else {
+		paramArr[0] = res
+	}
+	resStr, _ := json.Marshal(paramArr)
+	return string(resStr), nil
+}
@@ -21,6 +21,8 @@ import (
 
 	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/event"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/health"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
@@ -48,13 +50,15 @@ func testInit() {
 	conf.Config.BaseConfig = &conf.BaseConfig{
 		GatewayID:    "0",
 		GatewayName:  "test",
-		Address:      "https://127.0.0.1:19999",
-		ServerName:   "chainmaker.org",
-		Tlsca:        "../../config/cert/client/ca.crt",
-		ClientKey:    "../../config/cert/client/client.key",
-		ClientCert:   "../../config/cert/client/client.crt",
 		TxVerifyType: "notneed",
-		CallType:     "grpc",
+	}
+	conf.Config.Relay = &conf.Relay{
+		Address:    "127.0.0.1:19999",
+		ServerName: "chainmaker.org",
+		Tlsca:      "../../config/cert/client/ca.crt",
+		ClientKey:  "../../config/cert/client/client.key",
+		ClientCert: "../../config/cert/client/client.crt",
+		CallType:   "grpc",
 	}
 	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
 	chainConfigByte, _ := proto.Marshal(&common.BcosConfig{
@@ -134,6 +138,62 @@ func TestHandler_CrossChainCancel(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "3",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainCancelRequest{
+					Version:        common.Version_V1_0_0,
+					CrossChainFlag: event.TbisFlag,
+					CancelInfo: &common.CancelInfo{
+						ChainRid:     "chain1",
+						ContractName: "aaa",
+						Method:       "bbb",
+						Parameter:    "{\"a\":\"a\"}",
+					},
+				},
+			},
+			want: &cross_chain.CrossChainCancelResponse{
+				Code:    common.Code_INTERNAL_ERROR,
+				Message: "unmarshal param error: json: cannot unmarshal object into Go value of type []interface {}",
+			},
+			wantErr: false,
+		},
+		{
+			name: "4",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainCancelRequest{
+					Version:        common.Version_V1_0_0,
+					CrossChainFlag: event.TbisFlag,
+					CancelInfo: &common.CancelInfo{
+						ChainRid:     "chain1",
+						ContractName: "aaa",
+						Method:       "bbb",
+					},
+				},
+			},
+			want: &cross_chain.CrossChainCancelResponse{
+				Code:    common.Code_GATEWAY_SUCCESS,
+				Message: common.Code_GATEWAY_SUCCESS.String(),
+				TxContent: &common.TxContent{
+					TxId:        tx.Hash,
+					Tx:          txByte,
+					TxResult:    common.TxResultValue_TX_SUCCESS,
+					GatewayId:   conf.Config.BaseConfig.GatewayID,
+					ChainRid:    "chain1",
+					TxProve:     "",
+					BlockHeight: 10,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -219,6 +279,40 @@ func TestHandler_CrossChainConfirm(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "3",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainConfirmRequest{
+					Version:        common.Version_V1_0_0,
+					CrossChainFlag: event.TbisFlag,
+					TryResult:      []string{"123"},
+					ConfirmInfo: &common.ConfirmInfo{
+						ChainRid:     "chain1",
+						ContractName: "aaa",
+						Method:       "bbb",
+						Parameter:    "[\"\",\"a\"]",
+					},
+				},
+			},
+			want: &cross_chain.CrossChainConfirmResponse{
+				Code:    common.Code_GATEWAY_SUCCESS,
+				Message: common.Code_GATEWAY_SUCCESS.String(),
+				TxContent: &common.TxContent{
+					TxId:        tx.Hash,
+					Tx:          txByte,
+					TxResult:    common.TxResultValue_TX_SUCCESS,
+					GatewayId:   conf.Config.BaseConfig.GatewayID,
+					ChainRid:    "chain1",
+					TxProve:     "",
+					BlockHeight: 10,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -389,6 +483,8 @@ func TestHandler_IsCrossChainSuccess(t *testing.T) {
 
 func TestHandler_PingPong(t *testing.T) {
 	testInit()
+	// 链的状态由后台刷新，PingPong只读取缓存
+	health.Refresh()
 	type fields struct {
 		log *zap.SugaredLogger
 	}
@@ -486,3 +582,190 @@ func TestHandler_TxVerify(t *testing.T) {
 		})
 	}
 }
+
+func Test_fillTbisResult(t *testing.T) {
+	type args struct {
+		param          string
+		chainRid       string
+		proveStatus    int
+		contractStatus int
+		contractResult string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "1",
+			args: args{
+				param:          "",
+				chainRid:       "chain1",
+				proveStatus:    event.SubSuccess,
+				contractStatus: event.SubSuccess,
+				contractResult: "123",
+			},
+			want: "[\"{\\\"chain_rid\\\":\\\"chain1\\\",\\\"prove_status\\\":0," +
+				"\\\"contract_status\\\":0,\\\"contract_result\\\":\\\"123\\\"}\"]",
+			wantErr: false,
+		},
+		{
+			name: "2",
+			args: args{
+				param:          "[\"\",\"a\"]",
+				chainRid:       "chain1",
+				proveStatus:    event.SubFailed,
+				contractStatus: event.SubFailed,
+				contractResult: "failed",
+			},
+			want: "[\"{\\\"chain_rid\\\":\\\"chain1\\\",\\\"prove_status\\\":1," +
+				"\\\"contract_status\\\":1,\\\"contract_result\\\":\\\"failed\\\"}\",\"a\"]",
+			wantErr: false,
+		},
+		{
+			name: "3",
+			args: args{
+				param:          "{\"a\":\"a\"}",
+				chainRid:       "chain1",
+				proveStatus:    event.SubFailed,
+				contractStatus: event.SubFailed,
+				contractResult: "failed",
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fillTbisResult(tt.args.param, tt.args.chainRid, tt.args.proveStatus,
+				tt.args.contractStatus, tt.args.contractResult)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fillTbisResult() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("fillTbisResult() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_CrossChainEvent(t *testing.T) {
+	testInit()
+	event.InitEventManager()
+	emptyPage, _ := json.Marshal(&event.EventPage{
+		Total:            0,
+		Page:             1,
+		PageSize:         20,
+		CrossChainEvents: make([]*common.NewCrossChain, 0),
+	})
+	type fields struct {
+		log *zap.SugaredLogger
+	}
+	type args struct {
+		ctx context.Context
+		req *cross_chain.CrossChainEventRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *cross_chain.CrossChainEventResponse
+		wantErr bool
+	}{
+		{
+			name: "1",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainEventRequest{
+					Version: common.Version_V1_0_0,
+					Operate: common.Operate_UPDATE,
+				},
+			},
+			want: &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_INVALID_PARAMETER,
+				Message: "CrossChainEvent is required",
+			},
+			wantErr: false,
+		},
+		{
+			name: "2",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainEventRequest{
+					Version:         common.Version_V1_0_0,
+					Operate:         common.Operate_UPDATE,
+					CrossChainEvent: &common.NewCrossChain{},
+				},
+			},
+			want: &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_INTERNAL_ERROR,
+				Message: "CrossId is required",
+			},
+			wantErr: false,
+		},
+		{
+			name: "3",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainEventRequest{
+					Version: common.Version_V1_0_0,
+					Operate: event.OperateListExtension,
+					CrossChainEvent: &common.NewCrossChain{
+						SrcChainRid:         "chain1",
+						ConfigConstractName: "config",
+					},
+				},
+			},
+			want: &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_GATEWAY_SUCCESS,
+				Message: string(emptyPage),
+			},
+			wantErr: false,
+		},
+		{
+			name: "4",
+			fields: fields{
+				log: logger.GetLogger(logger.ModuleHandler),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &cross_chain.CrossChainEventRequest{
+					Version:         common.Version_V1_0_0,
+					Operate:         common.Operate(10),
+					CrossChainEvent: &common.NewCrossChain{},
+				},
+			},
+			want: &cross_chain.CrossChainEventResponse{
+				Code:    common.Code_INVALID_PARAMETER,
+				Message: "unsupported operate",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				log: tt.fields.log,
+			}
+			got, err := h.CrossChainEvent(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CrossChainEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CrossChainEvent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// 参数模板
+//
+// 以template:开头的confirm和cancel参数是模板，去掉前缀后可以使用${...}占位符，
+// 不以template:开头的参数原样使用，其中的${不做处理：
+//   - ${try[1]} 第二个try结果
+//   - ${try[0].data.list[2]} try结果是json时按路径取值
+//   - ${event.cross_chain_id} 跨链事件的字段，见eventFields
+//   - ${= try[0] * 2 + 1} govaluate表达式，表达式中可以使用上面的引用，数字字符串按数字参与计算
+//
+// 占位符在json字符串内时替换为转义后的字符串内容，在字符串外时替换为json值，引用不存在的值时报错，
+// 模板中需要原样输出${时写作$${
+const (
+	templatePrefix = "template:"
+	templateStart  = "${"
+	templateEscape = "$${"
+	templateEnd    = "}"
+	exprPrefix     = "="
+	// exprParamFormat 表达式中引用替换成的参数名，用govaluate的[]转义语法，不会和表达式中的标识符冲突
+	exprParamFormat = "[$ref%d]"
+)
+
+var (
+	// refRegexp 模板中的引用: try[N]后面跟.key或者[N]路径，或者event.field
+	refRegexp = regexp.MustCompile(`try\[(\d+)\]((?:\.[A-Za-z_][A-Za-z0-9_]*|\[\d+\])*)|event\.([a-z_]+)`)
+	// pathRegexp json路径中的一段
+	pathRegexp = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)|\[(\d+)\]`)
+)
+
+// templateContext 参数模板可以引用的值
+type templateContext struct {
+	tryResult []string
+	// eventFields 跨链事件的字段: cross_chain_id, cross_chain_name, cross_chain_flag,
+	// chain_rid, contract_name, method
+	eventFields map[string]string
+}
+
+// isTemplate 参数是否是模板，必须以template:开头明确声明
+//
+//	@param param
+//	@return bool
+func isTemplate(param string) bool {
+	return strings.HasPrefix(param, templatePrefix)
+}
+
+// renderTemplate 渲染参数模板
+//
+//	@receiver c
+//	@param template 以template:开头的参数
+//	@return string
+//	@return error
+func (c *templateContext) renderTemplate(template string) (string, error) {
+	var (
+		res      strings.Builder
+		inString bool
+		escaped  bool
+	)
+	template = strings.TrimPrefix(template, templatePrefix)
+	for i := 0; i < len(template); i++ {
+		if strings.HasPrefix(template[i:], templateEscape) {
+			res.WriteString(templateStart)
+			i += len(templateEscape) - 1
+			escaped = false
+			continue
+		}
+		if strings.HasPrefix(template[i:], templateStart) {
+			end := strings.Index(template[i+len(templateStart):], templateEnd)
+			if end < 0 {
+				return "", fmt.Errorf("unclosed placeholder in template at %d", i)
+			}
+			inner := strings.TrimSpace(template[i+len(templateStart) : i+len(templateStart)+end])
+			value, err := c.evaluate(inner)
+			if err != nil {
+				return "", err
+			}
+			encoded, err := encodeTemplateValue(value, inString)
+			if err != nil {
+				return "", err
+			}
+			res.WriteString(encoded)
+			i += len(templateStart) + end + len(templateEnd) - 1
+			escaped = false
+			continue
+		}
+		ch := template[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && ch == '\\':
+			escaped = true
+		case ch == '"':
+			inString = !inString
+		}
+		res.WriteByte(ch)
+	}
+	return res.String(), nil
+}
+
+// evaluate 计算占位符的值
+//
+//	@receiver c
+//	@param inner 占位符的内容
+//	@return interface{}
+//	@return error
+func (c *templateContext) evaluate(inner string) (interface{}, error) {
+	if !strings.HasPrefix(inner, exprPrefix) {
+		if loc := refRegexp.FindStringIndex(inner); loc == nil || loc[0] != 0 || loc[1] != len(inner) {
+			return nil, fmt.Errorf("invalid placeholder: ${%s}", inner)
+		}
+		return c.resolve(inner)
+	}
+	parameters := make(map[string]interface{})
+	var resolveErr error
+	expr := replaceRefs(strings.TrimPrefix(inner, exprPrefix), func(ref string) string {
+		value, err := c.resolve(ref)
+		if err != nil {
+			resolveErr = err
+			return ref
+		}
+		name := fmt.Sprintf(exprParamFormat, len(parameters))
+		parameters[strings.Trim(name, "[]")] = exprValue(value)
+		return name
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	expression, err := govaluate.NewEvaluableExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression ${%s}: %s", inner, err.Error())
+	}
+	value, err := expression.Evaluate(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate expression ${%s} error: %s", inner, err.Error())
+	}
+	return value, nil
+}
+
+// replaceRefs 替换表达式中的引用，字符串字面量中的内容和其他标识符中间的部分不替换
+//
+//	@param expr
+//	@param replace
+//	@return string
+func replaceRefs(expr string, replace func(ref string) string) string {
+	var res strings.Builder
+	replaceCode := func(code string) {
+		last := 0
+		for _, loc := range refRegexp.FindAllStringIndex(code, -1) {
+			// 例如mytry[0]中的try[0]是标识符的一部分
+			if loc[0] > 0 && isIdentChar(code[loc[0]-1]) {
+				continue
+			}
+			res.WriteString(code[last:loc[0]])
+			res.WriteString(replace(code[loc[0]:loc[1]]))
+			last = loc[1]
+		}
+		res.WriteString(code[last:])
+	}
+	start := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case quote != 0 && ch == '\\':
+			i++
+		case quote != 0 && ch == quote:
+			res.WriteString(expr[start : i+1])
+			start = i + 1
+			quote = 0
+		case quote == 0 && (ch == '\'' || ch == '"'):
+			replaceCode(expr[start:i])
+			start = i
+			quote = ch
+		}
+	}
+	if quote != 0 {
+		// 没有闭合的字符串原样保留，由govaluate报错
+		res.WriteString(expr[start:])
+	} else {
+		replaceCode(expr[start:])
+	}
+	return res.String()
+}
+
+// isIdentChar 是否是标识符中的字符
+//
+//	@param ch
+//	@return bool
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch == '$' || ch == '.' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') ||
+		('0' <= ch && ch <= '9')
+}
+
+// resolve 获取引用的值
+//
+//	@receiver c
+//	@param ref
+//	@return interface{}
+//	@return error
+func (c *templateContext) resolve(ref string) (interface{}, error) {
+	match := refRegexp.FindStringSubmatch(ref)
+	if match[3] != "" {
+		value, ok := c.eventFields[match[3]]
+		if !ok {
+			return nil, fmt.Errorf("template refers to missing value: %s", ref)
+		}
+		return value, nil
+	}
+	index, _ := strconv.Atoi(match[1])
+	if index >= len(c.tryResult) {
+		return nil, fmt.Errorf("template refers to missing value: %s, only %d try results",
+			ref, len(c.tryResult))
+	}
+	if match[2] == "" {
+		return c.tryResult[index], nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(c.tryResult[index]))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("template refers to missing value: %s, try result is not json", ref)
+	}
+	for _, segment := range pathRegexp.FindAllStringSubmatch(match[2], -1) {
+		var ok bool
+		if segment[1] != "" {
+			var object map[string]interface{}
+			if object, ok = value.(map[string]interface{}); ok {
+				value, ok = object[segment[1]]
+			}
+		} else {
+			var array []interface{}
+			if array, ok = value.([]interface{}); ok {
+				i, _ := strconv.Atoi(segment[2])
+				if ok = i < len(array); ok {
+					value = array[i]
+				}
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("template refers to missing value: %s", ref)
+		}
+	}
+	return value, nil
+}
+
+// exprValue 转换为表达式可以计算的值，数字字符串转为数字
+//
+//	@param value
+//	@return interface{}
+func exprValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// encodeTemplateValue 占位符的值转为json，在json字符串内时只返回转义后的内容
+//
+//	@param value
+//	@param inString
+//	@return string
+//	@return error
+func encodeTemplateValue(value interface{}, inString bool) (string, error) {
+	if inString {
+		if _, ok := value.(string); !ok {
+			raw, err := marshalTemplateValue(value)
+			if err != nil {
+				return "", err
+			}
+			value = raw
+		}
+		quoted, err := marshalTemplateValue(value)
+		if err != nil {
+			return "", err
+		}
+		return quoted[1 : len(quoted)-1], nil
+	}
+	return marshalTemplateValue(value)
+}
+
+// marshalTemplateValue json序列化，不转义html字符
+//
+//	@param value
+//	@return string
+//	@return error
+func marshalTemplateValue(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("marshal template value error: %s", err.Error())
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// replaceTryResult 把旧格式的TRY_RESULT按顺序替换为try结果，不经过fmt.Sprintf，参数中的%不受影响
+//
+//	@param param
+//	@param placeholder
+//	@param tryResult 数量和placeholder出现的次数相同
+//	@return string
+func replaceTryResult(param, placeholder string, tryResult []string) string {
+	parts := strings.Split(param, placeholder)
+	var res strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			res.WriteString(tryResult[i-1])
+		}
+		res.WriteString(part)
+	}
+	return res.String()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handler
+
+import (
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+)
+
+func Test_fillTryResult(t *testing.T) {
+	tc := newTemplateContext([]string{"5", `{"data":{"list":["a","b\"c"]},"amount":3}`, "x\"y"},
+		"cross1", "name", "flag", "chain1", "contract", "confirm")
+	tests := []struct {
+		name      string
+		param     string
+		crossType common.CrossType
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:  "empty",
+			param: "",
+			want:  nilParam,
+		},
+		{
+			name:      "legacy with percent",
+			param:     `["TRY_RESULT","100%","TRY_RESULT",TRY_RESULT]`,
+			crossType: common.CrossType_QUERY,
+			want:      `["5","100%","{"data":{"list":["a","b\"c"]},"amount":3}",x"y]`,
+		},
+		{
+			name:      "legacy count not match",
+			param:     `["TRY_RESULT"]`,
+			crossType: common.CrossType_QUERY,
+			wantErr:   true,
+		},
+		{
+			name:      "legacy invoke",
+			param:     `["TRY_RESULT"]`,
+			crossType: common.CrossType_INVOKE,
+			want:      `["TRY_RESULT"]`,
+		},
+		{
+			name:  "index and escape",
+			param: `template:["${try[2]}", ${try[2]}, "100%"]`,
+			want:  `["x\"y", "x\"y", "100%"]`,
+		},
+		{
+			name:  "json path",
+			param: `template:["${try[1].data.list[1]}", ${try[1].amount}, ${try[1].data}]`,
+			want:  `["b\"c", 3, {"list":["a","b\"c"]}]`,
+		},
+		{
+			name:  "event field",
+			param: `template:["${event.cross_chain_id}","${ event.chain_rid }"]`,
+			want:  `["cross1","chain1"]`,
+		},
+		{
+			name:  "expression",
+			param: `template:[${= try[0] * 2 + try[1].amount}, "${= event.cross_chain_flag == 'flag'}"]`,
+			want:  `[13, "true"]`,
+		},
+		{
+			name:  "not opted in",
+			param: `["${try[0]}", "100%"]`,
+			want:  `["${try[0]}", "100%"]`,
+		},
+		{
+			name:  "escaped placeholder",
+			param: `template:["$${try[0]}", "${try[0]}"]`,
+			want:  `["${try[0]}", "5"]`,
+		},
+		{
+			name:  "reference in string literal",
+			param: `template:[${= event.method + ' try[0] ' + "event.method"}]`,
+			want:  `["confirm try[0] event.method"]`,
+		},
+		{
+			name:    "identifier not replaced",
+			param:   `template:[${= try[0] + ref0}]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing try result",
+			param:   `template:["${try[3]}"]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing json path",
+			param:   `template:["${try[1].data.list[5]}"]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing event field",
+			param:   `template:["${event.unknown}"]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing value in expression",
+			param:   `template:[${= try[9] + 1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid placeholder",
+			param:   `template:["${foo}"]`,
+			wantErr: true,
+		},
+		{
+			name:    "unclosed placeholder",
+			param:   `template:["${try[0]"]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fillTryResult(tt.param, tt.crossType, tc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fillTryResult() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("fillTryResult() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
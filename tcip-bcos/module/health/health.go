@@ -0,0 +1,303 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/certwatch"
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
+)
+
+const (
+	// LivenessPath 存活探测接口
+	LivenessPath = "/healthz"
+	// ReadinessPath 就绪探测接口
+	ReadinessPath = "/readyz"
+
+	defaultInterval = 10 * time.Second
+	// staleIntervals 超过多少个刷新间隔没有刷新时认为状态已经过期
+	staleIntervals = 3
+	// probeKey 检查数据库是否可用时读取的key
+	probeKey = "health#probe"
+)
+
+// Status 网关的健康状态
+type Status struct {
+	Ready bool `json:"ready"`
+	// ChainOk 全部链都可以连通
+	ChainOk bool `json:"chain_ok"`
+	// Reasons 没有就绪的原因
+	Reasons   []string                    `json:"reasons,omitempty"`
+	UpdatedAt int64                       `json:"updated_at"`
+	Chains    []*chain_client.ChainStatus `json:"chains"`
+	Relay     *RelayStatus                `json:"relay"`
+	Db        *DbStatus                   `json:"db"`
+	// Certs 证书有效期，后台检查的结果
+	Certs []*certwatch.CertStatus `json:"certs,omitempty"`
+}
+
+// RelayStatus 中继网关的健康状态
+type RelayStatus struct {
+	// Reachable 至少有一个中继网关实例没有熔断
+	Reachable bool               `json:"reachable"`
+	Disabled  bool               `json:"disabled"`
+	Reason    string             `json:"reason,omitempty"`
+	Endpoints []*endpoint.Status `json:"endpoints,omitempty"`
+}
+
+// DbStatus 数据库的健康状态
+type DbStatus struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+var (
+	lock      sync.Mutex
+	current   *Status
+	startOnce sync.Once
+)
+
+// Start 启动后台刷新，需要在链客户端和request模块初始化之后调用，
+// 第一次刷新完成前报告未就绪，探测接口不会等待刷新
+func Start() {
+	startOnce.Do(func() {
+		lock.Lock()
+		if current == nil {
+			current = pendingStatus()
+		}
+		lock.Unlock()
+		go run(getInterval())
+	})
+}
+
+// run 定时刷新健康状态
+//
+//	@param interval
+func run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	Refresh()
+	for range ticker.C {
+		Refresh()
+	}
+}
+
+// Refresh 重新检查全部状态并缓存，会访问链，不要在请求处理中直接调用
+//
+//	@return *Status
+func Refresh() *Status {
+	status := collect(conf.Config.Health)
+	lock.Lock()
+	defer lock.Unlock()
+	current = status
+	return status
+}
+
+// Get 获取缓存的健康状态，不会访问链，还没有刷新过时返回未就绪，返回的状态不能修改
+//
+//	@return *Status
+func Get() *Status {
+	lock.Lock()
+	defer lock.Unlock()
+	if current == nil {
+		current = pendingStatus()
+	}
+	return current
+}
+
+// pendingStatus 第一次刷新完成前的状态，未就绪，只检查本地数据库
+//
+//	@return *Status
+func pendingStatus() *Status {
+	return &Status{
+		Reasons:   []string{"health status is not collected yet"},
+		UpdatedAt: time.Now().Unix(),
+		Chains:    make([]*chain_client.ChainStatus, 0),
+		Relay:     &RelayStatus{},
+		Db:        dbStatus(),
+	}
+}
+
+// IsStale 状态是否已经超过几个刷新间隔没有更新，说明后台刷新卡住了
+//
+//	@param status
+//	@return bool
+func IsStale(status *Status) bool {
+	return time.Since(time.Unix(status.UpdatedAt, 0)) > staleIntervals*getInterval()
+}
+
+// collect 检查链、中继网关和数据库的状态
+//
+//	@param config
+//	@return *Status
+func collect(config *conf.HealthConfig) *Status {
+	status := &Status{
+		ChainOk:   true,
+		UpdatedAt: time.Now().Unix(),
+		Chains:    make([]*chain_client.ChainStatus, 0),
+		Relay:     relayStatus(),
+		Db:        dbStatus(),
+		Certs:     certwatch.Status(),
+	}
+	if chain_client.ChainClientV1 == nil {
+		status.ChainOk = false
+		status.Reasons = append(status.Reasons, "chain client is not initialized")
+	} else {
+		status.Chains = chain_client.ChainClientV1.ChainStatus()
+	}
+	var maxHeaderLag int64
+	if config != nil {
+		maxHeaderLag = config.MaxHeaderLag
+	}
+	for _, chain := range status.Chains {
+		if !chain.Reachable {
+			status.ChainOk = false
+			status.Reasons = append(status.Reasons, fmt.Sprintf("chain %s is unreachable", chain.ChainRid))
+		}
+		if !chain.Subscribed {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("event subscription of chain %s is down", chain.ChainRid))
+		}
+		if maxHeaderLag > 0 && chain.HeaderSyncLag > maxHeaderLag {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("block header sync of chain %s is %d blocks behind",
+				chain.ChainRid, chain.HeaderSyncLag))
+		}
+	}
+	if !status.Relay.Reachable {
+		status.Reasons = append(status.Reasons, "no relay gateway endpoint is available")
+	}
+	if status.Relay.Disabled {
+		status.Reasons = append(status.Reasons, "gateway is disabled by relay gateway: "+status.Relay.Reason)
+	}
+	if !status.Db.Ok {
+		status.Reasons = append(status.Reasons, "db is unavailable: "+status.Db.Error)
+	}
+	for _, cert := range status.Certs {
+		if cert.Error != "" {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("certificate %s is unreadable: %s", cert.Name, cert.Error))
+		} else if cert.Expired {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("certificate %s has expired", cert.Name))
+		}
+	}
+	status.Ready = len(status.Reasons) == 0
+	return status
+}
+
+// relayStatus 中继网关实例的熔断状态和网关的禁用状态，不会主动访问中继网关
+//
+//	@return *RelayStatus
+func relayStatus() *RelayStatus {
+	gatewayStatus := gateway.GetStatus()
+	status := &RelayStatus{
+		Reachable: true,
+		Disabled:  gatewayStatus.Disabled,
+		Reason:    gatewayStatus.Reason,
+	}
+	if request.RequestV1 == nil {
+		return status
+	}
+	status.Endpoints = request.RequestV1.EndpointStatus()
+	if len(status.Endpoints) == 0 {
+		return status
+	}
+	status.Reachable = false
+	for _, endpointStatus := range status.Endpoints {
+		if endpointStatus.Healthy {
+			status.Reachable = true
+		}
+	}
+	return status
+}
+
+// dbStatus 读取一个key检查数据库是否可用
+//
+//	@return *DbStatus
+func dbStatus() *DbStatus {
+	if db.Db == nil {
+		return &DbStatus{Error: "db is not initialized"}
+	}
+	if _, err := db.Db.Has([]byte(probeKey)); err != nil {
+		return &DbStatus{Error: err.Error()}
+	}
+	return &DbStatus{Ok: true}
+}
+
+// LivenessHandler 存活探测，只要数据库可用就返回200，链或者中继网关不可用时重启网关没有帮助
+//
+//	@return http.Handler
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := Get()
+		code := http.StatusOK
+		if !status.Db.Ok {
+			code = http.StatusServiceUnavailable
+		}
+		writeJson(w, code, &struct {
+			Alive     bool      `json:"alive"`
+			UpdatedAt int64     `json:"updated_at"`
+			Db        *DbStatus `json:"db"`
+		}{
+			Alive:     code == http.StatusOK,
+			UpdatedAt: status.UpdatedAt,
+			Db:        status.Db,
+		})
+	})
+}
+
+// ReadinessHandler 就绪探测，就绪并且状态没有过期时返回200，否则返回503，响应体是完整的健康状态
+//
+//	@return http.Handler
+func ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := Get()
+		if IsStale(status) {
+			stale := *status
+			stale.Ready = false
+			stale.Reasons = append(append([]string{}, status.Reasons...), "health status is stale")
+			status = &stale
+		}
+		code := http.StatusOK
+		if !status.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeJson(w, code, status)
+	})
+}
+
+// writeJson 输出json响应
+//
+//	@param w
+//	@param code
+//	@param body
+func writeJson(w http.ResponseWriter, code int, body interface{}) {
+	bodyByte, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(bodyByte)
+}
+
+// getInterval 刷新间隔
+//
+//	@return time.Duration
+func getInterval() time.Duration {
+	if conf.Config.Health == nil || conf.Config.Health.Interval == 0 {
+		return defaultInterval
+	}
+	return time.Duration(conf.Config.Health.Interval) * time.Second
+}
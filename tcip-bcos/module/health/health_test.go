@@ -0,0 +1,158 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	logger.InitLogConfig(log)
+	conf.Config.BaseConfig = &conf.BaseConfig{TxVerifyType: "notneed"}
+	conf.Config.ChainConfig = []*conf.ChainConfig{{ChainRid: "chain1"}, {ChainRid: "chain2"}}
+	conf.Config.Health = &conf.HealthConfig{Interval: 1, MaxHeaderLag: 5}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	db.NewDbHandle()
+	_ = request.InitRequestManagerMock()
+	_ = chain_client.InitChainClientMock()
+}
+
+func TestCollect(t *testing.T) {
+	initTest()
+	status := Refresh()
+	assert.True(t, status.Ready)
+	assert.True(t, status.ChainOk)
+	assert.Equal(t, 2, len(status.Chains))
+	assert.True(t, status.Relay.Reachable)
+	assert.True(t, status.Db.Ok)
+	assert.Equal(t, status, Get())
+
+	tests := []struct {
+		name        string
+		chains      []*chain_client.ChainStatus
+		wantChainOk bool
+		wantReasons int
+	}{
+		{
+			name:        "unreachable",
+			chains:      []*chain_client.ChainStatus{{ChainRid: "chain1", Subscribed: true}},
+			wantChainOk: false,
+			wantReasons: 1,
+		},
+		{
+			name:        "not subscribed",
+			chains:      []*chain_client.ChainStatus{{ChainRid: "chain1", Reachable: true}},
+			wantChainOk: true,
+			wantReasons: 1,
+		},
+		{
+			name: "header lag",
+			chains: []*chain_client.ChainStatus{{ChainRid: "chain1", Reachable: true, Subscribed: true,
+				HeaderSyncLag: 6}},
+			wantChainOk: true,
+			wantReasons: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain_client.ChainClientV1 = &chainClientStub{ChainClientMock: &chain_client.ChainClientMock{},
+				chains: tt.chains}
+			status := collect(conf.Config.Health)
+			assert.False(t, status.Ready)
+			assert.Equal(t, tt.wantChainOk, status.ChainOk)
+			assert.Equal(t, tt.wantReasons, len(status.Reasons))
+		})
+	}
+	_ = chain_client.InitChainClientMock()
+
+	assert.True(t, gateway.Disable("test"))
+	status = collect(conf.Config.Health)
+	assert.False(t, status.Ready)
+	assert.True(t, status.Relay.Disabled)
+	assert.True(t, gateway.Enable())
+}
+
+func TestHandler(t *testing.T) {
+	initTest()
+	Refresh()
+
+	recorder := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	status := &Status{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), status))
+	assert.True(t, status.Ready)
+	assert.Equal(t, "chain1", status.Chains[0].ChainRid)
+
+	// 后台刷新卡住时不再报告就绪
+	lock.Lock()
+	stale := *current
+	stale.UpdatedAt = time.Now().Add(-time.Minute).Unix()
+	current = &stale
+	lock.Unlock()
+	recorder = httptest.NewRecorder()
+	ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.True(t, Get().Ready)
+}
+
+func TestGet_NotCollected(t *testing.T) {
+	initTest()
+	stub := &chainClientStub{ChainClientMock: &chain_client.ChainClientMock{}}
+	chain_client.ChainClientV1 = stub
+	defer func() { _ = chain_client.InitChainClientMock() }()
+	lock.Lock()
+	current = nil
+	lock.Unlock()
+
+	// 第一次刷新完成前报告未就绪，探测时不会访问链
+	recorder := httptest.NewRecorder()
+	ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	recorder = httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.False(t, Get().Ready)
+	assert.Equal(t, 0, stub.calls)
+}
+
+// chainClientStub 返回指定链状态的链客户端
+type chainClientStub struct {
+	*chain_client.ChainClientMock
+	chains []*chain_client.ChainStatus
+	calls  int
+}
+
+// ChainStatus 返回指定的链状态
+func (c *chainClientStub) ChainStatus() []*chain_client.ChainStatus {
+	c.calls++
+	return c.chains
+}
@@ -6,10 +6,13 @@
 package logger
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -33,6 +36,10 @@ const (
 	ModuleDb = "[DB]"
 	// ModuleChainConfig 链配置模块
 	ModuleChainConfig
+	// ModuleAdmin 运维管理模块
+	ModuleAdmin = "[ADMIN]"
+	// ModuleCert 证书监控模块
+	ModuleCert = "[CERT]"
 
 	defaultLogPath = "./logs/default.log" // release struct need this path
 )
@@ -42,6 +49,8 @@ var (
 	loggers          = make(map[string]*zap.SugaredLogger)
 	loggerMutex      sync.Mutex
 	logInitialized   = false
+	// levels 单独配置了日志的模块的级别，没有单独配置的模块使用默认模块的级别
+	levels = make(map[string]zap.AtomicLevel)
 )
 
 // InitLogConfig set the config of logger module, called in initialization of config module
@@ -61,13 +70,14 @@ func InitLogConfig(config []*LogModuleConfig) {
 			LogInConsole: logModuleConfig.LogInConsole,
 			ShowColor:    logModuleConfig.ShowColor,
 		}
-		logger, _ := InitSugarLogger(config)
+		logger, level := InitSugarLogger(config)
 		loggers[logPrintName] = logger
+		levels[logPrintName] = level
 	}
 	// 最后添加"ModuleDefault"
 	if _, exist := loggers[ModuleDefault]; !exist {
 		// 创建默认的logger
-		loggers[ModuleDefault] = getLogDefaultModuleConfig()
+		loggers[ModuleDefault], levels[ModuleDefault] = getLogDefaultModuleConfig()
 	}
 	logInitialized = true
 }
@@ -95,7 +105,7 @@ func copyLogger(module string) *zap.SugaredLogger {
 	return zap.New(defaultLogger.Desugar().Core()).Named(module).WithOptions(zap.AddCaller()).Sugar()
 }
 
-func getLogDefaultModuleConfig() *zap.SugaredLogger {
+func getLogDefaultModuleConfig() (*zap.SugaredLogger, zap.AtomicLevel) {
 	if defaultLogConfig == nil {
 		defaultLogConfig = &Config{
 			Module:       ModuleDefault,
@@ -108,11 +118,59 @@ func getLogDefaultModuleConfig() *zap.SugaredLogger {
 			LogInConsole: true,
 			ShowColor:    true,
 		}
-		logger, _ := InitSugarLogger(defaultLogConfig)
-		return logger
+		return InitSugarLogger(defaultLogConfig)
 	}
-	logger, _ := InitSugarLogger(defaultLogConfig)
-	return logger
+	return InitSugarLogger(defaultLogConfig)
+}
+
+// GetLogLevels 获取单独配置了日志的模块和默认模块当前的级别
+//  @return map[string]string
+func GetLogLevels() map[string]string {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	result := make(map[string]string, len(levels))
+	for module, level := range levels {
+		result[module] = strings.ToUpper(level.Level().String())
+	}
+	return result
+}
+
+// SetLogLevel 修改模块的日志级别，没有单独配置的模块和默认模块共用级别，只能通过默认模块修改
+//  @param module 模块名，和配置文件中的module_name相同
+//  @param level DEBUG/INFO/WARN/ERROR
+//  @return error
+func SetLogLevel(module, level string) error {
+	var zapLevel zapcore.Level
+	switch strings.ToUpper(level) {
+	case DEBUG:
+		zapLevel = zap.DebugLevel
+	case INFO:
+		zapLevel = zap.InfoLevel
+	case WARN:
+		zapLevel = zap.WarnLevel
+	case ERROR:
+		zapLevel = zap.ErrorLevel
+	default:
+		return fmt.Errorf("invalid log level %s", level)
+	}
+	name := logPrintName(module)
+	if strings.HasPrefix(module, "[") {
+		name = module
+	}
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	atomicLevel, ok := levels[name]
+	if !ok {
+		names := make([]string, 0, len(levels))
+		for configured := range levels {
+			names = append(names, configured)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("log module %s is not configured, configured modules: %s", module,
+			strings.Join(names, ", "))
+	}
+	atomicLevel.SetLevel(zapLevel)
+	return nil
 }
 
 //func getLogModuleConfig(moduleName string) *zap.SugaredLogger {
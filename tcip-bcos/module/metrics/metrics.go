@@ -0,0 +1,161 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tcip_bcos"
+
+// 结果标签的取值
+const (
+	ResultSuccess = "success"
+	ResultFail    = "fail"
+)
+
+var (
+	// Registry 网关的指标都注册在这里，不使用prometheus的全局注册器
+	Registry = prometheus.NewRegistry()
+
+	// EventDetected 监听到的跨链事件数
+	EventDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_detected_total",
+		Help:      "Cross chain events detected on the source chain.",
+	}, []string{"chain_rid"})
+
+	// ForwardDuration 转发到中继网关的耗时，包括重试
+	ForwardDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "forward_duration_seconds",
+		Help:      "Time spent forwarding a request to the relay gateway, retries included.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"chain_rid", "kind", "result"})
+
+	// ForwardRetries 转发到中继网关的重试次数
+	ForwardRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "forward_retries_total",
+		Help:      "Retries of requests to the relay gateway.",
+	}, []string{"chain_rid", "kind"})
+
+	// HeaderSyncHeight 已经同步到中继网关的区块头高度
+	HeaderSyncHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "header_sync_height",
+		Help:      "Highest block header synced to the relay gateway.",
+	}, []string{"chain_rid"})
+
+	// ChainHeight 链上的最新区块高度
+	ChainHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_height",
+		Help:      "Latest block height of the chain.",
+	}, []string{"chain_rid"})
+
+	// HandlerCalls 对外接口的调用次数
+	HandlerCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_calls_total",
+		Help:      "RPC calls handled by the gateway.",
+	}, []string{"method", "code"})
+
+	// HandlerDuration 对外接口的耗时
+	HandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent handling RPC calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// InvokeContractDuration 调用合约的耗时
+	InvokeContractDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "invoke_contract_duration_seconds",
+		Help:      "Time spent invoking contracts on the chain.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"chain_rid", "method", "result"})
+
+	// InvokeContractGas 调用合约消耗的gas
+	InvokeContractGas = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "invoke_contract_gas",
+		Help:      "Gas used by contract invocations.",
+		Buckets:   prometheus.ExponentialBuckets(10000, 2, 12),
+	}, []string{"chain_rid", "method"})
+
+	// DbDuration 数据库操作的耗时
+	DbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_operation_duration_seconds",
+		Help:      "Time spent on leveldb operations.",
+		Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1},
+	}, []string{"operation"})
+
+	// RelayQueueDepth 等待转发到中继网关的请求数
+	RelayQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "relay_queue_depth",
+		Help:      "Requests waiting to be forwarded to the relay gateway.",
+	}, []string{"chain_rid"})
+
+	// CertExpiry 证书的过期时间
+	CertExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cert_expiry_timestamp_seconds",
+		Help:      "Expiry time of the TLS certificates used by the gateway.",
+	}, []string{"name"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		EventDetected,
+		ForwardDuration,
+		ForwardRetries,
+		HeaderSyncHeight,
+		ChainHeight,
+		HandlerCalls,
+		HandlerDuration,
+		InvokeContractDuration,
+		InvokeContractGas,
+		DbDuration,
+		RelayQueueDepth,
+		CertExpiry,
+	)
+}
+
+// Handler 输出全部指标的http接口
+//
+//	@return http.Handler
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Result 根据错误返回结果标签
+//
+//	@param err
+//	@return string
+func Result(err error) string {
+	if err != nil {
+		return ResultFail
+	}
+	return ResultSuccess
+}
+
+// ObserveSince 记录从start开始的耗时
+//
+//	@param observer
+//	@param start
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,44 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	EventDetected.WithLabelValues("chain1").Inc()
+	ObserveSince(ForwardDuration.WithLabelValues("chain1", "begin_cross_chain", Result(nil)),
+		time.Now().Add(-time.Second))
+	RelayQueueDepth.WithLabelValues("chain1").Set(3)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	body, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	for _, want := range []string{
+		`tcip_bcos_event_detected_total{chain_rid="chain1"} 1`,
+		`tcip_bcos_forward_duration_seconds_count{chain_rid="chain1",kind="begin_cross_chain",result="success"} 1`,
+		`tcip_bcos_relay_queue_depth{chain_rid="chain1"} 3`,
+		"go_goroutines",
+	} {
+		assert.True(t, strings.Contains(string(body), want), want)
+	}
+}
+
+func TestResult(t *testing.T) {
+	assert.Equal(t, ResultSuccess, Result(nil))
+	assert.Equal(t, ResultFail, Result(errors.New("fail")))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理空闲令牌桶的间隔
+const sweepInterval = time.Minute
+
+// Limiter 按key分别计算的令牌桶限流器
+type Limiter struct {
+	lock      sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket 令牌桶，tokens是last时刻剩余的令牌数
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter 新建限流器，burst小于1时使用每秒的请求数，至少为1
+//
+//	@param rate 每秒生成的令牌数
+//	@param burst 令牌桶容量
+//	@return *Limiter
+func NewLimiter(rate float64, burst int) *Limiter {
+	l := &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	if l.burst < 1 {
+		l.burst = math.Max(1, math.Ceil(rate))
+	}
+	l.lastSweep = l.now()
+	return l
+}
+
+// Allow 消耗key的一个令牌，令牌不足时返回false和下一个令牌生成需要等待的时间
+//
+//	@receiver l
+//	@param key
+//	@return bool
+//	@return time.Duration
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep 删除已经回满的令牌桶，避免客户端很多时占用内存
+//
+//	@receiver l
+//	@param now
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval || l.rate <= 0 {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("a")
+		assert.True(t, ok)
+	}
+	ok, wait := l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// 不同的key互不影响
+	ok, _ = l.Allow("b")
+	assert.True(t, ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+	ok, _ = l.Allow("a")
+	assert.False(t, ok)
+
+	// 空闲的令牌桶会被清理
+	now = now.Add(2 * sweepInterval)
+	ok, _ = l.Allow("c")
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(l.buckets))
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		want  float64
+	}{
+		{name: "burst", rate: 10, burst: 20, want: 20},
+		{name: "default burst", rate: 2.5, want: 3},
+		{name: "slow", rate: 0.1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewLimiter(tt.rate, tt.burst).burst)
+		})
+	}
+}
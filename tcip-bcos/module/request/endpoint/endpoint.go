@@ -0,0 +1,219 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// PrioritySelection 优先使用优先级高的实例，不可用时切换到下一个
+	PrioritySelection = conf.PrioritySelection
+	// RoundRobinSelection 轮流使用全部可用的实例
+	RoundRobinSelection = conf.RoundRobinSelection
+
+	defaultFailureThreshold = 3
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+// Endpoint 中继网关实例
+type Endpoint struct {
+	Address    string
+	ServerName string
+	Priority   int
+	// failures 连续失败次数，达到阈值后熔断
+	failures int
+	// openUntil 熔断结束时间，之后允许一个探测请求
+	openUntil time.Time
+}
+
+// Status 中继网关实例的健康状态
+type Status struct {
+	Address  string `json:"address"`
+	Healthy  bool   `json:"healthy"`
+	Failures int    `json:"failures"`
+}
+
+// Selector 选择中继网关实例，按实例统计失败次数并熔断
+type Selector struct {
+	lock             sync.Mutex
+	endpoints        []*Endpoint
+	roundRobin       bool
+	next             int
+	failureThreshold int
+	openTimeout      time.Duration
+}
+
+// NewSelector 根据中继网关配置新建选择器，没有配置endpoints时使用relay.address
+//
+//	@param config
+//	@return *Selector
+func NewSelector(config *conf.Relay) *Selector {
+	s := &Selector{
+		roundRobin:       config.Selection == RoundRobinSelection,
+		failureThreshold: defaultFailureThreshold,
+		openTimeout:      defaultOpenTimeout,
+	}
+	if config.FailureThreshold > 0 {
+		s.failureThreshold = config.FailureThreshold
+	}
+	if config.OpenTimeout > 0 {
+		s.openTimeout = time.Duration(config.OpenTimeout) * time.Second
+	}
+	for _, endpoint := range config.Endpoints {
+		serverName := endpoint.ServerName
+		if serverName == "" {
+			serverName = config.ServerName
+		}
+		s.endpoints = append(s.endpoints, &Endpoint{
+			Address:    endpoint.Address,
+			ServerName: serverName,
+			Priority:   endpoint.Priority,
+		})
+	}
+	if len(s.endpoints) == 0 {
+		s.endpoints = []*Endpoint{{Address: config.Address, ServerName: config.ServerName}}
+	}
+	sort.SliceStable(s.endpoints, func(i, j int) bool {
+		return s.endpoints[i].Priority < s.endpoints[j].Priority
+	})
+	return s
+}
+
+// Pick 选择一个可用的实例，exclude中的实例本次调用已经失败过，全部实例都不可用时返回Unavailable
+//
+//	@receiver s
+//	@param exclude
+//	@return *Endpoint
+//	@return error
+func (s *Selector) Pick(exclude map[*Endpoint]bool) (*Endpoint, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	count := len(s.endpoints)
+	start := 0
+	if s.roundRobin {
+		start = s.next
+	}
+	for i := 0; i < count; i++ {
+		index := (start + i) % count
+		endpoint := s.endpoints[index]
+		if exclude[endpoint] || now.Before(endpoint.openUntil) {
+			continue
+		}
+		if endpoint.failures >= s.failureThreshold {
+			// 熔断时间已过，放行一个探测请求，探测失败之前其他请求继续等待
+			endpoint.openUntil = now.Add(s.openTimeout)
+		}
+		if s.roundRobin {
+			s.next = index + 1
+		}
+		return endpoint, nil
+	}
+	return nil, status.Error(codes.Unavailable, "no available relay gateway endpoint")
+}
+
+// Report 报告调用结果，连接不上或者超时计为失败，其他结果说明实例可用
+//
+//	@receiver s
+//	@param endpoint
+//	@param err
+func (s *Selector) Report(endpoint *Endpoint, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !IsEndpointError(err) {
+		endpoint.failures = 0
+		endpoint.openUntil = time.Time{}
+		return
+	}
+	endpoint.failures++
+	if endpoint.failures >= s.failureThreshold {
+		endpoint.openUntil = time.Now().Add(s.openTimeout)
+	}
+}
+
+// Len 实例个数
+//
+//	@receiver s
+//	@return int
+func (s *Selector) Len() int {
+	return len(s.endpoints)
+}
+
+// Status 全部实例的健康状态
+//
+//	@receiver s
+//	@return []*Status
+func (s *Selector) Status() []*Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	statuses := make([]*Status, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		statuses = append(statuses, &Status{
+			Address:  endpoint.Address,
+			Healthy:  endpoint.failures < s.failureThreshold,
+			Failures: endpoint.failures,
+		})
+	}
+	return statuses
+}
+
+// IsEndpointError 是否是实例本身的问题，换一个实例可能成功
+//
+//	@param err
+//	@return bool
+func IsEndpointError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFailoverError 是否可以切换到下一个实例重新调用，只有Unavailable说明请求没有到达实例，
+// 超时的请求可能已经被实例处理，换一个实例重新发送会重复跨链
+//
+//	@param err
+//	@return bool
+func IsFailoverError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// Call 依次在可用的实例上调用call，实例不可用时切换到下一个实例，请求不会丢失，
+// 超时计入实例的失败次数，但是不切换实例，由调用方按照重试策略处理
+//
+//	@receiver s
+//	@param call
+//	@return error
+func (s *Selector) Call(call func(endpoint *Endpoint) error) error {
+	exclude := make(map[*Endpoint]bool)
+	var lastErr error
+	for {
+		endpoint, err := s.Pick(exclude)
+		if err != nil {
+			if lastErr != nil {
+				return lastErr
+			}
+			return err
+		}
+		lastErr = call(endpoint)
+		s.Report(endpoint, lastErr)
+		if !IsFailoverError(lastErr) {
+			return lastErr
+		}
+		exclude[endpoint] = true
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "down")
+
+func TestSelector_Priority(t *testing.T) {
+	s := NewSelector(&conf.Relay{
+		ServerName: "relay",
+		Endpoints: []*conf.RelayEndpoint{
+			{Address: "b", Priority: 2},
+			{Address: "a", Priority: 1, ServerName: "a.relay"},
+		},
+		FailureThreshold: 2,
+	})
+	endpoint, err := s.Pick(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", endpoint.Address)
+	assert.Equal(t, "a.relay", endpoint.ServerName)
+
+	// 连续失败达到阈值后熔断，切换到下一个实例
+	s.Report(endpoint, errUnavailable)
+	endpoint, _ = s.Pick(nil)
+	assert.Equal(t, "a", endpoint.Address)
+	s.Report(endpoint, errUnavailable)
+	endpoint, _ = s.Pick(nil)
+	assert.Equal(t, "b", endpoint.Address)
+	assert.Equal(t, "relay", endpoint.ServerName)
+	assert.False(t, s.Status()[0].Healthy)
+
+	// 熔断时间过后放行一个探测请求
+	s.endpoints[0].openUntil = time.Now().Add(-time.Second)
+	endpoint, _ = s.Pick(nil)
+	assert.Equal(t, "a", endpoint.Address)
+	probe := endpoint
+	endpoint, _ = s.Pick(nil)
+	assert.Equal(t, "b", endpoint.Address)
+	s.Report(probe, nil)
+	endpoint, _ = s.Pick(nil)
+	assert.Equal(t, "a", endpoint.Address)
+	assert.True(t, s.Status()[0].Healthy)
+}
+
+func TestSelector_RoundRobin(t *testing.T) {
+	s := NewSelector(&conf.Relay{
+		Selection: RoundRobinSelection,
+		Endpoints: []*conf.RelayEndpoint{{Address: "a"}, {Address: "b"}},
+	})
+	got := make([]string, 0)
+	for i := 0; i < 4; i++ {
+		endpoint, err := s.Pick(nil)
+		assert.Nil(t, err)
+		got = append(got, endpoint.Address)
+	}
+	assert.Equal(t, []string{"a", "b", "a", "b"}, got)
+
+	s = NewSelector(&conf.Relay{Address: "single"})
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestSelector_Call(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      map[string]error
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name:      "failover",
+			errs:      map[string]error{"a": errUnavailable},
+			wantCalls: []string{"a", "b"},
+		},
+		{
+			name:      "business error not failover",
+			errs:      map[string]error{"a": status.Error(codes.InvalidArgument, "bad")},
+			wantCalls: []string{"a"},
+			wantErr:   status.Error(codes.InvalidArgument, "bad"),
+		},
+		{
+			name:      "timeout not failover",
+			errs:      map[string]error{"a": status.Error(codes.DeadlineExceeded, "timeout")},
+			wantCalls: []string{"a"},
+			wantErr:   status.Error(codes.DeadlineExceeded, "timeout"),
+		},
+		{
+			name:      "all unavailable",
+			errs:      map[string]error{"a": errUnavailable, "b": errUnavailable},
+			wantCalls: []string{"a", "b"},
+			wantErr:   errUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSelector(&conf.Relay{
+				Endpoints: []*conf.RelayEndpoint{{Address: "a"}, {Address: "b"}},
+			})
+			calls := make([]string, 0)
+			err := s.Call(func(endpoint *Endpoint) error {
+				calls = append(calls, endpoint.Address)
+				return tt.errs[endpoint.Address]
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantErr == nil, err == nil)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error())
+			}
+		})
+	}
+}
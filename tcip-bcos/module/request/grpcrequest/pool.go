@@ -0,0 +1,230 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcrequest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/ca"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/certwatch"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
+	"chainmaker.org/chainmaker/tcip-go/v2/api"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// defaultPoolSize 默认连接数
+	defaultPoolSize = 1
+	// maxBackoffDelay 重连的最大退避时间
+	maxBackoffDelay = 30 * time.Second
+	// minConnectTimeout 建立连接的最小超时时间
+	minConnectTimeout = 5 * time.Second
+)
+
+// connPool 到一个中继网关实例的长连接池，证书文件变化时重建连接
+type connPool struct {
+	log   *zap.SugaredLogger
+	relay *endpoint.Endpoint
+	lock  sync.Mutex
+	conns []*grpc.ClientConn
+	next  int
+	// fingerprint 建立连接时的中继网关地址和证书文件状态
+	fingerprint string
+	lastCheck   time.Time
+}
+
+// newConnPool 新建连接池，第一次使用时才建立连接
+//
+//	@param log
+//	@param relay 中继网关实例
+//	@return *connPool
+func newConnPool(log *zap.SugaredLogger, relay *endpoint.Endpoint) *connPool {
+	return &connPool{
+		log:   log,
+		relay: relay,
+	}
+}
+
+// getClient 获取一个中继网关客户端，优先使用状态正常的连接
+//
+//	@receiver p
+//	@return api.RpcRelayChainClient
+//	@return error
+func (p *connPool) getClient() (api.RpcRelayChainClient, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conns == nil || time.Since(p.lastCheck) > certwatch.CheckInterval {
+		p.lastCheck = time.Now()
+		if err := p.check(); err != nil {
+			p.log.Errorf("[getClient] %s", err.Error())
+			// 证书更新失败时继续使用旧的连接
+			if p.conns == nil {
+				return nil, err
+			}
+		}
+	}
+	for i := 0; i < len(p.conns); i++ {
+		index := (p.next + i) % len(p.conns)
+		state := p.conns[index].GetState()
+		if state == connectivity.Shutdown {
+			conn, err := p.dial()
+			if err != nil {
+				p.log.Errorf("[getClient] %s", err.Error())
+				return nil, err
+			}
+			p.conns[index] = conn
+			state = conn.GetState()
+		}
+		if state != connectivity.TransientFailure {
+			p.next = index + 1
+			return api.NewRpcRelayChainClient(p.conns[index]), nil
+		}
+	}
+	// 所有连接都在重连中，直接使用下一个连接，请求会返回Unavailable
+	conn := p.conns[p.next%len(p.conns)]
+	p.next++
+	return api.NewRpcRelayChainClient(conn), nil
+}
+
+// check 中继网关配置或者证书文件变化时重建连接，失败时不更新指纹，下次检查时重试
+//
+//	@receiver p
+//	@return error
+func (p *connPool) check() error {
+	fingerprint, err := getFingerprint(p.relay)
+	if err != nil {
+		return err
+	}
+	if fingerprint == p.fingerprint {
+		return nil
+	}
+	if err = p.rebuild(); err != nil {
+		return err
+	}
+	p.fingerprint = fingerprint
+	return nil
+}
+
+// rebuild 重新建立全部连接，旧的连接等正在进行的请求超时后关闭
+//
+//	@receiver p
+//	@return error
+func (p *connPool) rebuild() error {
+	size := conf.Config.Relay.ConnPoolSize
+	if size <= 0 {
+		size = defaultPoolSize
+	}
+	conns := make([]*grpc.ClientConn, 0, size)
+	for i := 0; i < size; i++ {
+		conn, err := p.dial()
+		if err != nil {
+			for _, c := range conns {
+				_ = c.Close()
+			}
+			return err
+		}
+		conns = append(conns, conn)
+	}
+	if p.conns != nil {
+		p.log.Infof("[rebuild] relay config or certificates changed, reconnect to %s", p.relay.Address)
+		oldConns := p.conns
+		closeDelay := time.Duration(conf.DefaultTimeout()) * time.Second
+		time.AfterFunc(closeDelay, func() {
+			for _, c := range oldConns {
+				_ = c.Close()
+			}
+		})
+	}
+	p.conns = conns
+	p.next = 0
+	return nil
+}
+
+// dial 建立一个到中继网关的连接，连接断开后grpc会按照退避策略自动重连
+//
+//	@receiver p
+//	@return *grpc.ClientConn
+//	@return error
+func (p *connPool) dial() (*grpc.ClientConn, error) {
+	var kacp = keepalive.ClientParameters{
+		Time:                10 * time.Second,
+		Timeout:             time.Second,
+		PermitWithoutStream: true,
+	}
+	caCert, err := ioutil.ReadFile(conf.Config.Relay.Tlsca)
+	if err != nil {
+		return nil, err
+	}
+	clientCert, err := ioutil.ReadFile(conf.Config.Relay.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	clientKey, err := certwatch.ReadKey(conf.Config.Relay.ClientKey, conf.Config.Relay.ClientKeyPassword)
+	if err != nil {
+		return nil, err
+	}
+	tlsClient := ca.CAClient{
+		ServerName: p.relay.ServerName,
+		CaCerts:    []string{string(caCert)},
+		CertBytes:  clientCert,
+		KeyBytes:   clientKey,
+		Logger:     p.log,
+	}
+	c, err := tlsClient.GetCredentialsByCA()
+	if err != nil {
+		return nil, err
+	}
+	backoffConfig := backoff.DefaultConfig
+	backoffConfig.MaxDelay = maxBackoffDelay
+	return grpc.Dial(
+		p.relay.Address,
+		grpc.WithTransportCredentials(*c),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(conf.Config.RpcConfig.MaxRecvMsgSize*1024*1024),
+			grpc.MaxCallSendMsgSize(conf.Config.RpcConfig.MaxSendMsgSize*1024*1024),
+		),
+		grpc.WithKeepaliveParams(kacp),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoffConfig,
+			MinConnectTimeout: minConnectTimeout,
+		}),
+	)
+}
+
+// close 关闭全部连接
+//
+//	@receiver p
+func (p *connPool) close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.conns = nil
+	p.fingerprint = ""
+}
+
+// getFingerprint 中继网关实例地址和证书文件的修改时间、大小，任何一个变化都需要重建连接
+//
+//	@param relay
+//	@return string
+//	@return error
+func getFingerprint(relay *endpoint.Endpoint) (string, error) {
+	fingerprint, err := certwatch.Fingerprint(conf.Config.Relay.Tlsca, conf.Config.Relay.ClientCert,
+		conf.Config.Relay.ClientKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s|%s%s", relay.Address, relay.ServerName, fingerprint), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcrequest
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
+)
+
+// copyCert 把证书复制到临时目录，测试中修改证书文件的时间
+func copyCert(t *testing.T, dir, src string) string {
+	content, err := ioutil.ReadFile(src)
+	assert.Nil(t, err)
+	dst := path.Join(dir, path.Base(src))
+	assert.Nil(t, ioutil.WriteFile(dst, content, 0600))
+	return dst
+}
+
+func TestConnPool_getClient(t *testing.T) {
+	testInit()
+	dir, err := ioutil.TempDir("", "relay-cert")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	conf.Config.RpcConfig = &conf.RpcConfig{MaxSendMsgSize: 10, MaxRecvMsgSize: 10}
+	conf.Config.Relay.Address = "127.0.0.1:19999"
+	conf.Config.Relay.ConnPoolSize = 2
+	conf.Config.Relay.Tlsca = copyCert(t, dir, "../../../config/cert/client/ca.crt")
+	conf.Config.Relay.ClientCert = copyCert(t, dir, "../../../config/cert/client/client.crt")
+	conf.Config.Relay.ClientKey = copyCert(t, dir, "../../../config/cert/client/client.key")
+
+	pool := newConnPool(logger.GetLogger(logger.ModuleRequest),
+		&endpoint.Endpoint{Address: conf.Config.Relay.Address, ServerName: conf.Config.Relay.ServerName})
+	defer pool.close()
+	_, err = pool.getClient()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pool.conns))
+	first := pool.conns[0]
+
+	// 证书没有变化时复用连接
+	pool.lastCheck = time.Time{}
+	_, err = pool.getClient()
+	assert.Nil(t, err)
+	assert.Equal(t, first, pool.conns[0])
+
+	// 证书变化后重建连接
+	modTime := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(conf.Config.Relay.ClientCert, modTime, modTime))
+	pool.lastCheck = time.Time{}
+	_, err = pool.getClient()
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, pool.conns[0])
+
+	// 证书文件不存在时继续使用旧的连接
+	second := pool.conns[0]
+	conf.Config.Relay.ClientKey = path.Join(dir, "not-exist.key")
+	pool.lastCheck = time.Time{}
+	_, err = pool.getClient()
+	assert.Nil(t, err)
+	assert.Equal(t, second, pool.conns[0])
+
+	// 没有可用的连接时报错
+	newPool := newConnPool(logger.GetLogger(logger.ModuleRequest),
+		&endpoint.Endpoint{Address: conf.Config.Relay.Address, ServerName: conf.Config.Relay.ServerName})
+	_, err = newPool.getClient()
+	assert.NotNil(t, err)
+}
@@ -6,27 +6,30 @@ SPDX-License-Identifier: Apache-2.0
 package grpcrequest
 
 import (
-	"chainmaker.org/chainmaker/common/v2/ca"
 	"context"
-	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/metadata"
-	"io/ioutil"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc/metadata"
+
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
 
 	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
 
 	"chainmaker.org/chainmaker/tcip-go/v2/api"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GrpcRequest grpc请求结构体
 type GrpcRequest struct {
-	// 后续可以实现一个connection pool
-	//conn map[string]api.RpcCrossChainClient
-	log *zap.SugaredLogger
+	log          *zap.SugaredLogger
+	selector     *endpoint.Selector
+	selectorOnce sync.Once
+	lock         sync.Mutex
+	pools        map[*endpoint.Endpoint]*connPool
 }
 
 // NewGrpcRequest 初始化grpc请求
@@ -47,18 +50,16 @@ func NewGrpcRequest(log *zap.SugaredLogger) *GrpcRequest {
 //	@return error
 func (g *GrpcRequest) BeginCrossChain(
 	req *relay_chain.BeginCrossChainRequest) (*relay_chain.BeginCrossChainResponse, error) {
-	timeout := conf.Config.BaseConfig.DefaultTimeout
-	client, conn, err := g.getConnection()
+	var response *relay_chain.BeginCrossChainResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.BeginCrossChain(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-	defer cancel()
-	md := metadata.Pairs("x-token", conf.Config.Relay.AccessCode)
-	metadataCtx := metadata.NewOutgoingContext(ctx, md)
-	response, err := client.BeginCrossChain(metadataCtx, req)
-	defer conn.Close()
-	return response, err
+	return response, nil
 }
 
 // SyncBlockHeader 同步区块头
@@ -69,72 +70,162 @@ func (g *GrpcRequest) BeginCrossChain(
 //	@return error
 func (g *GrpcRequest) SyncBlockHeader(
 	req *relay_chain.SyncBlockHeaderRequest) (*relay_chain.SyncBlockHeaderResponse, error) {
-	timeout := conf.Config.BaseConfig.DefaultTimeout
-	client, conn, err := g.getConnection()
+	var response *relay_chain.SyncBlockHeaderResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.SyncBlockHeader(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-	defer cancel()
-	md := metadata.Pairs("x-token", conf.Config.Relay.AccessCode)
-	metadataCtx := metadata.NewOutgoingContext(ctx, md)
-	response, err := client.SyncBlockHeader(metadataCtx, req)
-	_ = conn.Close()
-	return response, err
+	return response, nil
 }
 
-// 后续可以实现一个connection pool
+// GatewayRegister 注册网关
 //
 //	@receiver g
-//	@return api.RpcRelayChainClient
-//	@return *grpc.ClientConn
+//	@param req
+//	@return *relay_chain.GatewayRegisterResponse
 //	@return error
-func (g *GrpcRequest) getConnection() (api.RpcRelayChainClient, *grpc.ClientConn, error) {
-	var kacp = keepalive.ClientParameters{
-		Time:                10 * time.Second,
-		Timeout:             time.Second,
-		PermitWithoutStream: true,
-	}
-	var tlsClient ca.CAClient
-	var (
-		err error
-	)
-	caCert, err := ioutil.ReadFile(conf.Config.Relay.Tlsca)
+func (g *GrpcRequest) GatewayRegister(
+	req *relay_chain.GatewayRegisterRequest) (*relay_chain.GatewayRegisterResponse, error) {
+	var response *relay_chain.GatewayRegisterResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.GatewayRegister(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	clientCert, err := ioutil.ReadFile(conf.Config.Relay.ClientCert)
+	return response, nil
+}
+
+// GatewayUpdate 更新网关信息
+//
+//	@receiver g
+//	@param req
+//	@return *relay_chain.GatewayUpdateResponse
+//	@return error
+func (g *GrpcRequest) GatewayUpdate(
+	req *relay_chain.GatewayUpdateRequest) (*relay_chain.GatewayUpdateResponse, error) {
+	var response *relay_chain.GatewayUpdateResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.GatewayUpdate(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	clientKey, err := ioutil.ReadFile(conf.Config.Relay.ClientKey)
+	return response, nil
+}
+
+// InitSpvContract 安装spv合约
+//
+//	@receiver g
+//	@param req
+//	@return *relay_chain.InitContractResponse
+//	@return error
+func (g *GrpcRequest) InitSpvContract(
+	req *relay_chain.InitContractRequest) (*relay_chain.InitContractResponse, error) {
+	var response *relay_chain.InitContractResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.InitContract(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, nil, err
-	}
-	tlsClient = ca.CAClient{
-		ServerName: conf.Config.Relay.ServerName,
-		CaCerts:    []string{string(caCert)},
-		CertBytes:  clientCert,
-		KeyBytes:   clientKey,
-		Logger:     g.log,
+		return nil, err
 	}
+	return response, nil
+}
 
-	c, err := tlsClient.GetCredentialsByCA()
+// UpdateSpvContract 更新spv合约
+//
+//	@receiver g
+//	@param req
+//	@return *relay_chain.UpdateContractResponse
+//	@return error
+func (g *GrpcRequest) UpdateSpvContract(
+	req *relay_chain.UpdateContractRequest) (*relay_chain.UpdateContractResponse, error) {
+	var response *relay_chain.UpdateContractResponse
+	err := g.invoke(func(ctx context.Context, client api.RpcRelayChainClient) error {
+		var err error
+		response, err = client.UpdateContract(ctx, req)
+		return err
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	conn, err := grpc.Dial(
-		conf.Config.Relay.Address,
-		grpc.WithTransportCredentials(*c),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(conf.Config.RpcConfig.MaxRecvMsgSize*1024*1024),
-			grpc.MaxCallSendMsgSize(conf.Config.RpcConfig.MaxSendMsgSize*1024*1024),
-		),
-		grpc.WithKeepaliveParams(kacp),
-	)
+	return response, nil
+}
+
+// invoke 在可用的中继网关实例上调用接口，实例不可用时切换到下一个实例
+//
+//	@receiver g
+//	@param call
+//	@return error
+func (g *GrpcRequest) invoke(call func(ctx context.Context, client api.RpcRelayChainClient) error) error {
+	timeout := conf.DefaultTimeout()
+	return g.getSelector().Call(func(relay *endpoint.Endpoint) error {
+		client, err := g.getConnection(relay)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+		defer cancel()
+		md := metadata.Pairs("x-token", conf.Config.Relay.AccessCode)
+		metadataCtx := metadata.NewOutgoingContext(ctx, md)
+		err = call(metadataCtx, client)
+		if err != nil {
+			g.log.Warnf("[invoke] relay gateway %s: %s", relay.Address, err.Error())
+		}
+		return err
+	})
+}
+
+// getSelector 获取中继网关实例选择器，第一次使用时根据配置创建
+//
+//	@receiver g
+//	@return *endpoint.Selector
+func (g *GrpcRequest) getSelector() *endpoint.Selector {
+	g.selectorOnce.Do(func() {
+		g.selector = endpoint.NewSelector(conf.Config.Relay)
+	})
+	return g.selector
+}
+
+// EndpointStatus 全部中继网关实例的健康状态
+//
+//	@receiver g
+//	@return []*endpoint.Status
+func (g *GrpcRequest) EndpointStatus() []*endpoint.Status {
+	return g.getSelector().Status()
+}
+
+// getConnection 从实例的连接池中获取中继网关客户端
+//
+//	@receiver g
+//	@param relay
+//	@return api.RpcRelayChainClient
+//	@return error
+func (g *GrpcRequest) getConnection(relay *endpoint.Endpoint) (api.RpcRelayChainClient, error) {
+	g.lock.Lock()
+	if g.pools == nil {
+		g.pools = make(map[*endpoint.Endpoint]*connPool)
+	}
+	pool, ok := g.pools[relay]
+	if !ok {
+		pool = newConnPool(g.log, relay)
+		g.pools[relay] = pool
+	}
+	g.lock.Unlock()
+	client, err := pool.getClient()
 	if err != nil {
-		return nil, nil, err
+		// 证书读取失败等本地错误，换一个实例也可能成功
+		return nil, status.Error(codes.Unavailable, err.Error())
 	}
-	return api.NewRpcRelayChainClient(conn), conn, nil
-
+	return client, nil
 }
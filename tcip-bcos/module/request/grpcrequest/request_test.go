@@ -19,7 +19,6 @@ import (
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
 	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
 )
 
 var log = []*logger.LogModuleConfig{
@@ -35,6 +34,10 @@ func testInit() *GrpcRequest {
 	conf.Config.BaseConfig = &conf.BaseConfig{
 		DefaultTimeout: 10,
 	}
+	conf.Config.RpcConfig = &conf.RpcConfig{
+		MaxSendMsgSize: 10,
+		MaxRecvMsgSize: 10,
+	}
 	conf.Config.Relay = &conf.Relay{
 		Address:    "https://127.0.0.1:19999",
 		ServerName: "chainmaker.org",
@@ -238,7 +241,6 @@ func TestGrpcRequest_getConnection(t *testing.T) {
 		name    string
 		fields  fields
 		want    api.RpcRelayChainClient
-		want1   *grpc.ClientConn
 		wantErr bool
 	}{
 		{
@@ -256,7 +258,12 @@ func TestGrpcRequest_getConnection(t *testing.T) {
 			g := &GrpcRequest{
 				log: tt.fields.log,
 			}
-			_, _, err := g.getConnection()
+			relay, err := g.getSelector().Pick(nil)
+			if err != nil {
+				t.Errorf("Pick() error = %v", err)
+				return
+			}
+			_, err = g.getConnection(relay)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getConnection() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -264,9 +271,6 @@ func TestGrpcRequest_getConnection(t *testing.T) {
 			//if !reflect.DeepEqual(got, tt.want) {
 			//	t.Errorf("getConnection() got = %v, want %v", got, tt.want)
 			//}
-			//if !reflect.DeepEqual(got1, tt.want1) {
-			//	t.Errorf("getConnection() got1 = %v, want %v", got1, tt.want1)
-			//}
 		})
 	}
 }
@@ -0,0 +1,181 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package request
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+	"go.uber.org/zap"
+)
+
+// GatewayRegister 注册网关，把中继网关分配的网关ID写回配置文件
+//
+//	@receiver r
+//	@param configPath 配置文件路径
+//	@return error
+func (r *RequestManager) GatewayRegister(configPath string) error {
+	gatewayInfo, err := r.getGatewayInfo()
+	if err != nil {
+		r.log.Errorf("[GatewayRegister] %s", err.Error())
+		return err
+	}
+	res, err := r.request.GatewayRegister(&relay_chain.GatewayRegisterRequest{
+		Version:     common.Version_V1_0_0,
+		GatewayInfo: gatewayInfo,
+	})
+	if err != nil {
+		r.log.Errorf("[GatewayRegister] %s", err.Error())
+		return err
+	}
+	if res.Code != common.Code_GATEWAY_SUCCESS {
+		r.log.Errorf("[GatewayRegister] register failed: code %s, message %s", res.Code.String(), res.Message)
+		return fmt.Errorf("register failed: code %s, message %s", res.Code.String(), res.Message)
+	}
+	conf.Config.BaseConfig.GatewayID = res.GatewayId
+	if err = conf.SetGatewayId(configPath, res.GatewayId); err != nil {
+		r.log.Errorf("[GatewayRegister] write gateway id %s to %s error: %s", res.GatewayId, configPath, err.Error())
+		return fmt.Errorf("gateway registered with id %s, but write it to %s failed: %s",
+			res.GatewayId, configPath, err.Error())
+	}
+	r.log.Infof("[GatewayRegister] register success: gatewayId %s", res.GatewayId)
+	return nil
+}
+
+// GatewayUpdate 把配置文件中的网关信息更新到中继网关
+//
+//	@receiver r
+//	@return error
+func (r *RequestManager) GatewayUpdate() error {
+	gatewayInfo, err := r.getGatewayInfo()
+	if err != nil {
+		r.log.Errorf("[GatewayUpdate] %s", err.Error())
+		return err
+	}
+	if gatewayInfo.GatewayId == "" {
+		return fmt.Errorf("gateway_id is empty, please register first")
+	}
+	res, err := r.request.GatewayUpdate(&relay_chain.GatewayUpdateRequest{
+		Version:     common.Version_V1_0_0,
+		GatewayInfo: gatewayInfo,
+	})
+	if err != nil {
+		r.log.Errorf("[GatewayUpdate] %s", err.Error())
+		return err
+	}
+	if res.Code != common.Code_GATEWAY_SUCCESS {
+		r.log.Errorf("[GatewayUpdate] update failed: code %s, message %s", res.Code.String(), res.Message)
+		return fmt.Errorf("update failed: code %s, message %s", res.Code.String(), res.Message)
+	}
+	r.log.Infof("[GatewayUpdate] update success: gatewayId %s", gatewayInfo.GatewayId)
+	return nil
+}
+
+// getGatewayInfo 根据配置构建注册到中继网关的网关信息
+//
+//	@receiver r
+//	@return *common.GatewayInfo
+//	@return error
+func (r *RequestManager) getGatewayInfo() (*common.GatewayInfo, error) {
+	base := conf.Config.BaseConfig
+	gatewayInfo := &common.GatewayInfo{
+		GatewayId:         base.GatewayID,
+		GatewayName:       base.GatewayName,
+		Address:           base.Address,
+		ServerName:        base.ServerName,
+		ToGatewayList:     base.ToGatewayList,
+		FromGatewayList:   base.FromGatewayList,
+		TxVerifyType:      getTxVerifyType(r.log),
+		TxVerifyInterface: getTxVerifyInterface(r.log),
+		CallType:          getCallType(r.log),
+	}
+	if base.Address == "" {
+		return nil, fmt.Errorf("base.address is required")
+	}
+	var err error
+	if base.Tlsca != "" {
+		if gatewayInfo.Tlsca, err = ioutil.ReadFile(base.Tlsca); err != nil {
+			return nil, fmt.Errorf("read base.tls_ca error: %s", err.Error())
+		}
+	}
+	if base.ClientCert != "" {
+		if gatewayInfo.ClientCert, err = ioutil.ReadFile(base.ClientCert); err != nil {
+			return nil, fmt.Errorf("read base.client_cert error: %s", err.Error())
+		}
+	}
+	if gatewayInfo.TxVerifyType == common.TxVerifyType_RPC_INTERFACE && gatewayInfo.TxVerifyInterface == nil {
+		return nil, fmt.Errorf("base.tx_verify_interface is required when tx_verify_type is %s", conf.RpcTxVerify)
+	}
+	return gatewayInfo, nil
+}
+
+// getCallType 中继网关调用本网关的方式，默认grpc
+//
+//	@param log
+//	@return common.CallType
+func getCallType(log *zap.SugaredLogger) common.CallType {
+	switch conf.Config.BaseConfig.CallType {
+	case conf.RestCallType:
+		return common.CallType_REST
+	case conf.GrpcCallType, "":
+		return common.CallType_GRPC
+	default:
+		log.Warnf("[getCallType] unsupported call_type %s, use %s", conf.Config.BaseConfig.CallType,
+			conf.GrpcCallType)
+		return common.CallType_GRPC
+	}
+}
+
+// getTxVerifyType 交易验证方式，默认spv
+//
+//	@param log
+//	@return common.TxVerifyType
+func getTxVerifyType(log *zap.SugaredLogger) common.TxVerifyType {
+	switch conf.Config.BaseConfig.TxVerifyType {
+	case conf.RpcTxVerify:
+		return common.TxVerifyType_RPC_INTERFACE
+	case conf.NotNeedTxVerify:
+		return common.TxVerifyType_NOT_NEED
+	case conf.SpvTxVerify, "":
+		return common.TxVerifyType_SPV
+	default:
+		log.Warnf("[getTxVerifyType] unsupported tx_verify_type %s, use %s",
+			conf.Config.BaseConfig.TxVerifyType, conf.SpvTxVerify)
+		return common.TxVerifyType_SPV
+	}
+}
+
+// getTxVerifyInterface 交易验证接口，只有rpc验证方式需要
+//
+//	@param log
+//	@return *common.TxVerifyInterface
+func getTxVerifyInterface(log *zap.SugaredLogger) *common.TxVerifyInterface {
+	config := conf.Config.BaseConfig.TxVerifyInterface
+	if conf.Config.BaseConfig.TxVerifyType != conf.RpcTxVerify || config == nil {
+		return nil
+	}
+	txVerifyInterface := &common.TxVerifyInterface{
+		Address:   config.Address,
+		TlsEnable: config.TlsEnable,
+		HostName:  config.HostName,
+	}
+	if !config.TlsEnable {
+		return txVerifyInterface
+	}
+	var err error
+	if txVerifyInterface.Tlsca, err = ioutil.ReadFile(config.Tlsca); err != nil {
+		log.Errorf("[getTxVerifyInterface] read tls_ca error: %s", err.Error())
+		return nil
+	}
+	if txVerifyInterface.ClientCert, err = ioutil.ReadFile(config.ClientCert); err != nil {
+		log.Errorf("[getTxVerifyInterface] read client_cert error: %s", err.Error())
+		return nil
+	}
+	return txVerifyInterface
+}
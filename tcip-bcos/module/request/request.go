@@ -7,6 +7,11 @@ package request
 
 import (
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/deadletter"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/dispatcher"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/eventcache"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
 	"encoding/base64"
 	"encoding/json"
@@ -18,23 +23,38 @@ import (
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/grpcrequest"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/restrequest"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/retry"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/txrecord"
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
 	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
 	"go.uber.org/zap"
 )
 
+// defaultProbeInterval 网关被禁用后探测是否恢复的默认间隔
+const defaultProbeInterval = time.Minute
+
 // Request 请求接口
 type Request interface {
 	BeginCrossChain(req *relay_chain.BeginCrossChainRequest) (*relay_chain.BeginCrossChainResponse, error)
 	SyncBlockHeader(req *relay_chain.SyncBlockHeaderRequest) (*relay_chain.SyncBlockHeaderResponse, error)
+	GatewayRegister(req *relay_chain.GatewayRegisterRequest) (*relay_chain.GatewayRegisterResponse, error)
+	GatewayUpdate(req *relay_chain.GatewayUpdateRequest) (*relay_chain.GatewayUpdateResponse, error)
+	InitSpvContract(req *relay_chain.InitContractRequest) (*relay_chain.InitContractResponse, error)
+	UpdateSpvContract(req *relay_chain.UpdateContractRequest) (*relay_chain.UpdateContractResponse, error)
 }
 
 // RequestManager 请求管理结构体
 type RequestManager struct {
 	log     *zap.SugaredLogger
 	request Request
+	retry   *retry.Policy
+	// dispatcher 按照源链转发跨链请求
+	dispatcher *dispatcher.Dispatcher
+	// cursor 并发转发时的事件游标
+	cursor *dispatcher.Cursor
 }
 
 // RequestV1 rquest模块对象
@@ -54,69 +74,157 @@ func InitRequestManager() error {
 		panic("unsupport call_type:" + conf.Config.Relay.CallType)
 	}
 	RequestV1 = &RequestManager{
-		request: request,
-		log:     log,
+		request:    request,
+		log:        log,
+		retry:      retry.NewPolicy(conf.Config.Retry),
+		dispatcher: dispatcher.NewDispatcher(log, conf.Config.Dispatch),
+		cursor:     dispatcher.NewCursor(),
 	}
 	return nil
 }
 
+// Dispatch 解析跨链事件，放入源链的转发队列，队列满时阻塞
+//
+//	@receiver r
+//	@param eventInfo
+func (r *RequestManager) Dispatch(eventInfo *utils.EventInfo) {
+	beginCrossChainRequest, err := r.buildRequest(eventInfo)
+	if err != nil && !errors.Is(err, eventcache.ErrLoadConfig) {
+		return
+	}
+	r.beginForward(eventInfo)
+	if err != nil {
+		// 没有加载到跨链事件配置时在转发队列中重试，不阻塞事件订阅
+		r.dispatcher.Dispatch(eventInfo.ChainRid, eventInfo.ContractName, func() {
+			r.retryForward(eventInfo)
+		})
+		return
+	}
+	// 同一个源合约的请求按照顺序转发
+	r.dispatcher.Dispatch(eventInfo.ChainRid, beginCrossChainRequest.ConfirmInfo.ContractName, func() {
+		r.forward(eventInfo, beginCrossChainRequest)
+	})
+}
+
 // BeginCrossChain 如果要保存跨链信息的话，在这个函数里面实现就可以,可以根据结果写入数据库或者文件什么的都可以
 //
 //	@receiver r
 //	@param eventInfo
 func (r *RequestManager) BeginCrossChain(eventInfo *utils.EventInfo) {
+	beginCrossChainRequest, err := r.buildRequest(eventInfo)
+	if err != nil && !errors.Is(err, eventcache.ErrLoadConfig) {
+		return
+	}
+	r.beginForward(eventInfo)
+	if err != nil {
+		r.retryForward(eventInfo)
+		return
+	}
+	r.forward(eventInfo, beginCrossChainRequest)
+}
+
+// retryForward 没有加载到跨链事件配置时按照重试策略重新构建跨链请求并转发，
+// 重试耗尽时放入死信队列，重新加载后和配置不一致时拒绝，都会推进事件游标
+//
+//	@receiver r
+//	@param eventInfo
+func (r *RequestManager) retryForward(eventInfo *utils.EventInfo) {
+	start := time.Now()
+	attempts := 1
+	for {
+		if r.retry.Exhausted(attempts, start) {
+			r.deadLetterEvent(eventInfo, attempts,
+				fmt.Errorf("retry exhausted after %d attempts: %s", attempts, eventcache.ErrLoadConfig.Error()))
+			break
+		}
+		r.retry.Wait(attempts)
+		attempts++
+		beginCrossChainRequest, err := r.buildRequest(eventInfo)
+		if err == nil {
+			r.forward(eventInfo, beginCrossChainRequest)
+			return
+		}
+		if !errors.Is(err, eventcache.ErrLoadConfig) {
+			break
+		}
+	}
+	r.doneForward(eventInfo)
+}
+
+// buildRequest 解析跨链事件中的跨链请求
+//
+//	@receiver r
+//	@param eventInfo
+//	@return *relay_chain.BeginCrossChainRequest
+//	@return error
+func (r *RequestManager) buildRequest(eventInfo *utils.EventInfo) (*relay_chain.BeginCrossChainRequest, error) {
 	beginCrossChainRequest, err := r.buildCrossChainMsg(eventInfo)
 	if err != nil {
 		r.log.Errorf("[BeginCrossChain] %s", err.Error())
-		return
+		return nil, err
 	}
 	if beginCrossChainRequest == nil {
 		r.log.Warnf("[BeginCrossChain] build beginCrossChainRequest failed: topic %s", eventInfo.Topic)
-		return
+		return nil, errors.New("build beginCrossChainRequest failed")
 	}
-	r.log.Info("[BeginCrossChain] Call tcip-relayer BeginCrossChain method start: topic %s, request %+v",
+	return beginCrossChainRequest, nil
+}
+
+// forward 把跨链请求转发给中继网关，失败的请求放入死信队列
+//
+//	@receiver r
+//	@param eventInfo
+//	@param beginCrossChainRequest
+func (r *RequestManager) forward(eventInfo *utils.EventInfo, beginCrossChainRequest *relay_chain.BeginCrossChainRequest) {
+	r.log.Infof("[BeginCrossChain] Call tcip-relayer BeginCrossChain method start: topic %s, request %+v",
 		eventInfo.Topic, beginCrossChainRequest)
 
-	resString := []byte("")
-	// 5秒重试一次
-	for {
-		res, err := r.request.BeginCrossChain(beginCrossChainRequest)
-		// 这个错就是网络问题，也得重试
-		if err != nil {
-			r.log.Errorf("[BeginCrossChain] Call tcip-relayer BeginCrossChain method "+
-				"error: topic %s, error %s, txId: %s",
-				eventInfo.Topic, err.Error(), eventInfo.TxId)
-			time.Sleep(time.Second * 5)
-			continue
-		}
-		resString, _ = json.Marshal(res)
-		// 这里出错有两种可能，一种是中继链上链失败，中继链挂了或者中继网关和中继链网络断了，那没办法，重试就行了
-		// 另一种是参数错误，几乎不可能
-		if res.Code != common.Code_GATEWAY_SUCCESS {
-			if res.Code == common.Code_GATEWAY_DISABLED {
-				panic("this gateway is disabled, please contact admin")
-			}
-			r.log.Errorf("[BeginCrossChain] Call tcip-relayer BeginCrossChain method "+
-				"error: topic %s, response %s, txId: %s",
-				eventInfo.Topic, string(resString), eventInfo.TxId)
-			time.Sleep(time.Second * 5)
-			continue
-		}
-		_ = r.setLaseCrossHeight(eventInfo.ChainRid, eventInfo.BlockHeight)
-		break
+	letter := &deadletter.Letter{
+		Kind:        deadletter.KindBeginCrossChain,
+		ChainRid:    eventInfo.ChainRid,
+		TxId:        eventInfo.TxId,
+		BlockHeight: eventInfo.BlockHeight,
+		FirstTime:   time.Now().Unix(),
 	}
-	r.log.Infof("[BeginCrossChain] Call tcip-relayer BeginCrossChain method "+
-		"success: topic %s, response %s, txId %s",
-		eventInfo.Topic, string(resString), eventInfo.TxId)
+	attempts, err := r.beginCrossChain(eventInfo.ChainRid, beginCrossChainRequest)
+	if err != nil {
+		r.deadLetter(letter, beginCrossChainRequest, attempts, err)
+	}
+	r.doneForward(eventInfo)
 }
 
-// SyncBlockHeader 同步区块头
+// beginForward 事件放入转发队列前记录到事件游标中，重放的事件不影响游标
+//
+//	@receiver r
+//	@param eventInfo
+func (r *RequestManager) beginForward(eventInfo *utils.EventInfo) {
+	if !eventInfo.Replay {
+		r.cursor.Begin(eventInfo.ChainRid, eventInfo.BlockHeight)
+	}
+}
+
+// doneForward 事件处理完成后推进事件游标，进入死信队列的请求由运维处理，扫描高度照常推进，
+// 重放的事件不能让游标回退，多个协程并发转发时游标只推进到之前的事件全部转发完成的高度
+//
+//	@receiver r
+//	@param eventInfo
+func (r *RequestManager) doneForward(eventInfo *utils.EventInfo) {
+	if !eventInfo.Replay {
+		r.cursor.Done(eventInfo.ChainRid, eventInfo.BlockHeight, func(height int64) {
+			_ = r.setLaseCrossHeight(eventInfo.ChainRid, height)
+		})
+	}
+}
+
+// SyncBlockHeader 同步区块头，重试耗尽时返回错误，后面的区块头不能继续同步，
+// 不放入死信队列，下一轮同步会从已经同步成功的高度重新开始
 //
 //	@receiver r
 //	@param blockHeader
 //	@param chainRid
+//	@return error
 func (r *RequestManager) SyncBlockHeader(blockHeader *bcostypes.Block,
-	blockHeaderBatch []string, chainRid string, successHeight uint64) {
+	blockHeaderBatch []string, chainRid string, successHeight uint64) error {
 	var (
 		blockHeaderByte      []byte
 		blockHeaderBatchByte []byte
@@ -124,20 +232,19 @@ func (r *RequestManager) SyncBlockHeader(blockHeader *bcostypes.Block,
 	)
 
 	r.log.Infof("[SyncBlockHeader] start: chainId: %s, blockHeader: %d", chainRid, successHeight)
-	beginTime := time.Now().Unix()
 	if blockHeaderBatch != nil {
 		blockHeaderBatchByte, err = json.Marshal(blockHeaderBatch)
 		if err != nil {
 			r.log.Errorf("[SyncBlockHeader]Marshal blockHeaderBatch failed: error: %s, chainId: %s",
 				err.Error(), chainRid)
-			return
+			return err
 		}
 	} else {
 		blockHeaderByte, err = json.Marshal(blockHeader)
 		if err != nil {
 			r.log.Errorf("[SyncBlockHeader]Marshal blockHeader failed: error: %s, chainId: %s",
 				err.Error(), chainRid)
-			return
+			return err
 		}
 	}
 	request := &relay_chain.SyncBlockHeaderRequest{
@@ -148,24 +255,277 @@ func (r *RequestManager) SyncBlockHeader(blockHeader *bcostypes.Block,
 		BlockHeader:     blockHeaderByte,
 		BlockHeaderBath: blockHeaderBatchByte,
 	}
-	for {
-		res, err := r.request.SyncBlockHeader(request)
+	_, err = r.syncBlockHeader(request)
+	return err
+}
+
+// Redrive 重新发送死信队列中的请求，成功后删除死信，失败时更新死信中的错误信息
+//
+//	@receiver r
+//	@param id
+//	@return error
+func (r *RequestManager) Redrive(id string) error {
+	letter, err := deadletter.Get(id)
+	if err != nil {
+		r.log.Errorf("[Redrive] %s", err.Error())
+		return err
+	}
+	if letter == nil {
+		return fmt.Errorf("dead letter %s not found", id)
+	}
+	switch letter.Kind {
+	case deadletter.KindBeginCrossChain:
+		req := &relay_chain.BeginCrossChainRequest{}
+		if err = proto.Unmarshal(letter.Request, req); err != nil {
+			return fmt.Errorf("unmarshal dead letter %s error: %s", id, err.Error())
+		}
+		attempts, err := r.beginCrossChain(letter.ChainRid, req)
+		if err != nil {
+			r.deadLetter(letter, req, attempts, err)
+			return err
+		}
+	case deadletter.KindCrossChainEvent:
+		eventInfo := &utils.EventInfo{}
+		if err = json.Unmarshal(letter.Request, eventInfo); err != nil {
+			return fmt.Errorf("unmarshal dead letter %s error: %s", id, err.Error())
+		}
+		// 重新发送的事件不影响事件游标
+		eventInfo.Replay = true
+		req, err := r.buildRequest(eventInfo)
+		if err != nil {
+			r.saveLetter(letter, letter.Request, 1, err)
+			return err
+		}
+		// 构建成功后转发失败时作为跨链请求放入死信队列
+		letter.Kind = deadletter.KindBeginCrossChain
+		attempts, err := r.beginCrossChain(letter.ChainRid, req)
+		if err != nil {
+			r.deadLetter(letter, req, attempts, err)
+			return err
+		}
+	case deadletter.KindSyncBlockHeader:
+		// 旧版本放入的区块头死信，重发会打乱区块头的同步顺序，
+		// 区块头同步会从已经同步成功的高度重新开始，直接删除
+		r.log.Warnf("[Redrive] drop block header dead letter %s: chainRid %s, blockHeight %d, "+
+			"block headers are resynced from the last synced height", id, letter.ChainRid, letter.BlockHeight)
+		return deadletter.Delete(id)
+	default:
+		return fmt.Errorf("unsupported dead letter kind %s", letter.Kind)
+	}
+	r.log.Infof("[Redrive] dead letter %s redriven: kind %s, chainRid %s", id, letter.Kind, letter.ChainRid)
+	return deadletter.Delete(id)
+}
+
+// beginCrossChain 按照重试策略调用中继网关的BeginCrossChain
+//
+//	@receiver r
+//	@param chainRid 源链资源id
+//	@param req
+//	@return int 调用次数
+//	@return error
+func (r *RequestManager) beginCrossChain(chainRid string, req *relay_chain.BeginCrossChainRequest) (int, error) {
+	start := time.Now()
+	txId := ""
+	if req.TxContent != nil {
+		txId = req.TxContent.TxId
+	}
+	crossChainId := ""
+	attempts, err := r.relayDo(func() (*retry.Result, error) {
+		res, err := r.request.BeginCrossChain(req)
+		if err != nil {
+			r.log.Errorf("[BeginCrossChain] Call tcip-relayer BeginCrossChain method "+
+				"error: error %s, txId: %s", err.Error(), txId)
+			return nil, err
+		}
+		crossChainId = res.CrossChainId
+		if res.Code != common.Code_GATEWAY_SUCCESS {
+			resString, _ := json.Marshal(res)
+			r.log.Errorf("[BeginCrossChain] Call tcip-relayer BeginCrossChain method "+
+				"error: response %s, txId: %s", string(resString), txId)
+		}
+		return &retry.Result{Code: res.Code, Message: res.Message}, nil
+	})
+	observeForward(chainRid, deadletter.KindBeginCrossChain, start, attempts, err)
+	r.recordBegin(chainRid, crossChainId, req, err)
+	if err != nil {
+		return attempts, err
+	}
+	r.log.Infof("[BeginCrossChain] Call tcip-relayer BeginCrossChain method success: txId %s, attempts %d",
+		txId, attempts)
+	return attempts, nil
+}
+
+// recordBegin 保存转发跨链请求的交易记录，保存失败不影响转发
+//
+//	@receiver r
+//	@param chainRid
+//	@param crossChainId 中继网关分配的跨链id，转发失败时可能为空
+//	@param req
+//	@param err
+func (r *RequestManager) recordBegin(chainRid, crossChainId string, req *relay_chain.BeginCrossChainRequest,
+	err error) {
+	record := &txrecord.Record{
+		CrossChainId: crossChainId,
+		Phase:        txrecord.PhaseBegin,
+		ChainRid:     chainRid,
+		Success:      err == nil,
+	}
+	if err != nil {
+		record.Message = err.Error()
+	}
+	if req.TxContent != nil {
+		record.TxId = req.TxContent.TxId
+		record.BlockHeight = req.TxContent.BlockHeight
+	}
+	if err = txrecord.Put(record); err != nil {
+		r.log.Warnf("[recordBegin] save tx record error: %s", err.Error())
+	}
+}
+
+// syncBlockHeader 按照重试策略调用中继网关的SyncBlockHeader
+//
+//	@receiver r
+//	@param req
+//	@return int 调用次数
+//	@return error
+func (r *RequestManager) syncBlockHeader(req *relay_chain.SyncBlockHeaderRequest) (int, error) {
+	start := time.Now()
+	beginTime := start.Unix()
+	attempts, err := r.relayDo(func() (*retry.Result, error) {
+		res, err := r.request.SyncBlockHeader(req)
 		if err != nil {
 			r.log.Errorf("[SyncBlockHeader]Request SyncBlockHeader failed: error: %s, chainId: %s",
-				err.Error(), chainRid)
-			time.Sleep(time.Second * 5)
-			continue
+				err.Error(), req.ChainRid)
+			return nil, err
 		}
-		if res.Code == common.Code_GATEWAY_SUCCESS {
-			r.log.Infof("[SyncBlockHeader]SyncBlockHeader success: chainId: %s, blockHeight: %d,"+
-				" message: %s, timeUsed: %d",
-				chainRid, successHeight, res.Message, time.Now().Unix()-beginTime)
-			_ = r.setLaseBlockHeaderHeight(chainRid, int64(successHeight))
+		if res.Code != common.Code_GATEWAY_SUCCESS {
+			r.log.Errorf("[SyncBlockHeader]Request SyncBlockHeader failed: code: %d, error: %s, "+
+				"chainId: %s, blockHeight: %d", res.Code, res.Message, req.ChainRid, req.BlockHeight)
+		}
+		return &retry.Result{Code: res.Code, Message: res.Message}, nil
+	})
+	observeForward(req.ChainRid, deadletter.KindSyncBlockHeader, start, attempts, err)
+	if err != nil {
+		return attempts, err
+	}
+	r.log.Infof("[SyncBlockHeader]SyncBlockHeader success: chainId: %s, blockHeight: %d, timeUsed: %d",
+		req.ChainRid, req.BlockHeight, time.Now().Unix()-beginTime)
+	_ = r.setLaseBlockHeaderHeight(req.ChainRid, int64(req.BlockHeight))
+	return attempts, nil
+}
+
+// observeForward 记录转发的耗时和重试次数
+//
+//	@param chainRid
+//	@param kind
+//	@param start
+//	@param attempts
+//	@param err
+func observeForward(chainRid, kind string, start time.Time, attempts int, err error) {
+	metrics.ObserveSince(metrics.ForwardDuration.WithLabelValues(chainRid, kind, metrics.Result(err)), start)
+	if attempts > 1 {
+		metrics.ForwardRetries.WithLabelValues(chainRid, kind).Add(float64(attempts - 1))
+	}
+}
+
+// relayDo 按照重试策略调用中继网关，网关被禁用时暂停转发，直到探测到中继网关重新启用本网关
+//
+//	@receiver r
+//	@param call
+//	@return int 调用次数
+//	@return error
+func (r *RequestManager) relayDo(call func() (*retry.Result, error)) (int, error) {
+	total := 0
+	for {
+		attempts, err := r.retry.Do(func() (*retry.Result, error) {
+			r.waitEnabled()
+			res, err := call()
+			// 中继网关正常处理了请求，说明本网关已经被重新启用
+			if err == nil && res.Code != common.Code_GATEWAY_DISABLED && gateway.Enable() {
+				r.log.Infof("[relayDo] gateway is enabled by relay gateway, resume forwarding")
+			}
+			return res, err
+		})
+		total += attempts
+		if !errors.Is(err, retry.ErrGatewayDisabled) {
+			return total, err
+		}
+		if gateway.Disable(err.Error()) {
+			r.log.Errorf("[relayDo] gateway is disabled by relay gateway, pause forwarding until it is enabled")
+		}
+	}
+}
+
+// waitEnabled 网关被禁用时等待恢复，每个探测间隔放行一个请求用来探测
+//
+//	@receiver r
+func (r *RequestManager) waitEnabled() {
+	interval := defaultProbeInterval
+	if conf.Config.Relay.DisabledProbeInterval > 0 {
+		interval = time.Duration(conf.Config.Relay.DisabledProbeInterval) * time.Second
+	}
+	for gateway.IsDisabled() {
+		if gateway.TryProbe(interval) {
 			return
 		}
-		time.Sleep(time.Second * 5)
-		r.log.Errorf("[SyncBlockHeader]Request SyncBlockHeader failed: code: %d, error: %s, chainId: %s, blockHeight: %d",
-			res.Code, res.Message, chainRid, successHeight)
+		gateway.WaitEnabled(interval)
+	}
+}
+
+// deadLetter 把重试失败的请求放入死信队列
+//
+//	@receiver r
+//	@param letter
+//	@param req
+//	@param attempts
+//	@param cause
+func (r *RequestManager) deadLetter(letter *deadletter.Letter, req proto.Message, attempts int, cause error) {
+	reqByte, err := proto.Marshal(req)
+	if err != nil {
+		r.log.Errorf("[deadLetter] marshal request error: %s", err.Error())
+		return
+	}
+	r.saveLetter(letter, reqByte, attempts, cause)
+}
+
+// deadLetterEvent 把重试耗尽仍然没有加载到跨链事件配置的跨链事件放入死信队列
+//
+//	@receiver r
+//	@param eventInfo
+//	@param attempts
+//	@param cause
+func (r *RequestManager) deadLetterEvent(eventInfo *utils.EventInfo, attempts int, cause error) {
+	eventByte, err := json.Marshal(eventInfo)
+	if err != nil {
+		r.log.Errorf("[deadLetterEvent] marshal event error: %s", err.Error())
+		return
+	}
+	letter := &deadletter.Letter{
+		Kind:        deadletter.KindCrossChainEvent,
+		ChainRid:    eventInfo.ChainRid,
+		TxId:        eventInfo.TxId,
+		BlockHeight: eventInfo.BlockHeight,
+		FirstTime:   time.Now().Unix(),
+	}
+	r.saveLetter(letter, eventByte, attempts, cause)
+}
+
+// saveLetter 保存死信
+//
+//	@receiver r
+//	@param letter
+//	@param reqByte 序列化后的请求
+//	@param attempts
+//	@param cause
+func (r *RequestManager) saveLetter(letter *deadletter.Letter, reqByte []byte, attempts int, cause error) {
+	r.log.Errorf("[deadLetter] %s request failed: chainRid %s, blockHeight %d, error %s",
+		letter.Kind, letter.ChainRid, letter.BlockHeight, cause.Error())
+	letter.Request = reqByte
+	letter.Error = cause.Error()
+	letter.Attempts += attempts
+	letter.LastTime = time.Now().Unix()
+	if err := deadletter.Put(letter); err != nil {
+		r.log.Errorf("[deadLetter] save dead letter error: %s", err.Error())
 	}
 }
 
@@ -223,6 +583,23 @@ func (r *RequestManager) buildBeginCrossChainRequestFromEvent(
 		r.log.Warnf(msg)
 		return nil, errors.New(msg)
 	}
+	// 事件中的跨链请求必须和触发交易对应的跨链事件配置一致，防止篡改目标链
+	var tx bcostypes.TransactionDetail
+	if eventInfo.Tx != nil {
+		_ = json.Unmarshal(eventInfo.Tx, &tx)
+	}
+	err = eventcache.CheckRequest(eventInfo.ChainRid, eventInfo.ContractName, tx.Input, reqData,
+		&beginCrossChainRequest)
+	if errors.Is(err, eventcache.ErrLoadConfig) {
+		r.log.Errorf("[buildBeginCrossChainRequestFromEvent] %s: topic %s", err.Error(), eventInfo.Topic)
+		return nil, err
+	}
+	if err != nil {
+		msg := fmt.Sprintf("[buildBeginCrossChainRequestFromEvent] This topic is not a "+
+			"registered cross chain event: topic %s, error %s", eventInfo.Topic, err.Error())
+		r.log.Warnf(msg)
+		return nil, errors.New(msg)
+	}
 	var triggerInfo common.TriggerInfo
 	err = proto.Unmarshal(paramData, &triggerInfo)
 	// 反序列化请求失败，表明数据错误
@@ -247,15 +624,56 @@ func (r *RequestManager) buildBeginCrossChainRequestFromEvent(
 		TxProve:     eventInfo.TxProve,
 		BlockHeight: eventInfo.BlockHeight,
 	}
-	beginCrossChainRequest.Timeout = int64(conf.Config.BaseConfig.DefaultTimeout)
+	beginCrossChainRequest.Timeout = int64(conf.DefaultTimeout())
 	beginCrossChainRequest.ConfirmInfo.Parameter = triggerInfo.SrcConfirmParam
 	beginCrossChainRequest.CancelInfo.Parameter = triggerInfo.SrcCancelParam
-	beginCrossChainRequest.CrossChainMsg[0].Parameter = triggerInfo.DestTryParam
-	beginCrossChainRequest.CrossChainMsg[0].ConfirmInfo.Parameter = triggerInfo.DestConfirmParam
-	beginCrossChainRequest.CrossChainMsg[0].CancelInfo.Parameter = triggerInfo.DestCancelParam
+	// 多个跨链目标时，目标参数按照下标对应到每个跨链消息
+	count := len(beginCrossChainRequest.CrossChainMsg)
+	tryParams, err := utils.SplitDestParam("DestTryParam", triggerInfo.DestTryParam, count)
+	if err != nil {
+		msg := fmt.Sprintf("[buildBeginCrossChainRequestFromEvent] %s, topic %s", err.Error(), eventInfo.Topic)
+		r.log.Warnf(msg)
+		return nil, errors.New(msg)
+	}
+	confirmParams, err := utils.SplitDestParam("DestConfirmParam", triggerInfo.DestConfirmParam, count)
+	if err != nil {
+		msg := fmt.Sprintf("[buildBeginCrossChainRequestFromEvent] %s, topic %s", err.Error(), eventInfo.Topic)
+		r.log.Warnf(msg)
+		return nil, errors.New(msg)
+	}
+	cancelParams, err := utils.SplitDestParam("DestCancelParam", triggerInfo.DestCancelParam, count)
+	if err != nil {
+		msg := fmt.Sprintf("[buildBeginCrossChainRequestFromEvent] %s, topic %s", err.Error(), eventInfo.Topic)
+		r.log.Warnf(msg)
+		return nil, errors.New(msg)
+	}
+	for i, crossChainMsg := range beginCrossChainRequest.CrossChainMsg {
+		crossChainMsg.Parameter = tryParams[i]
+		crossChainMsg.ConfirmInfo.Parameter = confirmParams[i]
+		crossChainMsg.CancelInfo.Parameter = cancelParams[i]
+	}
 	return &beginCrossChainRequest, nil
 }
 
+// QueueDepth 每条源链等待转发的跨链请求数
+//
+//	@receiver r
+//	@return map[string]int
+func (r *RequestManager) QueueDepth() map[string]int {
+	return r.dispatcher.Depth()
+}
+
+// EndpointStatus 全部中继网关实例的健康状态，请求方式没有实例信息时返回nil
+//
+//	@receiver r
+//	@return []*endpoint.Status
+func (r *RequestManager) EndpointStatus() []*endpoint.Status {
+	if statusRequest, ok := r.request.(interface{ EndpointStatus() []*endpoint.Status }); ok {
+		return statusRequest.EndpointStatus()
+	}
+	return nil
+}
+
 func (r *RequestManager) setLaseBlockHeaderHeight(chainRid string, height int64) error {
 	err := db.Db.Put([]byte(fmt.Sprintf("%s_last_block_header_height", chainRid)),
 		[]byte(fmt.Sprintf("%d", height)))
@@ -263,6 +681,7 @@ func (r *RequestManager) setLaseBlockHeaderHeight(chainRid string, height int64)
 		r.log.Errorf("[getLaseBlockHeaderHeight] %s", err.Error())
 		return fmt.Errorf("[getLaseBlockHeaderHeight] %s", err.Error())
 	}
+	metrics.HeaderSyncHeight.WithLabelValues(chainRid).Set(float64(height))
 	return nil
 }
 
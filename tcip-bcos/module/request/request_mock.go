@@ -8,7 +8,10 @@ package request
 import (
 	"errors"
 
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/dispatcher"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/retry"
 	"chainmaker.org/chainmaker/tcip-go/v2/common"
 	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
 )
@@ -16,6 +19,43 @@ import (
 type requestMock struct {
 }
 
+// GatewayRegister 注册网关
+//  @receiver r
+//  @param req
+//  @return *relay_chain.GatewayRegisterResponse
+//  @return error
+func (r *requestMock) GatewayRegister(
+	req *relay_chain.GatewayRegisterRequest) (*relay_chain.GatewayRegisterResponse, error) {
+	switch req.Version {
+	case common.Version_V1_0_0:
+		return &relay_chain.GatewayRegisterResponse{
+			GatewayId: "0",
+			Code:      common.Code_GATEWAY_SUCCESS,
+			Message:   common.Code_GATEWAY_SUCCESS.String(),
+		}, nil
+	default:
+		return nil, errors.New("unsupported version")
+	}
+}
+
+// GatewayUpdate 更新网关
+//  @receiver r
+//  @param req
+//  @return *relay_chain.GatewayUpdateResponse
+//  @return error
+func (r *requestMock) GatewayUpdate(
+	req *relay_chain.GatewayUpdateRequest) (*relay_chain.GatewayUpdateResponse, error) {
+	switch req.Version {
+	case common.Version_V1_0_0:
+		return &relay_chain.GatewayUpdateResponse{
+			Code:    common.Code_GATEWAY_SUCCESS,
+			Message: common.Code_GATEWAY_SUCCESS.String(),
+		}, nil
+	default:
+		return nil, errors.New("unsupported version")
+	}
+}
+
 // BeginCrossChain 发送跨链请求
 //  @receiver r
 //  @param req
@@ -93,8 +133,11 @@ func (r *requestMock) UpdateSpvContract(
 func InitRequestManagerMock() error {
 	log := logger.GetLogger(logger.ModuleRequest)
 	RequestV1 = &RequestManager{
-		request: &requestMock{},
-		log:     log,
+		request:    &requestMock{},
+		log:        log,
+		retry:      retry.NewPolicy(conf.Config.Retry),
+		dispatcher: dispatcher.NewDispatcher(log, conf.Config.Dispatch),
+		cursor:     dispatcher.NewCursor(),
 	}
 	return nil
 }
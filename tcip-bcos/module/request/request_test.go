@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 package request
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path"
 	"reflect"
@@ -13,6 +17,15 @@ import (
 	"time"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/deadletter"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/eventcache"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/utils"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+	bcosabi "github.com/FISCO-BCOS/go-sdk/abi"
+	bcostypes "github.com/FISCO-BCOS/go-sdk/core/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 
@@ -35,7 +48,6 @@ func testInit() {
 		Address:      "https://127.0.0.1:19999",
 		ServerName:   "chainmaker.org",
 		Tlsca:        "../../config/cert/client/ca.crt",
-		ClientKey:    "../../config/cert/client/client.key",
 		ClientCert:   "../../config/cert/client/client.crt",
 		TxVerifyType: "notneed",
 		CallType:     "grpc",
@@ -46,6 +58,10 @@ func testInit() {
 
 func TestRequestManager_GatewayRegister(t *testing.T) {
 	testInit()
+	// 注册成功后网关ID写回配置文件
+	configPath := path.Join(os.TempDir(), time.Now().String())
+	_ = os.WriteFile(configPath, []byte("base:\n  gateway_id:\n"), 0600)
+	defer os.Remove(configPath)
 	type fields struct {
 		log     *zap.SugaredLogger
 		request Request
@@ -66,7 +82,7 @@ func TestRequestManager_GatewayRegister(t *testing.T) {
 				request: RequestV1.request,
 			},
 			args: args{
-				objectPath: path.Join(os.TempDir(), time.Now().String()),
+				objectPath: configPath,
 			},
 			wantErr: false,
 		},
@@ -117,14 +133,14 @@ func TestRequestManager_GatewayUpdate(t *testing.T) {
 				log:     tt.fields.log,
 				request: tt.fields.request,
 			}
-			if err := r.GatewayUpdate(tt.args.objectPath); (err != nil) != tt.wantErr {
+			if err := r.GatewayUpdate(); (err != nil) != tt.wantErr {
 				t.Errorf("GatewayUpdate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestRequestManager_InitSpvContracta(t *testing.T) {
+func TestRequestManager_InitSpvContract(t *testing.T) {
 	testInit()
 	type fields struct {
 		log     *zap.SugaredLogger
@@ -165,8 +181,8 @@ func TestRequestManager_InitSpvContracta(t *testing.T) {
 				log:     tt.fields.log,
 				request: tt.fields.request,
 			}
-			if err := r.InitSpvContracta(tt.args.version, tt.args.path, tt.args.runtimeType, tt.args.kvJsonStr, tt.args.chainId); (err != nil) != tt.wantErr {
-				t.Errorf("InitSpvContracta() error = %v, wantErr %v", err, tt.wantErr)
+			if err := r.InitSpvContract(tt.args.version, tt.args.path, tt.args.runtimeType, tt.args.kvJsonStr, tt.args.chainId); (err != nil) != tt.wantErr {
+				t.Errorf("InitSpvContract() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -338,3 +354,110 @@ func Test_getTxVerifyType(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestManager_Redrive(t *testing.T) {
+	testInit()
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	db.NewDbHandle()
+	defer db.Db.Close()
+
+	// 旧版本放入的区块头死信直接删除，不重新发送
+	letter := &deadletter.Letter{Kind: deadletter.KindSyncBlockHeader, ChainRid: "chain1", BlockHeight: 10}
+	assert.Nil(t, deadletter.Put(letter))
+	assert.Nil(t, RequestV1.Redrive(letter.Id))
+	got, err := deadletter.Get(letter.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+
+	assert.NotNil(t, RequestV1.Redrive("not-exist"))
+}
+
+// newTriggerEvent 配置合约触发跨链的事件，交易参数中有CrossId
+func newTriggerEvent(event *common.NewCrossChain, height int64) *utils.EventInfo {
+	req := &relay_chain.BeginCrossChainRequest{
+		Version: common.Version_V1_0_0,
+		From:    conf.Config.BaseConfig.GatewayID,
+		CrossChainMsg: []*common.CrossChainMsg{
+			{
+				GatewayId:    event.DestGatewayId,
+				ChainRid:     event.DestChainRid,
+				ContractName: event.DestContractName,
+				Method:       event.DestTryMethod,
+				ConfirmInfo:  &common.ConfirmInfo{Method: event.DestConfirmMethod},
+				CancelInfo:   &common.CancelInfo{Method: event.DestCancelMethod},
+			},
+		},
+		ConfirmInfo: &common.ConfirmInfo{ChainRid: event.SrcChainRid, ContractName: event.SrcContractName},
+		CancelInfo:  &common.CancelInfo{ChainRid: event.SrcChainRid, ContractName: event.SrcContractName},
+	}
+	reqData, _ := proto.Marshal(req)
+	triggerData, _ := proto.Marshal(&common.TriggerInfo{})
+	stringType, _ := bcosabi.NewType("string", "", nil)
+	packed, _ := bcosabi.Arguments{{Type: stringType}}.Pack(event.CrossId)
+	tx, _ := json.Marshal(&bcostypes.TransactionDetail{Input: "0x12345678" + hex.EncodeToString(packed)})
+	return &utils.EventInfo{
+		ChainRid:     event.SrcChainRid,
+		ContractName: "config",
+		Data: []string{base64.StdEncoding.EncodeToString(reqData),
+			base64.StdEncoding.EncodeToString(triggerData)},
+		Tx:          tx,
+		TxId:        fmt.Sprintf("tx%d", height),
+		BlockHeight: height,
+	}
+}
+
+func TestRequestManager_RetryForward(t *testing.T) {
+	testInit()
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	db.NewDbHandle()
+	defer db.Db.Close()
+	conf.Config.Relay = &conf.Relay{}
+	RequestV1.retry.MaxAttempts = 2
+	RequestV1.retry.InitialInterval = time.Millisecond
+
+	event := &common.NewCrossChain{
+		CrossId:           "cross1",
+		SrcChainRid:       "chain1",
+		SrcContractName:   "src",
+		DestGatewayId:     "1",
+		DestChainRid:      "chain2",
+		DestContractName:  "dest",
+		DestTryMethod:     "try",
+		DestConfirmMethod: "confirm",
+		DestCancelMethod:  "cancel",
+	}
+	loaded := false
+	eventcache.SetLoader(func(chainRid, configConstractName, crossId string) (*common.NewCrossChain, error) {
+		if !loaded {
+			return nil, fmt.Errorf("chain unavailable")
+		}
+		return event, nil
+	})
+	defer eventcache.SetLoader(nil)
+
+	// 没有加载到配置时重试耗尽放入死信队列，事件游标照常推进
+	RequestV1.BeginCrossChain(newTriggerEvent(event, 10))
+	letters, err := deadletter.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(letters))
+	assert.Equal(t, deadletter.KindCrossChainEvent, letters[0].Kind)
+	assert.Equal(t, 2, letters[0].Attempts)
+	height, err := db.Db.Get([]byte("chain1_last_cross_height"))
+	assert.Nil(t, err)
+	assert.Equal(t, "10", string(height))
+
+	// 配置可以加载后重新发送成功
+	loaded = true
+	assert.Nil(t, RequestV1.Redrive(letters[0].Id))
+	letters, err = deadletter.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(letters))
+
+	// 和配置不一致时拒绝，不放入死信队列
+	tampered := *event
+	tampered.DestChainRid = "evil"
+	RequestV1.BeginCrossChain(newTriggerEvent(&tampered, 11))
+	letters, err = deadletter.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(letters))
+}
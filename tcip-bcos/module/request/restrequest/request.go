@@ -6,60 +6,452 @@ SPDX-License-Identifier: Apache-2.0
 package restrequest
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cmtls "chainmaker.org/chainmaker/common/v2/crypto/tls"
+	cmx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/certwatch"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
 	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// 中继网关grpc-gateway的接口路径
+const (
+	gatewayRegisterPath   = "/v1/GatewayRegister"
+	gatewayUpdatePath     = "/v1/GatewayUpdate"
+	beginCrossChainPath   = "/v1/BeginCrossChain"
+	syncBlockHeaderPath   = "/v1/SyncBlockHeader"
+	initSpvContractPath   = "/v1/InitContract"
+	updateSpvContractPath = "/v1/UpdateContract"
+
+	// tokenHeader grpc-gateway只会把Grpc-Metadata-前缀的header转为grpc metadata
+	tokenHeader         = "x-token"
+	tokenMetadataHeader = "Grpc-Metadata-X-Token"
+	contentType         = "application/json"
+)
+
+// errNilRequest 请求为空
+var errNilRequest = status.Error(codes.InvalidArgument, "request is nil")
+
+// gatewayError grpc-gateway返回的错误结构
+type gatewayError struct {
+	Error   string `json:"error"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
 // RestRequest rest请求结构体
 type RestRequest struct {
-	log *zap.SugaredLogger
+	log          *zap.SugaredLogger
+	marshaler    *runtime.JSONPb
+	selector     *endpoint.Selector
+	selectorOnce sync.Once
+	clients      map[*endpoint.Endpoint]*http.Client
+	lock         sync.Mutex
+	// certFingerprint 创建http客户端时的证书文件状态
+	certFingerprint string
+	lastCertCheck   time.Time
 }
 
 // NewRestRequest restrequest新建
-//  @param log
-//  @return *RestRequest
+//
+//	@param log
+//	@return *RestRequest
 func NewRestRequest(log *zap.SugaredLogger) *RestRequest {
 	return &RestRequest{
-		log: log,
+		log:       log,
+		marshaler: &runtime.JSONPb{OrigName: true, EmitDefaults: false, EnumsAsInts: true},
 	}
 }
 
+// GatewayRegister 注册网关
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.GatewayRegisterResponse
+//	@return error
+func (r *RestRequest) GatewayRegister(
+	req *relay_chain.GatewayRegisterRequest) (*relay_chain.GatewayRegisterResponse, error) {
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.GatewayRegisterResponse{}
+	if err := r.post(gatewayRegisterPath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GatewayUpdate 更新网关信息
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.GatewayUpdateResponse
+//	@return error
+func (r *RestRequest) GatewayUpdate(
+	req *relay_chain.GatewayUpdateRequest) (*relay_chain.GatewayUpdateResponse, error) {
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.GatewayUpdateResponse{}
+	if err := r.post(gatewayUpdatePath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // BeginCrossChain 开始跨链
-//  @receiver r
-//  @param req
-//  @return *relay_chain.BeginCrossChainResponse
-//  @return error
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.BeginCrossChainResponse
+//	@return error
 func (r *RestRequest) BeginCrossChain(
 	req *relay_chain.BeginCrossChainRequest) (*relay_chain.BeginCrossChainResponse, error) {
-	panic("error")
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.BeginCrossChainResponse{}
+	if err := r.post(beginCrossChainPath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // SyncBlockHeader 同步区块头
-//  @receiver r
-//  @param req
-//  @return *relay_chain.SyncBlockHeaderResponse
-//  @return error
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.SyncBlockHeaderResponse
+//	@return error
 func (r *RestRequest) SyncBlockHeader(
 	req *relay_chain.SyncBlockHeaderRequest) (*relay_chain.SyncBlockHeaderResponse, error) {
-	panic("error")
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.SyncBlockHeaderResponse{}
+	if err := r.post(syncBlockHeaderPath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // InitSpvContract 初始化spv合约
-//  @receiver r
-//  @param req
-//  @return *relay_chain.InitContractResponse
-//  @return error
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.InitContractResponse
+//	@return error
 func (r *RestRequest) InitSpvContract(
 	req *relay_chain.InitContractRequest) (*relay_chain.InitContractResponse, error) {
-	panic("error")
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.InitContractResponse{}
+	if err := r.post(initSpvContractPath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // UpdateSpvContract 更新spv合约
-//  @receiver r
-//  @param req
-//  @return *relay_chain.UpdateContractResponse
-//  @return error
+//
+//	@receiver r
+//	@param req
+//	@return *relay_chain.UpdateContractResponse
+//	@return error
 func (r *RestRequest) UpdateSpvContract(
 	req *relay_chain.UpdateContractRequest) (*relay_chain.UpdateContractResponse, error) {
-	panic("error")
+	if req == nil {
+		return nil, errNilRequest
+	}
+	response := &relay_chain.UpdateContractResponse{}
+	if err := r.post(updateSpvContractPath, req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// post 调用中继网关的restful接口，实例不可用时切换到下一个实例，错误转换为和grpc一致的status error
+//
+//	@receiver r
+//	@param path
+//	@param req
+//	@param response
+//	@return error
+func (r *RestRequest) post(path string, req, response interface{}) error {
+	body, err := r.marshaler.Marshal(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "marshal request error: %s", err.Error())
+	}
+	return r.getSelector().Call(func(relay *endpoint.Endpoint) error {
+		return r.postTo(relay, path, body, response)
+	})
+}
+
+// postTo 调用一个中继网关实例的restful接口
+//
+//	@receiver r
+//	@param relay
+//	@param path
+//	@param body
+//	@param response
+//	@return error
+func (r *RestRequest) postTo(relay *endpoint.Endpoint, path string, body []byte, response interface{}) error {
+	client, err := r.getClient(relay)
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(conf.DefaultTimeout()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, getUrl(relay.Address, path), bytes.NewReader(body))
+	if err != nil {
+		return status.Errorf(codes.Internal, "build request error: %s", err.Error())
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set(tokenHeader, conf.Config.Relay.AccessCode)
+	httpReq.Header.Set(tokenMetadataHeader, conf.Config.Relay.AccessCode)
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "read response error: %s", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		r.log.Warnf("[postTo] %s%s http status %d: %s", relay.Address, path, resp.StatusCode, string(respBody))
+		return httpError(resp.StatusCode, respBody)
+	}
+	if err = r.marshaler.Unmarshal(respBody, response); err != nil {
+		return status.Errorf(codes.Internal, "unmarshal response error: %s", err.Error())
+	}
+	return nil
+}
+
+// getSelector 获取中继网关实例选择器，第一次使用时根据配置创建
+//
+//	@receiver r
+//	@return *endpoint.Selector
+func (r *RestRequest) getSelector() *endpoint.Selector {
+	r.selectorOnce.Do(func() {
+		r.selector = endpoint.NewSelector(conf.Config.Relay)
+	})
+	return r.selector
+}
+
+// EndpointStatus 全部中继网关实例的健康状态
+//
+//	@receiver r
+//	@return []*endpoint.Status
+func (r *RestRequest) EndpointStatus() []*endpoint.Status {
+	return r.getSelector().Status()
+}
+
+// getUrl 拼接接口地址，配置中没有协议时根据是否配置了tls证书决定
+//
+//	@param address 中继网关实例地址
+//	@param path
+//	@return string
+func getUrl(address, path string) string {
+	address = strings.TrimSuffix(address, "/")
+	if strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://") {
+		return address + path
+	}
+	if conf.Config.Relay.Tlsca == "" {
+		return "http://" + address + path
+	}
+	return "https://" + address + path
+}
+
+// getClient 获取中继网关实例的http客户端，配置了tls证书时使用双向认证，
+// tls连接由chainmaker的tls实现建立，支持国密证书
+//
+//	@receiver r
+//	@param relay
+//	@return *http.Client
+//	@return error
+func (r *RestRequest) getClient(relay *endpoint.Endpoint) (*http.Client, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.checkCerts()
+	if client, ok := r.clients[relay]; ok {
+		return client, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.Config.Relay.Tlsca != "" {
+		tlsConfig, err := getTlsConfig(relay.ServerName)
+		if err != nil {
+			r.log.Errorf("[getClient] %s", err.Error())
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		transport.DialTLSContext = dialTls(tlsConfig)
+	}
+	if r.clients == nil {
+		r.clients = make(map[*endpoint.Endpoint]*http.Client)
+	}
+	client := &http.Client{Transport: transport}
+	r.clients[relay] = client
+	return client, nil
+}
+
+// checkCerts 证书文件变化时丢弃已有的http客户端，新证书加载失败时继续使用旧的客户端，调用方需要持有锁
+//
+//	@receiver r
+func (r *RestRequest) checkCerts() {
+	if time.Since(r.lastCertCheck) < certwatch.CheckInterval {
+		return
+	}
+	r.lastCertCheck = time.Now()
+	fingerprint, err := certwatch.Fingerprint(conf.Config.Relay.Tlsca, conf.Config.Relay.ClientCert,
+		conf.Config.Relay.ClientKey)
+	if err != nil {
+		r.log.Errorf("[checkCerts] check relay certificates failed, keep the old ones: %s", err.Error())
+		return
+	}
+	if fingerprint == r.certFingerprint {
+		return
+	}
+	if r.certFingerprint != "" && len(r.clients) != 0 {
+		if conf.Config.Relay.Tlsca != "" {
+			if _, err = getTlsConfig(""); err != nil {
+				r.log.Errorf("[checkCerts] reload relay certificates failed, keep the old ones: %s", err.Error())
+				return
+			}
+		}
+		for _, client := range r.clients {
+			client.CloseIdleConnections()
+		}
+		r.clients = nil
+		r.log.Info("[checkCerts] relay certificates changed, rebuild http clients")
+	}
+	r.certFingerprint = fingerprint
+}
+
+// getTlsConfig 根据中继网关配置构建tls配置，证书可以是国密证书
+//
+//	@param serverName 中继网关实例证书中的域名
+//	@return *cmtls.Config
+//	@return error
+func getTlsConfig(serverName string) (*cmtls.Config, error) {
+	caCert, err := ioutil.ReadFile(conf.Config.Relay.Tlsca)
+	if err != nil {
+		return nil, err
+	}
+	certPool := cmx509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("invalid tls ca: %s", conf.Config.Relay.Tlsca)
+	}
+	tlsConfig := &cmtls.Config{
+		RootCAs:    certPool,
+		ServerName: serverName,
+		MinVersion: cmtls.VersionTLS12,
+	}
+	if conf.Config.Relay.ClientCert != "" {
+		certPem, err := ioutil.ReadFile(conf.Config.Relay.ClientCert)
+		if err != nil {
+			return nil, err
+		}
+		keyPem, err := certwatch.ReadKey(conf.Config.Relay.ClientKey, conf.Config.Relay.ClientKeyPassword)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := cmtls.X509KeyPair(certPem, keyPem)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []cmtls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// dialTls 返回http.Transport使用的tls拨号函数，标准库的tls不支持国密证书，所以握手交给chainmaker的tls实现
+//
+//	@param tlsConfig
+//	@return func(ctx context.Context, network, addr string) (net.Conn, error)
+func dialTls(tlsConfig *cmtls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		rawConn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		config := tlsConfig
+		if config.ServerName == "" {
+			// 和标准库一致，没有配置域名时用地址中的主机名校验证书
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			config = config.Clone()
+			config.ServerName = host
+		}
+		conn := cmtls.Client(rawConn, config)
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+		if err = conn.Handshake(); err != nil {
+			_ = rawConn.Close()
+			return nil, err
+		}
+		_ = conn.SetDeadline(time.Time{})
+		return conn, nil
+	}
+}
+
+// httpError 把http错误转换为grpc status error，优先使用grpc-gateway返回的错误码
+//
+//	@param statusCode
+//	@param body
+//	@return error
+func httpError(statusCode int, body []byte) error {
+	gwErr := &gatewayError{}
+	if err := json.Unmarshal(body, gwErr); err == nil && (gwErr.Code != 0 || gwErr.Message != "") {
+		message := gwErr.Message
+		if message == "" {
+			message = gwErr.Error
+		}
+		return status.Error(codes.Code(gwErr.Code), message)
+	}
+	message := fmt.Sprintf("http status %d: %s", statusCode, string(body))
+	switch statusCode {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, message)
+	case http.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, message)
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, message)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, message)
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return status.Error(codes.DeadlineExceeded, message)
+	case http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, message)
+	case http.StatusNotImplemented:
+		return status.Error(codes.Unimplemented, message)
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return status.Error(codes.Unavailable, message)
+	default:
+		return status.Error(codes.Unknown, message)
+	}
 }
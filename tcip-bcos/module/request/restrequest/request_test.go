@@ -6,18 +6,29 @@ SPDX-License-Identifier: Apache-2.0
 package restrequest
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request/endpoint"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
 	"go.uber.org/zap"
 )
 
-func initTest() *zap.SugaredLogger {
+const accessCode = "testAccessCode"
+
+func initTest(handler http.HandlerFunc) (*zap.SugaredLogger, *httptest.Server) {
 	log := []*logger.LogModuleConfig{
 		{
 			ModuleName:   "default",
@@ -26,45 +37,149 @@ func initTest() *zap.SugaredLogger {
 		},
 	}
 	logger.InitLogConfig(log)
-	return logger.GetLogger(logger.ModuleRequest)
+	server := httptest.NewServer(handler)
+	conf.Config.BaseConfig = &conf.BaseConfig{DefaultTimeout: 5}
+	conf.Config.Relay = &conf.Relay{
+		AccessCode: accessCode,
+		Address:    server.URL,
+		CallType:   conf.RestCallType,
+	}
+	return logger.GetLogger(logger.ModuleRequest), server
+}
+
+func relayHandler(t *testing.T, wantPath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, wantPath, r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.Header.Get(tokenMetadataHeader) != accessCode {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid token","code":16,"message":"invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"message":"GATEWAY_SUCCESS"}`))
+	}
 }
 
 func TestRestRequest_BeginCrossChain(t *testing.T) {
-	defer func() {
-		err := recover()
-		assert.NotNil(t, err)
-	}()
-	log := initTest()
+	log, server := initTest(relayHandler(t, beginCrossChainPath))
+	defer server.Close()
 	req := NewRestRequest(log)
-	_, _ = req.BeginCrossChain(nil)
+
+	_, err := req.BeginCrossChain(nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	res, err := req.BeginCrossChain(&relay_chain.BeginCrossChainRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
+
+	conf.Config.Relay.AccessCode = "wrong"
+	_, err = req.BeginCrossChain(&relay_chain.BeginCrossChainRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRestRequest_GatewayRegister(t *testing.T) {
+	log, server := initTest(relayHandler(t, gatewayRegisterPath))
+	defer server.Close()
+	req := NewRestRequest(log)
+	res, err := req.GatewayRegister(&relay_chain.GatewayRegisterRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
+}
+
+func TestRestRequest_GatewayUpdate(t *testing.T) {
+	log, server := initTest(relayHandler(t, gatewayUpdatePath))
+	defer server.Close()
+	req := NewRestRequest(log)
+	res, err := req.GatewayUpdate(&relay_chain.GatewayUpdateRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
 }
 
 func TestRestRequest_InitSpvContract(t *testing.T) {
-	defer func() {
-		err := recover()
-		assert.NotNil(t, err)
-	}()
-	log := initTest()
+	log, server := initTest(relayHandler(t, initSpvContractPath))
+	defer server.Close()
 	req := NewRestRequest(log)
-	_, _ = req.InitSpvContract(nil)
+	res, err := req.InitSpvContract(&relay_chain.InitContractRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
 }
 
 func TestRestRequest_SyncBlockHeader(t *testing.T) {
-	defer func() {
-		err := recover()
-		assert.NotNil(t, err)
-	}()
-	log := initTest()
+	log, server := initTest(relayHandler(t, syncBlockHeaderPath))
+	defer server.Close()
 	req := NewRestRequest(log)
-	_, _ = req.SyncBlockHeader(nil)
+	res, err := req.SyncBlockHeader(&relay_chain.SyncBlockHeaderRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
+
+	server.Close()
+	_, err = req.SyncBlockHeader(&relay_chain.SyncBlockHeaderRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
 }
 
 func TestRestRequest_UpdateSpvContract(t *testing.T) {
-	defer func() {
-		err := recover()
-		assert.NotNil(t, err)
-	}()
-	log := initTest()
+	log, server := initTest(relayHandler(t, updateSpvContractPath))
+	defer server.Close()
 	req := NewRestRequest(log)
-	_, _ = req.UpdateSpvContract(nil)
+	res, err := req.UpdateSpvContract(&relay_chain.UpdateContractRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Code_GATEWAY_SUCCESS, res.Code)
+}
+
+func Test_httpError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       codes.Code
+	}{
+		{"gateway error", http.StatusBadRequest, `{"error":"e","code":9,"message":"e"}`, codes.FailedPrecondition},
+		{"forbidden", http.StatusForbidden, "forbidden", codes.PermissionDenied},
+		{"timeout", http.StatusGatewayTimeout, "", codes.DeadlineExceeded},
+		{"unavailable", http.StatusServiceUnavailable, "", codes.Unavailable},
+		{"unknown", http.StatusInternalServerError, "", codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(httpError(tt.statusCode, []byte(tt.body))); got != tt.want {
+				t.Errorf("httpError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestRequest_checkCerts(t *testing.T) {
+	log, server := initTest(relayHandler(t, beginCrossChainPath))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "relay-cert")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"ca.crt", "client.crt", "client.key"} {
+		content, err := ioutil.ReadFile(path.Join("../../../config/cert/client", name))
+		assert.Nil(t, err)
+		assert.Nil(t, ioutil.WriteFile(path.Join(dir, name), content, 0600))
+	}
+	conf.Config.Relay.Tlsca = path.Join(dir, "ca.crt")
+	conf.Config.Relay.ClientCert = path.Join(dir, "client.crt")
+	conf.Config.Relay.ClientKey = path.Join(dir, "client.key")
+
+	r := NewRestRequest(log)
+	relay := &endpoint.Endpoint{Address: server.URL}
+	first, err := r.getClient(relay)
+	assert.Nil(t, err)
+
+	// 证书变化后重建客户端
+	modTime := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(conf.Config.Relay.ClientCert, modTime, modTime))
+	r.lastCertCheck = time.Time{}
+	second, err := r.getClient(relay)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, second)
+
+	// 新证书不可用时继续使用旧的客户端
+	assert.Nil(t, ioutil.WriteFile(conf.Config.Relay.ClientCert, []byte("broken"), 0600))
+	r.lastCertCheck = time.Time{}
+	third, err := r.getClient(relay)
+	assert.Nil(t, err)
+	assert.Equal(t, second, third)
 }
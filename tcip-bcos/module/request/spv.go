@@ -0,0 +1,160 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/relay_chain"
+)
+
+// spvContractNameFormat spv合约名称: spv + 网关ID + 链资源ID
+const spvContractNameFormat = "spv%s%s"
+
+// InitSpvContract 在中继链上安装子链的spv合约
+//
+//	@receiver r
+//	@param version 合约版本
+//	@param path 合约文件路径
+//	@param runtimeType 合约运行时类型
+//	@param kvJsonStr 合约初始化参数，json对象
+//	@param chainRid 链资源id
+//	@return error
+func (r *RequestManager) InitSpvContract(version, path, runtimeType, kvJsonStr, chainRid string) error {
+	byteCode, runtime, kvs, err := getSpvContractParam(path, runtimeType, kvJsonStr)
+	if err != nil {
+		r.log.Errorf("[InitSpvContract] %s", err.Error())
+		return err
+	}
+	req := &relay_chain.InitContractRequest{
+		Version:         common.Version_V1_0_0,
+		ContractName:    getSpvContractName(chainRid),
+		ContractVersion: version,
+		ByteCode:        byteCode,
+		RuntimeType:     runtime,
+		KeyValuePairs:   kvs,
+		GatewayId:       conf.Config.BaseConfig.GatewayID,
+		ChainRid:        chainRid,
+	}
+	res, err := r.request.InitSpvContract(req)
+	if err != nil {
+		r.log.Errorf("[InitSpvContract] %s", err.Error())
+		return err
+	}
+	if res.Code != common.Code_GATEWAY_SUCCESS {
+		r.log.Errorf("[InitSpvContract] install failed: code %s, message %s", res.Code.String(), res.Message)
+		return fmt.Errorf("install spv contract failed: code %s, message %s", res.Code.String(), res.Message)
+	}
+	r.log.Infof("[InitSpvContract] install success: contract %s, version %s", req.ContractName, version)
+	return nil
+}
+
+// UpdateSpvContract 更新中继链上子链的spv合约
+//
+//	@receiver r
+//	@param version 合约版本
+//	@param path 合约文件路径
+//	@param runtimeType 合约运行时类型
+//	@param kvJsonStr 合约升级参数，json对象
+//	@param chainRid 链资源id
+//	@return error
+func (r *RequestManager) UpdateSpvContract(version, path, runtimeType, kvJsonStr, chainRid string) error {
+	byteCode, runtime, kvs, err := getSpvContractParam(path, runtimeType, kvJsonStr)
+	if err != nil {
+		r.log.Errorf("[UpdateSpvContract] %s", err.Error())
+		return err
+	}
+	req := &relay_chain.UpdateContractRequest{
+		Version:         common.Version_V1_0_0,
+		ContractName:    getSpvContractName(chainRid),
+		ContractVersion: version,
+		ByteCode:        byteCode,
+		RuntimeType:     runtime,
+		KeyValuePairs:   kvs,
+		GatewayId:       conf.Config.BaseConfig.GatewayID,
+		ChainRid:        chainRid,
+	}
+	res, err := r.request.UpdateSpvContract(req)
+	if err != nil {
+		r.log.Errorf("[UpdateSpvContract] %s", err.Error())
+		return err
+	}
+	if res.Code != common.Code_GATEWAY_SUCCESS {
+		r.log.Errorf("[UpdateSpvContract] update failed: code %s, message %s", res.Code.String(), res.Message)
+		return fmt.Errorf("update spv contract failed: code %s, message %s", res.Code.String(), res.Message)
+	}
+	r.log.Infof("[UpdateSpvContract] update success: contract %s, version %s", req.ContractName, version)
+	return nil
+}
+
+// getSpvContractName spv合约名称
+//
+//	@param chainRid
+//	@return string
+func getSpvContractName(chainRid string) string {
+	return fmt.Sprintf(spvContractNameFormat, conf.Config.BaseConfig.GatewayID, chainRid)
+}
+
+// getSpvContractParam 读取合约文件，解析运行时类型和合约参数
+//
+//	@param path
+//	@param runtimeType
+//	@param kvJsonStr
+//	@return []byte
+//	@return common.ChainmakerRuntimeType
+//	@return []*common.ContractKeyValuePair
+//	@return error
+func getSpvContractParam(path, runtimeType, kvJsonStr string) (
+	[]byte, common.ChainmakerRuntimeType, []*common.ContractKeyValuePair, error) {
+	runtime, ok := common.ChainmakerRuntimeType_value[runtimeType]
+	if !ok || runtime == int32(common.ChainmakerRuntimeType_INVALID) {
+		return nil, common.ChainmakerRuntimeType_INVALID, nil,
+			fmt.Errorf("unsupported runtime type %s", runtimeType)
+	}
+	byteCode, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, common.ChainmakerRuntimeType_INVALID, nil,
+			fmt.Errorf("read contract file %s error: %s", path, err.Error())
+	}
+	kvs, err := getKvsFromKvJsonStr(kvJsonStr)
+	if err != nil {
+		return nil, common.ChainmakerRuntimeType_INVALID, nil, err
+	}
+	return byteCode, common.ChainmakerRuntimeType(runtime), kvs, nil
+}
+
+// getKvsFromKvJsonStr 把json对象形式的合约参数转换为按key排序的键值对
+//
+//	@param kvJsonStr
+//	@return []*common.ContractKeyValuePair
+//	@return error
+func getKvsFromKvJsonStr(kvJsonStr string) ([]*common.ContractKeyValuePair, error) {
+	if kvJsonStr == "" {
+		return []*common.ContractKeyValuePair{}, nil
+	}
+	kvMap := make(map[string]string)
+	if err := json.Unmarshal([]byte(kvJsonStr), &kvMap); err != nil {
+		return nil, fmt.Errorf("contract params should be a json object of strings: %s", err.Error())
+	}
+	keys := make([]string, 0, len(kvMap))
+	for key := range kvMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kvs := make([]*common.ContractKeyValuePair, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, &common.ContractKeyValuePair{
+			Key:   key,
+			Value: []byte(kvMap[key]),
+		})
+	}
+	return kvs, nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultInitialInterval = time.Second
+	defaultMaxInterval     = time.Minute
+	defaultMultiplier      = 2
+	defaultJitter          = 0.2
+	// defaultMaxAttempts 没有配置retry时最多调用的次数，重试必须是有限的
+	defaultMaxAttempts = 20
+	// defaultMaxAge 没有配置retry时第一次调用后最多重试的时间
+	defaultMaxAge = time.Hour
+)
+
+// defaultRetryableCodes 默认重试的中继网关错误码，其余的错误码重试也不会成功
+var defaultRetryableCodes = []common.Code{
+	common.Code_GATEWAY_TIMEOUT,
+	// 中继网关还没有同步到对应的区块头时交易证明会失败
+	common.Code_TX_PROVE_ERROR,
+	common.Code_INTERNAL_ERROR,
+	common.Code_RELAY_CHAIN_ERROR,
+}
+
+// ErrGatewayDisabled 中继网关返回本网关已被禁用
+var ErrGatewayDisabled = errors.New("this gateway is disabled, please contact admin")
+
+// Policy 重试策略，指数退避加随机抖动，达到最大次数或者最长时间后放弃
+type Policy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+	// MaxAttempts 最多调用次数
+	MaxAttempts int
+	// MaxAge 第一次调用后最多重试多久
+	MaxAge         time.Duration
+	retryableCodes map[common.Code]bool
+	sleep          func(time.Duration)
+}
+
+// Result 调用结果，Code是中继网关返回的错误码
+type Result struct {
+	Code    common.Code
+	Message string
+}
+
+// NewPolicy 根据配置创建重试策略，没有配置的项使用默认值
+//
+//	@param config
+//	@return *Policy
+func NewPolicy(config *conf.RetryConfig) *Policy {
+	p := &Policy{
+		InitialInterval: defaultInitialInterval,
+		MaxInterval:     defaultMaxInterval,
+		Multiplier:      defaultMultiplier,
+		Jitter:          defaultJitter,
+		MaxAttempts:     defaultMaxAttempts,
+		MaxAge:          defaultMaxAge,
+		retryableCodes:  make(map[common.Code]bool),
+		sleep:           time.Sleep,
+	}
+	for _, code := range defaultRetryableCodes {
+		p.retryableCodes[code] = true
+	}
+	if config == nil {
+		return p
+	}
+	if config.InitialInterval > 0 {
+		p.InitialInterval = time.Duration(config.InitialInterval) * time.Millisecond
+	}
+	if config.MaxInterval > 0 {
+		p.MaxInterval = time.Duration(config.MaxInterval) * time.Millisecond
+	}
+	if config.Multiplier >= 1 {
+		p.Multiplier = config.Multiplier
+	}
+	if config.Jitter >= 0 && config.Jitter <= 1 {
+		p.Jitter = config.Jitter
+	}
+	if config.MaxAttempts > 0 {
+		p.MaxAttempts = config.MaxAttempts
+	}
+	if config.MaxAge > 0 {
+		p.MaxAge = time.Duration(config.MaxAge) * time.Second
+	}
+	if len(config.RetryableCodes) != 0 {
+		p.retryableCodes = make(map[common.Code]bool)
+		for _, code := range config.RetryableCodes {
+			p.retryableCodes[common.Code(code)] = true
+		}
+	}
+	return p
+}
+
+// Do 调用call直到成功、遇到不可重试的错误或者重试耗尽，返回调用次数和最后一次的错误
+//
+//	@receiver p
+//	@param call
+//	@return int
+//	@return error
+func (p *Policy) Do(call func() (*Result, error)) (int, error) {
+	start := time.Now()
+	attempts := 0
+	for {
+		attempts++
+		res, err := call()
+		if err == nil && res.Code == common.Code_GATEWAY_SUCCESS {
+			return attempts, nil
+		}
+		retryable := false
+		if err != nil {
+			retryable = IsRetryableError(err)
+		} else {
+			if res.Code == common.Code_GATEWAY_DISABLED {
+				return attempts, ErrGatewayDisabled
+			}
+			retryable = p.IsRetryableCode(res.Code)
+			err = fmt.Errorf("relay gateway response code %s: %s", res.Code.String(), res.Message)
+		}
+		if !retryable {
+			return attempts, fmt.Errorf("permanent error: %s", err.Error())
+		}
+		if p.Exhausted(attempts, start) {
+			return attempts, fmt.Errorf("retry exhausted after %d attempts: %s", attempts, err.Error())
+		}
+		p.sleep(p.Backoff(attempts))
+	}
+}
+
+// Backoff 第attempts次调用失败后等待的时间
+//
+//	@receiver p
+//	@param attempts
+//	@return time.Duration
+func (p *Policy) Backoff(attempts int) time.Duration {
+	interval := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(attempts-1))
+	if interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		// 在[interval*(1-jitter), interval*(1+jitter)]之间随机，避免大量请求同时重试
+		interval += interval * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(interval)
+}
+
+// Wait 第attempts次调用失败后按照退避时间等待，用于不通过Do调用的重试
+//
+//	@receiver p
+//	@param attempts
+func (p *Policy) Wait(attempts int) {
+	p.sleep(p.Backoff(attempts))
+}
+
+// Exhausted 是否已经达到最大调用次数或者最长重试时间
+//
+//	@receiver p
+//	@param attempts
+//	@param start
+//	@return bool
+func (p *Policy) Exhausted(attempts int, start time.Time) bool {
+	if p.MaxAttempts > 0 && attempts >= p.MaxAttempts {
+		return true
+	}
+	return p.MaxAge > 0 && time.Since(start) >= p.MaxAge
+}
+
+// IsRetryableCode 中继网关返回的错误码是否可以重试
+//
+//	@receiver p
+//	@param code
+//	@return bool
+func (p *Policy) IsRetryableCode(code common.Code) bool {
+	return p.retryableCodes[code]
+}
+
+// IsRetryableError 调用中继网关返回的错误是否可以重试，参数错误、认证失败等重试也不会成功
+//
+//	@param err
+//	@return bool
+func IsRetryableError(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		return false
+	default:
+		return true
+	}
+}
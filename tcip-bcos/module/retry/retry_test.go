@@ -0,0 +1,117 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := NewPolicy(&conf.RetryConfig{InitialInterval: 100, MaxInterval: 1000, Multiplier: 2, Jitter: 0})
+	assert.Equal(t, 100*time.Millisecond, p.Backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.Backoff(3))
+	assert.Equal(t, time.Second, p.Backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := p.Backoff(2)
+		assert.True(t, backoff >= 100*time.Millisecond && backoff <= 300*time.Millisecond)
+	}
+}
+
+func TestPolicy_Do(t *testing.T) {
+	tests := []struct {
+		name         string
+		results      []*Result
+		errs         []error
+		wantAttempts int
+		wantErr      bool
+		wantDisabled bool
+	}{
+		{
+			name:         "success",
+			results:      []*Result{{Code: common.Code_GATEWAY_SUCCESS}},
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry then success",
+			results:      []*Result{nil, {Code: common.Code_RELAY_CHAIN_ERROR}, {Code: common.Code_GATEWAY_SUCCESS}},
+			errs:         []error{status.Error(codes.Unavailable, "down"), nil, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent code",
+			results:      []*Result{{Code: common.Code_INVALID_PARAMETER}},
+			errs:         []error{nil},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "permanent error",
+			results:      []*Result{nil},
+			errs:         []error{status.Error(codes.Unauthenticated, "token")},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "exhausted",
+			results:      []*Result{nil, nil, nil, nil},
+			errs:         []error{errors.New("e"), errors.New("e"), errors.New("e"), errors.New("e")},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "disabled",
+			results:      []*Result{{Code: common.Code_GATEWAY_DISABLED}},
+			errs:         []error{nil},
+			wantAttempts: 1,
+			wantErr:      true,
+			wantDisabled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPolicy(&conf.RetryConfig{MaxAttempts: 3})
+			slept := 0
+			p.sleep = func(time.Duration) { slept++ }
+			calls := 0
+			attempts, err := p.Do(func() (*Result, error) {
+				calls++
+				return tt.results[calls-1], tt.errs[calls-1]
+			})
+			assert.Equal(t, tt.wantAttempts, attempts)
+			assert.Equal(t, tt.wantAttempts-1, slept)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantDisabled, errors.Is(err, ErrGatewayDisabled))
+		})
+	}
+}
+
+func TestPolicy_Exhausted(t *testing.T) {
+	p := NewPolicy(&conf.RetryConfig{MaxAttempts: 100, MaxAge: 1})
+	assert.False(t, p.Exhausted(10, time.Now()))
+	assert.True(t, p.Exhausted(100, time.Now()))
+	assert.True(t, p.Exhausted(1, time.Now().Add(-2*time.Second)))
+
+	// 没有配置retry或者配置为0时使用有限的默认值
+	for _, config := range []*conf.RetryConfig{nil, {}} {
+		p = NewPolicy(config)
+		assert.Equal(t, defaultMaxAttempts, p.MaxAttempts)
+		assert.Equal(t, defaultMaxAge, p.MaxAge)
+		assert.False(t, p.Exhausted(1, time.Now()))
+		assert.True(t, p.Exhausted(defaultMaxAttempts, time.Now()))
+		assert.True(t, p.Exhausted(1, time.Now().Add(-defaultMaxAge)))
+	}
+}
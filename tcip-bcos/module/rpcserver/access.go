@@ -0,0 +1,302 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// forwardedForHeader 代理添加的客户端地址，grpc-gateway转发时也会放到metadata中
+	forwardedForHeader = "X-Forwarded-For"
+	forwardedForKey    = "x-forwarded-for"
+)
+
+// unixClientIp unix socket的客户端没有ip，按本机地址检查访问策略和限流
+var unixClientIp = net.IPv4(127, 0, 0, 1)
+
+// accessPolicy 客户端地址的访问策略
+type accessPolicy struct {
+	allow             []*net.IPNet
+	deny              []*net.IPNet
+	trustForwardedFor bool
+	trustedProxies    []*net.IPNet
+}
+
+// newAccessPolicy 根据rpc配置解析访问策略，blacklist并入denylist
+//
+//	@param config
+//	@return *accessPolicy
+//	@return error
+func newAccessPolicy(config *conf.RpcConfig) (*accessPolicy, error) {
+	var err error
+	p := &accessPolicy{trustForwardedFor: config.TrustForwardedFor}
+	if p.allow, err = parseNets(config.AllowList); err != nil {
+		return nil, err
+	}
+	if p.deny, err = parseNets(append(append([]string{}, config.DenyList...), config.BlackList...)); err != nil {
+		return nil, err
+	}
+	if p.trustedProxies, err = parseNets(config.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// allowed 地址是否可以访问，不能解析的地址只在没有配置allowlist时放行
+//
+//	@receiver p
+//	@param ip
+//	@return bool
+func (p *accessPolicy) allowed(ip net.IP) bool {
+	if ip == nil {
+		return len(p.allow) == 0
+	}
+	if containsIp(p.deny, ip) {
+		return false
+	}
+	return len(p.allow) == 0 || containsIp(p.allow, ip)
+}
+
+// resolve 确定客户端地址，信任X-Forwarded-For时从右往左跳过可信代理，否则使用直接连接的地址
+//
+//	@receiver p
+//	@param remote 直接连接的地址
+//	@param forwardedFor X-Forwarded-For的全部值
+//	@return net.IP
+func (p *accessPolicy) resolve(remote net.IP, forwardedFor []string) net.IP {
+	if !p.trustForwardedFor || remote == nil {
+		return remote
+	}
+	chain := make([]net.IP, 0)
+	for _, value := range forwardedFor {
+		for _, item := range strings.Split(value, ",") {
+			chain = append(chain, parseIp(item))
+		}
+	}
+	ip := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !p.isTrustedProxy(ip) || chain[i] == nil {
+			break
+		}
+		ip = chain[i]
+	}
+	return ip
+}
+
+// isTrustedProxy 是否是可信的代理，没有配置trusted_proxies时不信任任何代理，防止客户端伪造X-Forwarded-For
+//
+//	@receiver p
+//	@param ip
+//	@return bool
+func (p *accessPolicy) isTrustedProxy(ip net.IP) bool {
+	return containsIp(p.trustedProxies, ip)
+}
+
+// accessControl 访问策略，配置重新加载后更新
+type accessControl struct {
+	lock   sync.Mutex
+	policy *accessPolicy
+}
+
+// access 全局的访问策略，grpc拦截器和restful代理共用
+var access = &accessControl{}
+
+// getPolicy 获取当前的访问策略
+//
+//	@receiver a
+//	@return *accessPolicy
+func (a *accessControl) getPolicy() *accessPolicy {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.policy == nil {
+		policy, err := newAccessPolicy(conf.Config.RpcConfig)
+		if err != nil {
+			rpcLog.Errorf("[getPolicy] %s", err.Error())
+			policy = &accessPolicy{}
+		}
+		a.policy = policy
+	}
+	return a.policy
+}
+
+// ReloadAccessPolicy 配置重新加载后按照新的rpc配置更新访问策略，新配置有误时继续使用旧策略，
+// 重新加载不修改conf.Config，新配置由conf.Reload返回
+//
+//	@param config 检查过的新rpc配置
+//	@return error
+func ReloadAccessPolicy(config *conf.RpcConfig) error {
+	access.lock.Lock()
+	defer access.lock.Unlock()
+	policy, err := newAccessPolicy(config)
+	if err != nil {
+		rpcLog.Errorf("[ReloadAccessPolicy] reload access lists failed, keep the old ones: %s", err.Error())
+		return err
+	}
+	access.policy = policy
+	rpcLog.Info("[ReloadAccessPolicy] access lists reloaded")
+	return nil
+}
+
+// IpFilterInterceptor 按照allowlist和denylist过滤客户端地址，restful代理转发的请求使用代理记录的客户端地址
+//
+//	@return grpc.UnaryServerInterceptor
+func IpFilterInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		policy := access.getPolicy()
+		ip := getClientIpWithPolicy(ctx, policy)
+		if !policy.allowed(ip) {
+			errMsg := fmt.Sprintf("%s is rejected by access list [%s]", info.FullMethod, ip)
+			rpcLog.Warn(errMsg)
+			return nil, status.Error(codes.PermissionDenied, errMsg)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// getClientIp 获取客户端ip，restful代理转发的请求使用代理记录的客户端地址
+//
+//	@param ctx
+//	@return net.IP
+func getClientIp(ctx context.Context) net.IP {
+	return getClientIpWithPolicy(ctx, access.getPolicy())
+}
+
+// getClientIpWithPolicy 按照访问策略获取客户端ip
+//
+//	@param ctx
+//	@param policy
+//	@return net.IP
+func getClientIpWithPolicy(ctx context.Context, policy *accessPolicy) net.IP {
+	if isUnixConn(ctx) {
+		return unixClientIp
+	}
+	ip := parseIp(GetClientAddr(ctx))
+	if !isGatewayConn(ctx) {
+		return ip
+	}
+	// restful代理通过进程内连接转发，代理已经检查过客户端地址
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(forwardedForKey)) == 0 {
+		return ip
+	}
+	// 最后一个地址是直接连接restful代理的地址
+	items := strings.Split(strings.Join(md.Get(forwardedForKey), ","), ",")
+	return policy.resolve(parseIp(items[len(items)-1]), items[:len(items)-1])
+}
+
+// isGatewayConn 是否是restful代理的进程内连接
+//
+//	@param ctx
+//	@return bool
+func isGatewayConn(ctx context.Context) bool {
+	pr, ok := peer.FromContext(ctx)
+	return ok && pr.Addr != nil && pr.Addr.Network() == gatewayNetwork
+}
+
+// isUnixConn 是否是unix socket连接
+//
+//	@param ctx
+//	@return bool
+func isUnixConn(ctx context.Context) bool {
+	_, ok := ctx.Value(connKey{}).(*net.UnixConn)
+	return ok
+}
+
+// IpFilterHandler restful请求的客户端地址过滤，和grpc拦截器使用相同的策略
+//
+//	@param next
+//	@return http.Handler
+func IpFilterHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := access.getPolicy()
+		ip := unixClientIp
+		if !isUnixConn(r.Context()) {
+			ip = policy.resolve(parseIp(r.RemoteAddr), r.Header.Values(forwardedForHeader))
+		}
+		if !policy.allowed(ip) {
+			errMsg := fmt.Sprintf("%s is rejected by access list [%s]", r.URL.Path, ip)
+			rpcLog.Warn(errMsg)
+			http.Error(w, errMsg, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseNets 解析ip或者cidr列表，单个ip转换为只包含自己的网段
+//
+//	@param items
+//	@return []*net.IPNet
+//	@return error
+func parseNets(items []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if strings.Contains(item, "/") {
+			_, ipNet, err := net.ParseCIDR(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cidr %s: %s", item, err.Error())
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		ip := net.ParseIP(item)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip %s", item)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
+// containsIp 地址是否在任意一个网段中
+//
+//	@param nets
+//	@param ip
+//	@return bool
+func containsIp(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIp 解析地址中的ip，支持带端口、ipv6方括号和zone的格式
+//
+//	@param addr
+//	@return net.IP
+func parseIp(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	if index := strings.Index(addr, "%"); index >= 0 {
+		addr = addr[:index]
+	}
+	return net.ParseIP(addr)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseIp(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{addr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{addr: "10.0.0.1", want: "10.0.0.1"},
+		{addr: "[2001:db8::1]:1234", want: "2001:db8::1"},
+		{addr: "2001:db8::1", want: "2001:db8::1"},
+		{addr: "[fe80::1%eth0]:1234", want: "fe80::1"},
+		{addr: " 10.0.0.2 ", want: "10.0.0.2"},
+		{addr: "unknown", want: "<nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseIp(tt.addr).String())
+		})
+	}
+}
+
+func TestAccessPolicy(t *testing.T) {
+	policy, err := newAccessPolicy(&conf.RpcConfig{
+		AllowList:         []string{"10.0.0.0/8", "2001:db8::/32"},
+		DenyList:          []string{"10.0.0.5"},
+		BlackList:         []string{"2001:db8::5"},
+		TrustForwardedFor: true,
+		TrustedProxies:    []string{"192.168.0.0/16"},
+	})
+	assert.Nil(t, err)
+	tests := []struct {
+		name         string
+		remote       string
+		forwardedFor []string
+		want         bool
+	}{
+		{name: "allowed", remote: "10.1.2.3", want: true},
+		{name: "denied", remote: "10.0.0.5", want: false},
+		{name: "blacklist", remote: "2001:db8::5", want: false},
+		{name: "not in allowlist", remote: "172.16.0.1", want: false},
+		{name: "ipv6 allowed", remote: "2001:db8::1", want: true},
+		{name: "trusted proxy", remote: "192.168.1.1", forwardedFor: []string{"10.1.2.3"}, want: true},
+		{name: "trusted proxy chain", remote: "192.168.1.1",
+			forwardedFor: []string{"10.0.0.5, 192.168.1.2"}, want: false},
+		{name: "spoofed by client", remote: "10.1.2.3", forwardedFor: []string{"10.0.0.5"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := policy.resolve(net.ParseIP(tt.remote), tt.forwardedFor)
+			assert.Equal(t, tt.want, policy.allowed(ip))
+		})
+	}
+	// 没有配置可信代理时不使用X-Forwarded-For
+	policy, err = newAccessPolicy(&conf.RpcConfig{DenyList: []string{"10.0.0.5"}, TrustForwardedFor: true})
+	assert.Nil(t, err)
+	assert.True(t, policy.allowed(policy.resolve(net.ParseIP("10.1.2.3"), []string{"10.0.0.5"})))
+	_, err = newAccessPolicy(&conf.RpcConfig{AllowList: []string{"10.0.0.0/33"}})
+	assert.NotNil(t, err)
+	_, err = newAccessPolicy(&conf.RpcConfig{DenyList: []string{"host"}})
+	assert.NotNil(t, err)
+}
+
+func TestIpFilter(t *testing.T) {
+	initTest(t)
+	conf.Config.RpcConfig = &conf.RpcConfig{
+		DenyList:      []string{"10.0.0.5"},
+		RestfulConfig: conf.RstfulConfig{Enable: true},
+	}
+	access = &accessControl{}
+	interceptor := IpFilterInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	callFrom := func(ctx context.Context, addr net.Addr, forwardedFor string) error {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+		if forwardedFor != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(forwardedForKey, forwardedFor))
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/api.RpcCrossChain/PingPong"}, handler)
+		return err
+	}
+	call := func(addr string, forwardedFor string) error {
+		return callFrom(context.Background(), &net.TCPAddr{IP: net.ParseIP(addr), Port: 1}, forwardedFor)
+	}
+	assert.Nil(t, call("10.0.0.1", ""))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("10.0.0.5", "")))
+	// restful代理转发的请求
+	assert.Equal(t, codes.PermissionDenied, status.Code(callFrom(context.Background(), gatewayAddr{}, "10.0.0.5")))
+	assert.Nil(t, callFrom(context.Background(), gatewayAddr{}, "10.0.0.1"))
+	// 本地连接不再被当作restful代理
+	assert.Nil(t, call("127.0.0.1", "10.0.0.5"))
+	// unix socket按本机地址检查
+	unixCtx := ConnContext(context.Background(), &net.UnixConn{})
+	assert.Nil(t, callFrom(unixCtx, &net.UnixAddr{Name: "@", Net: "unix"}, "10.0.0.5"))
+
+	httpHandler := IpFilterHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/v1/PingPong", nil)
+	req.RemoteAddr = "10.0.0.5:1234"
+	recorder := httptest.NewRecorder()
+	httpHandler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	recorder = httptest.NewRecorder()
+	httpHandler.ServeHTTP(recorder, req.WithContext(unixCtx))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// 配置重新加载
+	reloaded := *conf.Config.RpcConfig
+	reloaded.DenyList = []string{"10.0.0.1"}
+	assert.Nil(t, ReloadAccessPolicy(&reloaded))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("10.0.0.1", "")))
+	assert.Nil(t, call("10.0.0.5", ""))
+	// 新配置有误时继续使用旧策略
+	invalid := reloaded
+	invalid.DenyList = []string{"host"}
+	assert.NotNil(t, ReloadAccessPolicy(&invalid))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("10.0.0.1", "")))
+}
+
+// gatewayAddr restful代理进程内连接的地址
+type gatewayAddr struct{}
+
+func (gatewayAddr) Network() string { return gatewayNetwork }
+
+func (gatewayAddr) String() string { return gatewayNetwork }
@@ -0,0 +1,284 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	cmtls "chainmaker.org/chainmaker/common/v2/crypto/tls"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/admin/adminpb"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RoleRelay 中继网关，可以调用跨链接口
+	RoleRelay = conf.RoleRelay
+	// RoleAdmin 管理员，可以管理跨链事件
+	RoleAdmin = conf.RoleAdmin
+
+	// tokenKey 调用方携带token的metadata
+	tokenKey = "x-token"
+)
+
+// adminMethodPrefix 运维管理接口的前缀，全部只允许管理员调用
+var adminMethodPrefix = "/" + adminpb.Admin_ServiceDesc.ServiceName + "/"
+
+// methodRoles 接口允许的角色，没有列出的接口只允许管理员调用
+var methodRoles = map[string][]string{
+	"CrossChainTry":       {RoleRelay},
+	"CrossChainConfirm":   {RoleRelay},
+	"CrossChainCancel":    {RoleRelay},
+	"IsCrossChainSuccess": {RoleRelay},
+	"TxVerify":            {RoleRelay},
+	"CrossChainEvent":     {RoleAdmin},
+	"PingPong":            {RoleRelay, RoleAdmin},
+}
+
+// connKey context中保存客户端连接的key
+type connKey struct{}
+
+// identityKey context中保存认证通过的调用方身份的key
+type identityKey struct{}
+
+// peerCert 调用方证书的subject和指纹
+type peerCert struct {
+	subject     string
+	commonName  string
+	fingerprint string
+}
+
+// ConnContext 把客户端连接保存到context中，国密tls连接不会出现在grpc的peer信息里，需要从连接上读取证书
+//
+//	@param ctx
+//	@param conn
+//	@return context.Context
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// AuthInterceptor 调用方认证和按接口授权，先按证书和x-token确定调用方身份，再检查身份的角色
+//
+//	@return grpc.UnaryServerInterceptor
+func AuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		auth := conf.Config.RpcConfig.Auth
+		if !auth.Enable {
+			return handler(ctx, req)
+		}
+		identity, err := authenticate(ctx, auth.Identities)
+		if err != nil {
+			rpcLog.Warnf("[AuthInterceptor] %s from [%s] is rejected: %s",
+				info.FullMethod, GetClientAddr(ctx), err.Error())
+			return nil, err
+		}
+		if err = authorize(identity, info.FullMethod); err != nil {
+			rpcLog.Warnf("[AuthInterceptor] %s from [%s] is rejected: %s",
+				info.FullMethod, GetClientAddr(ctx), err.Error())
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, identityKey{}, identity), req)
+	}
+}
+
+// authenticate 找到和调用方证书、token匹配的身份
+//
+//	@param ctx
+//	@param identities
+//	@return *conf.AuthIdentity
+//	@return error
+func authenticate(ctx context.Context, identities []*conf.AuthIdentity) (*conf.AuthIdentity, error) {
+	certs := getPeerCerts(ctx)
+	token := getToken(ctx)
+	for _, identity := range identities {
+		if matchIdentity(identity, certs, token) {
+			return identity, nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "unknown caller identity")
+}
+
+// authorize 检查身份的角色是否可以调用接口
+//
+//	@param identity
+//	@param fullMethod
+//	@return error
+func authorize(identity *conf.AuthIdentity, fullMethod string) error {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	roles, ok := methodRoles[method]
+	if !ok || strings.HasPrefix(fullMethod, adminMethodPrefix) {
+		roles = []string{RoleAdmin}
+	}
+	for _, role := range roles {
+		if identity.Role == role {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%s with role %s can not call %s",
+		identity.Name, identity.Role, method)
+}
+
+// matchIdentity 身份配置了证书时需要有一个证书匹配，配置了token时需要token匹配，什么都没配置的身份不匹配任何调用方
+//
+//	@param identity
+//	@param certs
+//	@param token
+//	@return bool
+func matchIdentity(identity *conf.AuthIdentity, certs []*peerCert, token string) bool {
+	checkCert := len(identity.Subjects) != 0 || len(identity.Fingerprints) != 0
+	checkToken := len(identity.Tokens) != 0
+	if !checkCert && !checkToken {
+		return false
+	}
+	if checkCert && !matchCert(identity, certs) {
+		return false
+	}
+	if checkToken && !matchToken(identity.Tokens, token) {
+		return false
+	}
+	return true
+}
+
+// matchCert 证书subject、CN或者指纹在允许列表中
+//
+//	@param identity
+//	@param certs
+//	@return bool
+func matchCert(identity *conf.AuthIdentity, certs []*peerCert) bool {
+	for _, cert := range certs {
+		for _, subject := range identity.Subjects {
+			if subject == cert.subject || subject == cert.commonName {
+				return true
+			}
+		}
+		for _, fingerprint := range identity.Fingerprints {
+			if normalizeFingerprint(fingerprint) == cert.fingerprint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchToken token在允许列表中，使用固定时间比较
+//
+//	@param tokens
+//	@param token
+//	@return bool
+func matchToken(tokens []string, token string) bool {
+	if token == "" {
+		return false
+	}
+	matched := false
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// getToken 读取调用方携带的x-token
+//
+//	@param ctx
+//	@return string
+func getToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	tokens := md.Get(tokenKey)
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[0]
+}
+
+// getPeerCerts 读取调用方的证书，只使用叶子证书
+//
+//	@param ctx
+//	@return []*peerCert
+func getPeerCerts(ctx context.Context) []*peerCert {
+	if pr, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) != 0 {
+			cert := tlsInfo.State.PeerCertificates[0]
+			return []*peerCert{newPeerCert(cert.Subject.String(), cert.Subject.CommonName, cert.Raw)}
+		}
+	}
+	switch conn := ctx.Value(connKey{}).(type) {
+	case *tls.Conn:
+		state := conn.ConnectionState()
+		if len(state.PeerCertificates) != 0 {
+			cert := state.PeerCertificates[0]
+			return []*peerCert{newPeerCert(cert.Subject.String(), cert.Subject.CommonName, cert.Raw)}
+		}
+	case interface{ ConnectionState() cmtls.ConnectionState }:
+		state := conn.ConnectionState()
+		if len(state.PeerCertificates) != 0 {
+			cert := state.PeerCertificates[0]
+			return []*peerCert{newPeerCert(cert.Subject.String(), cert.Subject.CommonName, cert.Raw)}
+		}
+	}
+	return nil
+}
+
+// newPeerCert 计算证书指纹
+//
+//	@param subject
+//	@param commonName
+//	@param raw
+//	@return *peerCert
+func newPeerCert(subject, commonName string, raw []byte) *peerCert {
+	sum := sha256.Sum256(raw)
+	return &peerCert{
+		subject:     subject,
+		commonName:  commonName,
+		fingerprint: hex.EncodeToString(sum[:]),
+	}
+}
+
+// normalizeFingerprint 指纹统一为不带冒号的小写十六进制
+//
+//	@param fingerprint
+//	@return string
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
+
+// checkAuthConfig 检查认证配置，开启认证时每个身份都需要有合法的角色和认证方式
+//
+//	@param auth
+//	@return error
+func checkAuthConfig(auth *conf.AuthConfig) error {
+	if !auth.Enable {
+		return nil
+	}
+	if len(auth.Identities) == 0 {
+		return fmt.Errorf("rpc auth is enabled but no identity is configured")
+	}
+	for i, identity := range auth.Identities {
+		if identity.Role != RoleRelay && identity.Role != RoleAdmin {
+			return fmt.Errorf("rpc auth identity %d %s: unknown role %s", i, identity.Name, identity.Role)
+		}
+		if len(identity.Subjects) == 0 && len(identity.Fingerprints) == 0 && len(identity.Tokens) == 0 {
+			return fmt.Errorf("rpc auth identity %d %s: no subject, fingerprint or token", i, identity.Name)
+		}
+	}
+	return nil
+}
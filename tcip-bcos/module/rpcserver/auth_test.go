@@ -0,0 +1,178 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func initTest(t *testing.T) *x509.Certificate {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	logger.InitLogConfig(log)
+	rpcLog = logger.GetLogger(logger.ModuleRpcServer)
+	certPem, err := ioutil.ReadFile("../../config/cert/client/client.crt")
+	assert.Nil(t, err)
+	block, _ := pem.Decode(certPem)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.Nil(t, err)
+	return cert
+}
+
+// newCallContext 模拟带证书和token的调用
+func newCallContext(cert *x509.Certificate, token string) context.Context {
+	ctx := context.Background()
+	pr := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10000}}
+	if cert != nil {
+		pr.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}}
+	}
+	ctx = peer.NewContext(ctx, pr)
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(tokenKey, token))
+	}
+	return ctx
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	cert := initTest(t)
+	sum := sha256.Sum256(cert.Raw)
+	conf.Config.RpcConfig = &conf.RpcConfig{
+		Auth: conf.AuthConfig{
+			Enable: true,
+			Identities: []*conf.AuthIdentity{
+				{Name: "relay", Role: RoleRelay, Subjects: []string{cert.Subject.CommonName},
+					Tokens: []string{"relayToken"}},
+				{Name: "admin", Role: RoleAdmin, Fingerprints: []string{hex.EncodeToString(sum[:])}},
+				{Name: "empty", Role: RoleRelay},
+			},
+		},
+	}
+	interceptor := AuthInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "relay try",
+			ctx:      newCallContext(cert, "relayToken"),
+			method:   "/api.RpcCrossChain/CrossChainTry",
+			wantCode: codes.OK,
+		},
+		{
+			// 证书同时匹配admin的指纹
+			name:     "admin event",
+			ctx:      newCallContext(cert, ""),
+			method:   "/api.RpcCrossChain/CrossChainEvent",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "admin try",
+			ctx:      newCallContext(cert, "wrongToken"),
+			method:   "/api.RpcCrossChain/CrossChainTry",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unknown method",
+			ctx:      newCallContext(cert, "relayToken"),
+			method:   "/api.RpcCrossChain/Unknown",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			// 运维管理接口只允许admin调用
+			name:     "relay admin service",
+			ctx:      newCallContext(cert, "relayToken"),
+			method:   "/tcip_bcos.admin.Admin/ListChains",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "admin service",
+			ctx:      newCallContext(cert, ""),
+			method:   "/tcip_bcos.admin.Admin/ListChains",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "no cert",
+			ctx:      newCallContext(nil, "relayToken"),
+			method:   "/api.RpcCrossChain/CrossChainTry",
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	conf.Config.RpcConfig.Auth.Enable = false
+	_, err := interceptor(newCallContext(nil, ""), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/api.RpcCrossChain/CrossChainTry"}, handler)
+	assert.Nil(t, err)
+}
+
+func TestCheckAuthConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *conf.AuthConfig
+		wantErr bool
+	}{
+		{name: "disabled", auth: &conf.AuthConfig{}},
+		{name: "no identity", auth: &conf.AuthConfig{Enable: true}, wantErr: true},
+		{
+			name: "unknown role",
+			auth: &conf.AuthConfig{Enable: true, Identities: []*conf.AuthIdentity{
+				{Name: "a", Role: "root", Tokens: []string{"t"}}}},
+			wantErr: true,
+		},
+		{
+			name: "no credential",
+			auth: &conf.AuthConfig{Enable: true, Identities: []*conf.AuthIdentity{
+				{Name: "a", Role: RoleAdmin}}},
+			wantErr: true,
+		},
+		{
+			name: "ok",
+			auth: &conf.AuthConfig{Enable: true, Identities: []*conf.AuthIdentity{
+				{Name: "a", Role: RoleAdmin, Tokens: []string{"t"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, checkAuthConfig(tt.auth) != nil)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMetricsPath 默认的指标接口路径
+const defaultMetricsPath = "/metrics"
+
+// MetricsInterceptor 记录接口的调用次数和耗时，调用成功时使用响应中的错误码
+//
+//	@param ctx
+//	@param req
+//	@param info
+//	@param handler
+//	@return interface{}
+//	@return error
+func MetricsInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	code := responseCode(resp, err)
+	metrics.HandlerCalls.WithLabelValues(method, code).Inc()
+	metrics.ObserveSince(metrics.HandlerDuration.WithLabelValues(method, code), start)
+	return resp, err
+}
+
+// responseCode 出错时是grpc的错误码，否则是响应中的网关错误码
+//
+//	@param resp
+//	@param err
+//	@return string
+func responseCode(resp interface{}, err error) string {
+	if err != nil {
+		return status.Code(err).String()
+	}
+	if coder, ok := resp.(interface{ GetCode() common.Code }); ok {
+		return coder.GetCode().String()
+	}
+	return common.Code_GATEWAY_SUCCESS.String()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"chainmaker.org/chainmaker/tcip-go/v2/common/cross_chain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     interface{}
+		err      error
+		wantCode string
+	}{
+		{
+			name:     "success",
+			resp:     &cross_chain.CrossChainTryResponse{Code: common.Code_GATEWAY_SUCCESS},
+			wantCode: common.Code_GATEWAY_SUCCESS.String(),
+		},
+		{
+			name:     "gateway error",
+			resp:     &cross_chain.CrossChainTryResponse{Code: common.Code_INVALID_PARAMETER},
+			wantCode: common.Code_INVALID_PARAMETER.String(),
+		},
+		{
+			name:     "grpc error",
+			err:      status.Error(codes.PermissionDenied, "denied"),
+			wantCode: codes.PermissionDenied.String(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HandlerCalls.WithLabelValues("CrossChainTry", tt.wantCode)
+			before := testutil.ToFloat64(counter)
+			_, err := MetricsInterceptor(context.Background(), nil,
+				&grpc.UnaryServerInfo{FullMethod: "/api.RpcCrossChain/CrossChainTry"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return tt.resp, tt.err
+				})
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
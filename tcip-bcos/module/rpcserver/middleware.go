@@ -17,8 +17,7 @@ import (
 	"reflect"
 	"runtime/debug"
 	"strings"
-
-	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"time"
 
 	"google.golang.org/grpc/peer"
 
@@ -32,29 +31,6 @@ const (
 	UNKNOWN = "unknown"
 )
 
-// BlackListInterceptor - set ip blacklist interceptor
-//
-//	@return grpc.UnaryServerInterceptor
-func BlackListInterceptor() grpc.UnaryServerInterceptor {
-
-	blackIps := conf.Config.RpcConfig.BlackList
-
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
-		interface{}, error) {
-
-		ipAddr := getClientIp(ctx)
-		for _, blackIp := range blackIps {
-			if ipAddr == blackIp {
-				errMsg := fmt.Sprintf("%s is rejected by black list [%s]", info.FullMethod, ipAddr)
-				rpcLog.Warn(errMsg)
-				return nil, status.Error(codes.ResourceExhausted, errMsg)
-			}
-		}
-
-		return handler(ctx, req)
-	}
-}
-
 // LoggingInterceptor - set logging interceptor
 //
 //	@return unc
@@ -92,11 +68,6 @@ func RecoveryInterceptor(ctx context.Context, req interface{},
 	return handler(ctx, req)
 }
 
-func getClientIp(ctx context.Context) string {
-	addr := GetClientAddr(ctx)
-	return strings.Split(addr, ":")[0]
-}
-
 // GetClientAddr 获取客户端地址
 //
 //	@param ctx
@@ -116,9 +87,16 @@ func GetClientAddr(ctx context.Context) string {
 	return pr.Addr.String()
 }
 
-func GrpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
+// GrpcHandlerFunc 同一个端口上处理grpc和http请求
+//
+//	@param grpcServer
+//	@param otherHandler
+//	@param idleTimeout 连接没有请求多久后关闭，0表示不关闭
+//	@return http.Handler
+func GrpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler, idleTimeout time.Duration) http.Handler {
 	var http2Server = &http2.Server{
 		MaxConcurrentStreams: math.MaxUint32,
+		IdleTimeout:          idleTimeout,
 	}
 
 	if otherHandler == nil || reflect.ValueOf(otherHandler).IsNil() {
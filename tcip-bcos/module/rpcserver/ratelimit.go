@@ -0,0 +1,136 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// retryAfterKey 限流时告诉调用方多久之后重试，单位秒，restful代理会转为Retry-After
+const retryAfterKey = "retry-after"
+
+// rateLimiter 按调用方和接口限流，单独配置的接口使用自己的限流器
+type rateLimiter struct {
+	defaultLimiter *ratelimit.Limiter
+	methods        map[string]*ratelimit.Limiter
+}
+
+// newRateLimiter 根据配置新建限流器，没有开启时返回nil
+//
+//	@param config
+//	@return *rateLimiter
+//	@return error
+func newRateLimiter(config *conf.RateLimitConfig) (*rateLimiter, error) {
+	if !config.Enable {
+		return nil, nil
+	}
+	if config.Rate <= 0 {
+		return nil, fmt.Errorf("rpc rate limit is enabled but rate is %v", config.Rate)
+	}
+	r := &rateLimiter{
+		defaultLimiter: ratelimit.NewLimiter(config.Rate, config.Burst),
+		methods:        make(map[string]*ratelimit.Limiter),
+	}
+	for _, method := range config.Methods {
+		if method.Rate <= 0 {
+			return nil, fmt.Errorf("rpc rate limit of %s is %v", method.Method, method.Rate)
+		}
+		r.methods[method.Method] = ratelimit.NewLimiter(method.Rate, method.Burst)
+	}
+	return r, nil
+}
+
+// allow 检查调用方是否还可以调用接口，不可以时返回带重试时间的ResourceExhausted
+//
+//	@receiver r
+//	@param ctx
+//	@param fullMethod
+//	@return error
+func (r *rateLimiter) allow(ctx context.Context, fullMethod string) error {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	limiter, ok := r.methods[method]
+	if !ok {
+		limiter = r.defaultLimiter
+	}
+	caller := getCaller(ctx)
+	allowed, wait := limiter.Allow(caller + "|" + method)
+	if allowed {
+		return nil
+	}
+	retryAfter := int64(math.Ceil(wait.Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterKey, strconv.FormatInt(retryAfter, 10)))
+	rpcLog.Warnf("[rateLimiter] %s from %s is rate limited, retry after %s", fullMethod, caller, wait)
+	st := status.Newf(codes.ResourceExhausted, "%s is rate limited, retry after %ds", method, retryAfter)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// getCaller 限流的调用方，认证通过时使用身份名称，否则使用客户端ip
+//
+//	@param ctx
+//	@return string
+func getCaller(ctx context.Context) string {
+	if identity, ok := ctx.Value(identityKey{}).(*conf.AuthIdentity); ok {
+		return "identity:" + identity.Name
+	}
+	return "ip:" + getClientIp(ctx).String()
+}
+
+// RateLimitInterceptor 普通调用的限流拦截器，需要放在认证拦截器之后
+//
+//	@param config
+//	@return grpc.UnaryServerInterceptor
+//	@return error
+func RateLimitInterceptor(config *conf.RateLimitConfig) (grpc.UnaryServerInterceptor, error) {
+	limiter, err := newRateLimiter(config)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		if limiter != nil {
+			if err := limiter.allow(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}, nil
+}
+
+// StreamRateLimitInterceptor 流式调用的限流拦截器，每个流计一次
+//
+//	@param config
+//	@return grpc.StreamServerInterceptor
+//	@return error
+func StreamRateLimitInterceptor(config *conf.RateLimitConfig) (grpc.StreamServerInterceptor, error) {
+	limiter, err := newRateLimiter(config)
+	if err != nil {
+		return nil, err
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if limiter != nil {
+			if err := limiter.allow(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// testStream 只提供context的ServerStream
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	initTest(t)
+	conf.Config.RpcConfig = &conf.RpcConfig{}
+	access = &accessControl{}
+	config := &conf.RateLimitConfig{
+		Enable: true,
+		Rate:   1,
+		Burst:  2,
+		Methods: []*conf.MethodLimit{
+			{Method: "CrossChainTry", Rate: 1, Burst: 1},
+		},
+	}
+	interceptor, err := RateLimitInterceptor(config)
+	assert.Nil(t, err)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	newCtx := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1}})
+	}
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/api.RpcCrossChain/" + method}, handler)
+		return err
+	}
+
+	ctx := newCtx("10.0.0.1")
+	assert.Nil(t, call(ctx, "CrossChainTry"))
+	err = call(ctx, "CrossChainTry")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	details := status.Convert(err).Details()
+	assert.Equal(t, 1, len(details))
+	assert.True(t, details[0].(*errdetails.RetryInfo).RetryDelay.AsDuration() > 0)
+
+	// 不同的接口和调用方分别计算
+	assert.Nil(t, call(ctx, "CrossChainConfirm"))
+	assert.Nil(t, call(ctx, "CrossChainConfirm"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call(ctx, "CrossChainConfirm")))
+	assert.Nil(t, call(newCtx("10.0.0.2"), "CrossChainTry"))
+	assert.Nil(t, call(context.WithValue(ctx, identityKey{}, &conf.AuthIdentity{Name: "relay"}), "CrossChainTry"))
+
+	streamInterceptor, err := StreamRateLimitInterceptor(config)
+	assert.Nil(t, err)
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error { return nil }
+	info := &grpc.StreamServerInfo{FullMethod: "/api.RpcCrossChain/CrossChainTry"}
+	stream := &testStream{ctx: newCtx("10.0.0.3")}
+	assert.Nil(t, streamInterceptor(nil, stream, info, streamHandler))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(streamInterceptor(nil, stream, info, streamHandler)))
+
+	// 没有开启时不限流
+	interceptor, err = RateLimitInterceptor(&conf.RateLimitConfig{})
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, call(ctx, "CrossChainTry"))
+	}
+	_, err = RateLimitInterceptor(&conf.RateLimitConfig{Enable: true})
+	assert.NotNil(t, err)
+}
@@ -8,12 +8,13 @@ package rpcserver
 import (
 	"chainmaker.org/chainmaker/common/v2/ca"
 	"context"
-	"crypto/x509"
 	"fmt"
 	"google.golang.org/grpc/keepalive"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/tmc/grpc-websocket-proxy/wsproxy"
@@ -22,8 +23,13 @@ import (
 
 	cmtls "chainmaker.org/chainmaker/common/v2/crypto/tls"
 	cmx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/admin"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/admin/adminpb"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/certwatch"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/handler"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/health"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/metrics"
 	tcipApi "chainmaker.org/chainmaker/tcip-go/v2/api"
 
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
@@ -33,6 +39,22 @@ import (
 	"github.com/cloudflare/cfssl/log"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	defaultListenAddress = "0.0.0.0"
+	// unixSocketMode unix socket文件的权限，只允许当前用户访问
+	unixSocketMode = 0600
+	// gatewayNetwork restful代理进程内连接的地址类型
+	gatewayNetwork = "bufconn"
+	// gatewayBufSize restful代理进程内连接的缓冲区大小
+	gatewayBufSize = 1024 * 1024
+
+	defaultKeepaliveMinTime = 2 * time.Second // If a client pings more than once every 2 seconds, terminate the connection
+	defaultKeepaliveTime    = 5 * time.Second // Ping the client if it is idle for 5 seconds
+	defaultKeepaliveTimeout = 1 * time.Second // Wait 1 second for the ping ack before assuming the connection is dead
 )
 
 var (
@@ -48,6 +70,8 @@ type RPCServer struct {
 	cancel     context.CancelFunc
 	isShutdown bool
 	mixServer  *http.Server
+	// gatewayListener restful代理到grpc服务的进程内连接，没有开启restful时为空
+	gatewayListener *bufconn.Listener
 }
 
 // NewRpcServer 新建rpc服务
@@ -56,21 +80,27 @@ type RPCServer struct {
 //	@return error
 func NewRpcServer() (*RPCServer, error) {
 
+	rpcLog = logger.GetLogger(logger.ModuleRpcServer)
 	grpcServer, err := newGrpc()
 	if err != nil {
 		return nil, fmt.Errorf("new grpc server failed, %s", err.Error())
 	}
 
-	mixServer, err := newMixServer(grpcServer)
+	var gatewayListener *bufconn.Listener
+	if conf.Config.RpcConfig.RestfulConfig.Enable {
+		gatewayListener = bufconn.Listen(gatewayBufSize)
+	}
+
+	mixServer, err := newMixServer(grpcServer, gatewayListener)
 	if err != nil {
 		return nil, fmt.Errorf("new http grpc server failed, %s", err.Error())
 	}
 
-	rpcLog = logger.GetLogger(logger.ModuleRpcServer)
 	return &RPCServer{
-		grpcServer: grpcServer,
-		mixServer:  mixServer,
-		log:        rpcLog,
+		grpcServer:      grpcServer,
+		mixServer:       mixServer,
+		log:             rpcLog,
+		gatewayListener: gatewayListener,
 	}, nil
 }
 
@@ -79,58 +109,55 @@ func NewRpcServer() (*RPCServer, error) {
 //	@receiver s
 //	@return error
 func (s *RPCServer) Start() error {
-	var (
-		err       error
-		tlsConfig *cmtls.Config
-		caCerts   []string
-	)
-
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	s.isShutdown = false
 
-	if err = s.RegisterHandler(); err != nil {
+	if err := s.RegisterHandler(); err != nil {
 		return fmt.Errorf("register handler failed, %s", err.Error())
 	}
 
-	caCert, err := ioutil.ReadFile(conf.Config.RpcConfig.TLSConfig.CaFile)
-	if err != nil {
-		log.Errorf("read ca file failed, %s", err.Error())
-		return err
+	if s.gatewayListener != nil {
+		go func() {
+			if err := s.grpcServer.Serve(s.gatewayListener); err != nil && !s.isShutdown {
+				s.log.Errorf("RPCServer gateway serve failed, %s", err.Error())
+			}
+		}()
 	}
 
-	caCerts = []string{string(caCert)}
-
-	tlsConfig, err = ca.GetTLSConfig(conf.Config.RpcConfig.TLSConfig.CertFile,
-		conf.Config.RpcConfig.TLSConfig.KeyFile, []string{}, caCerts,
-		"", "")
-
+	endPoint := net.JoinHostPort(getListenAddress(), strconv.Itoa(conf.Config.RpcConfig.Port))
+	listener, err := listenTcp(endPoint, conf.Config.RpcConfig)
 	if err != nil {
-		log.Errorf("GetTLSConfig, failed, %s", err.Error())
 		return err
 	}
+	go s.serve(listener)
+	s.log.Infof("gRPC server listen on %s, plaintext: %t", endPoint, conf.Config.RpcConfig.Plaintext)
 
-	endPoint := fmt.Sprintf("%s:%d", "0.0.0.0",
-		conf.Config.RpcConfig.Port)
-	conn, err := net.Listen("tcp", endPoint)
-	if err != nil {
-		return fmt.Errorf("TCP listen failed, %s", err.Error())
-	}
-
-	go func() {
-		err = s.mixServer.Serve(ca.NewTLSListener(conn, tlsConfig))
-		if err == http.ErrServerClosed {
-			s.log.Info("RPCServer http closed")
-		} else {
-			s.log.Errorf("RPCServer http serve failed, %s", err.Error())
+	if socketPath := conf.Config.RpcConfig.UnixSocket; socketPath != "" {
+		listener, err = listenUnix(socketPath)
+		if err != nil {
+			return err
 		}
-	}()
-
-	s.log.Infof("gRPC server listen on %s", endPoint)
+		go s.serve(listener)
+		s.log.Infof("gRPC server listen on unix socket %s", socketPath)
+	}
 
 	return nil
 }
 
+// serve 在监听上提供grpc和http服务，直到服务关闭
+//
+//	@receiver s
+//	@param listener
+func (s *RPCServer) serve(listener net.Listener) {
+	err := s.mixServer.Serve(listener)
+	if err == http.ErrServerClosed {
+		s.log.Infof("RPCServer http closed on %s", listener.Addr())
+	} else {
+		s.log.Errorf("RPCServer http serve failed on %s, %s", listener.Addr(), err.Error())
+	}
+}
+
 // RegisterHandler - register apiservice handler to rpcserver
 //
 //	@receiver s
@@ -138,6 +165,10 @@ func (s *RPCServer) Start() error {
 func (s *RPCServer) RegisterHandler() error {
 	apiHandler := handler.NewHandler()
 	tcipApi.RegisterRpcCrossChainServer(s.grpcServer, apiHandler)
+	if conf.Config.RpcConfig.Admin.Enable {
+		adminpb.RegisterAdminServer(s.grpcServer, admin.NewServer())
+		s.log.Info("admin service is registered")
+	}
 	return nil
 }
 
@@ -148,64 +179,186 @@ func (s *RPCServer) Stop() {
 	s.isShutdown = true
 	s.cancel()
 	s.grpcServer.GracefulStop()
+	// 关闭监听，unix socket文件会被删除
+	if err := s.mixServer.Close(); err != nil {
+		s.log.Warnf("RPCServer http close failed, %s", err.Error())
+	}
 	s.log.Info("RPCServer is stopped!")
 }
 
-// newGrpc - new GRPC object
-func newGrpc() (*grpc.Server, error) {
-	var opts []grpc.ServerOption
-	opts = []grpc.ServerOption{
-		grpc_middleware.WithUnaryServerChain(
-			RecoveryInterceptor,
-			LoggingInterceptor,
-			BlackListInterceptor(),
-		),
+// listenTcp 监听tcp端口，没有开启plaintext时使用tls，证书文件变化后新的连接使用新证书
+//
+//	@param endPoint
+//	@param config
+//	@return net.Listener
+//	@return error
+func listenTcp(endPoint string, config *conf.RpcConfig) (net.Listener, error) {
+	var tlsConfig *cmtls.Config
+	if !config.Plaintext {
+		tlsConfig = &cmtls.Config{}
+		reloader, err := certwatch.NewReloader("rpc server",
+			[]string{config.TLSConfig.CaFile, config.TLSConfig.CertFile, config.TLSConfig.KeyFile},
+			func() (interface{}, error) {
+				return loadTlsConfig(&config.TLSConfig)
+			}, rpcLog)
+		if err != nil {
+			log.Errorf("GetTLSConfig, failed, %s", err.Error())
+			return nil, err
+		}
+		tlsConfig.GetConfigForClient = func(*cmtls.ClientHelloInfo) (*cmtls.Config, error) {
+			return reloader.Get().(*cmtls.Config), nil
+		}
+	}
+	conn, err := net.Listen("tcp", endPoint)
+	if err != nil {
+		return nil, fmt.Errorf("TCP listen failed, %s", err.Error())
 	}
+	if config.Plaintext {
+		rpcLog.Warn("rpc plaintext mode is enabled, traffic is not encrypted and client certificates are unavailable")
+		return conn, nil
+	}
+	return ca.NewTLSListener(conn, tlsConfig), nil
+}
 
-	caCert, err := ioutil.ReadFile(conf.Config.RpcConfig.TLSConfig.CaFile)
+// loadTlsConfig 读取rpc服务的证书、私钥和ca，配置了私钥密码时在内存中解密私钥，不会写入磁盘
+//
+//	@param config
+//	@return *cmtls.Config
+//	@return error
+func loadTlsConfig(config *conf.TlsConfig) (*cmtls.Config, error) {
+	caCert, err := ioutil.ReadFile(config.CaFile)
 	if err != nil {
-		log.Errorf("read ca file failed, %s", err.Error())
-		return nil, err
+		return nil, fmt.Errorf("read ca file failed, %s", err.Error())
+	}
+	if config.KeyPassword == "" {
+		return ca.GetTLSConfig(config.CertFile, config.KeyFile, []string{}, []string{string(caCert)}, "", "")
+	}
+	certPem, err := ioutil.ReadFile(config.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("read cert file failed, %s", err.Error())
+	}
+	keyPem, err := certwatch.ReadKey(config.KeyFile, config.KeyPassword)
+	if err != nil {
+		return nil, fmt.Errorf("read key file failed, %s", err.Error())
+	}
+	cert, err := cmtls.X509KeyPair(certPem, keyPem)
+	if err != nil {
+		return nil, fmt.Errorf("load X509 key pair failed, %s", err.Error())
+	}
+	certPool := cmx509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("append ca cert to pool failed")
+	}
+	// 和ca.GetTLSConfig一样要求并校验客户端证书
+	return &cmtls.Config{
+		Certificates: []cmtls.Certificate{cert},
+		ClientAuth:   cmtls.RequireAndVerifyClientCert,
+		ClientCAs:    certPool,
+	}, nil
+}
+
+// listenUnix 监听unix socket，删除上次没有清理的socket文件，只允许当前用户访问
+//
+//	@param socketPath
+//	@return net.Listener
+//	@return error
+func listenUnix(socketPath string) (net.Listener, error) {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove stale unix socket failed, %s", err.Error())
 	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("unix socket listen failed, %s", err.Error())
+	}
+	if err = os.Chmod(socketPath, unixSocketMode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("chmod unix socket failed, %s", err.Error())
+	}
+	return listener, nil
+}
 
-	caCerts := []string{string(caCert)}
+// getListenAddress 监听地址，默认0.0.0.0
+//
+//	@return string
+func getListenAddress() string {
+	if conf.Config.RpcConfig.Address == "" {
+		return defaultListenAddress
+	}
+	return conf.Config.RpcConfig.Address
+}
 
-	tlsRPCServer := ca.CAServer{
-		CaCerts:  caCerts,
-		CertFile: conf.Config.RpcConfig.TLSConfig.CertFile,
-		KeyFile:  conf.Config.RpcConfig.TLSConfig.KeyFile,
-		Logger:   rpcLog,
+// getKeepalive 根据配置生成grpc的保活参数，没有配置的参数使用默认值
+//
+//	@param config
+//	@return keepalive.EnforcementPolicy
+//	@return keepalive.ServerParameters
+func getKeepalive(config *conf.KeepaliveConfig) (keepalive.EnforcementPolicy, keepalive.ServerParameters) {
+	kaep := keepalive.EnforcementPolicy{
+		MinTime:             defaultKeepaliveMinTime,
+		PermitWithoutStream: true,
+	}
+	kasp := keepalive.ServerParameters{
+		Time:              defaultKeepaliveTime,
+		Timeout:           defaultKeepaliveTimeout,
+		MaxConnectionIdle: time.Duration(config.MaxConnectionIdle) * time.Second,
+	}
+	if config.MinTime > 0 {
+		kaep.MinTime = time.Duration(config.MinTime) * time.Second
+	}
+	if config.PermitWithoutStream != nil {
+		kaep.PermitWithoutStream = *config.PermitWithoutStream
 	}
+	if config.Time > 0 {
+		kasp.Time = time.Duration(config.Time) * time.Second
+	}
+	if config.Timeout > 0 {
+		kasp.Timeout = time.Duration(config.Timeout) * time.Second
+	}
+	return kaep, kasp
+}
 
-	customVerify := ca.CustomVerify{
-		VerifyPeerCertificate: func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			return nil
-		},
-		GMVerifyPeerCertificate: func(rawCerts [][]byte, verifiedChains [][]*cmx509.Certificate) error {
-			return nil
-		},
+// newGrpc - new GRPC object
+func newGrpc() (*grpc.Server, error) {
+	if err := checkAuthConfig(&conf.Config.RpcConfig.Auth); err != nil {
+		return nil, err
+	}
+	if conf.Config.RpcConfig.Admin.Enable && !conf.Config.RpcConfig.Auth.Enable {
+		return nil, fmt.Errorf("rpc admin service requires rpc auth to be enabled")
+	}
+	if !conf.Config.RpcConfig.Auth.Enable {
+		rpcLog.Warn("rpc auth is disabled, any client trusted by the tls ca can call the gateway")
 	}
 
-	c, err := tlsRPCServer.GetCredentialsByCA(false, customVerify)
+	rateLimit, err := RateLimitInterceptor(&conf.Config.RpcConfig.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	streamRateLimit, err := StreamRateLimitInterceptor(&conf.Config.RpcConfig.RateLimit)
 	if err != nil {
-		log.Errorf("new gRPC failed, GetTLSCredentialsByCA err: %v", err)
 		return nil, err
 	}
 
-	opts = append(opts, grpc.Creds(*c))
+	var opts []grpc.ServerOption
+	opts = []grpc.ServerOption{
+		grpc_middleware.WithUnaryServerChain(
+			RecoveryInterceptor,
+			MetricsInterceptor,
+			LoggingInterceptor,
+			IpFilterInterceptor(),
+			AuthInterceptor(),
+			rateLimit,
+		),
+		grpc_middleware.WithStreamServerChain(
+			streamRateLimit,
+		),
+	}
 
+	// tls由监听负责，grpc服务本身不配置证书，restful代理的进程内连接不加密
 	opts = append(opts, grpc.MaxSendMsgSize(conf.Config.RpcConfig.MaxSendMsgSize*1024*1024))
 	opts = append(opts, grpc.MaxRecvMsgSize(conf.Config.RpcConfig.MaxRecvMsgSize*1024*1024))
 
 	// keep alive
-	var kaep = keepalive.EnforcementPolicy{
-		MinTime:             2 * time.Second, // If a client pings more than once every 2 seconds, terminate the connection
-		PermitWithoutStream: true,            // Allow pings even when there are no active streams
-	}
-	var kasp = keepalive.ServerParameters{
-		Time:    5 * time.Second, // Ping the client if it is idle for 5 seconds to ensure the connection is still active
-		Timeout: 1 * time.Second, // Wait 1 second for the ping ack before assuming the connection is dead
-	}
+	kaep, kasp := getKeepalive(&conf.Config.RpcConfig.Keepalive)
 	opts = append(opts, grpc.KeepaliveEnforcementPolicy(kaep), grpc.KeepaliveParams(kasp))
 
 	server := grpc.NewServer(opts...)
@@ -213,25 +366,34 @@ func newGrpc() (*grpc.Server, error) {
 	return server, nil
 }
 
-func newMixServer(grpcServer *grpc.Server) (*http.Server, error) {
+func newMixServer(grpcServer *grpc.Server, gatewayListener *bufconn.Listener) (*http.Server, error) {
 
-	var (
-		mux        *http.ServeMux
-		httpServer *http.Server
-	)
+	var httpServer *http.Server
+
+	mux := http.NewServeMux()
+	mux.Handle(health.LivenessPath, IpFilterHandler(health.LivenessHandler()))
+	mux.Handle(health.ReadinessPath, IpFilterHandler(health.ReadinessHandler()))
 
 	if conf.Config.RpcConfig.RestfulConfig.Enable {
-		mux = http.NewServeMux()
-		gwmux, err := newGateway()
+		gwmux, err := newGateway(gatewayListener)
 		if err != nil {
 			log.Error(err)
 			return nil, err
 		}
 
-		mux.Handle("/", gwmux)
+		mux.Handle("/", IpFilterHandler(gwmux))
 	}
 
-	handler := GrpcHandlerFunc(grpcServer, mux)
+	if conf.Config.RpcConfig.Metrics.Enable {
+		metricsPath := conf.Config.RpcConfig.Metrics.Path
+		if metricsPath == "" {
+			metricsPath = defaultMetricsPath
+		}
+		mux.Handle(metricsPath, IpFilterHandler(metrics.Handler()))
+	}
+
+	_, kasp := getKeepalive(&conf.Config.RpcConfig.Keepalive)
+	handler := GrpcHandlerFunc(grpcServer, mux, kasp.MaxConnectionIdle)
 
 	if conf.Config.RpcConfig.RestfulConfig.Enable {
 		httpServer = &http.Server{
@@ -240,50 +402,65 @@ func newMixServer(grpcServer *grpc.Server) (*http.Server, error) {
 	} else {
 		httpServer = &http.Server{Handler: handler}
 	}
+	httpServer.ConnContext = ConnContext
+	httpServer.IdleTimeout = kasp.MaxConnectionIdle
 
 	return httpServer, nil
 }
 
-func newGateway() (http.Handler, error) {
+// newGateway restful代理，通过进程内连接调用grpc服务，经过和grpc请求相同的拦截器
+//
+//	@param gatewayListener
+//	@return http.Handler
+//	@return error
+func newGateway(gatewayListener *bufconn.Listener) (http.Handler, error) {
 	ctx := context.Background()
 
-	dopts := []grpc.DialOption{}
-	caCert, err := ioutil.ReadFile(conf.Config.RpcConfig.TLSConfig.CaFile)
+	conn, err := dialGateway(ctx, gatewayListener)
 	if err != nil {
-		log.Errorf("read ca file failed, %s", err.Error())
+		log.Errorf("new gateway failed, dial grpc server err: %v", err)
 		return nil, err
 	}
 
-	caCerts := []string{string(caCert)}
-
-	tlsClient := ca.CAClient{
-		CaCerts:  caCerts,
-		CertFile: conf.Config.RpcConfig.TLSConfig.CertFile,
-		KeyFile:  conf.Config.RpcConfig.TLSConfig.KeyFile,
-		Logger:   rpcLog,
-	}
-
-	c, err := tlsClient.GetCredentialsByCA()
-	if err != nil {
-		log.Errorf("new gateway failed, GetTLSCredentialsByCA err: %v", err)
-		return nil, err
-	}
-
-	dopts = append(dopts, grpc.WithTransportCredentials(*c))
-
-	// NOTE: the mix http server certificate is valid for 127.0.0.1, so we must use 127.0.0.1
-	endPoint := fmt.Sprintf("%s:%d", "127.0.0.1", conf.Config.RpcConfig.Port)
-
 	gwmux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard,
 			&runtime.JSONPb{OrigName: true, EmitDefaults: false, EnumsAsInts: true},
 		),
+		// 限流时的重试时间转为标准的Retry-After
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			if key == retryAfterKey {
+				return "Retry-After", true
+			}
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
+		// unix socket的请求没有客户端ip，记录为本机地址
+		runtime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			if isUnixConn(r.Context()) {
+				return metadata.Pairs(forwardedForKey, unixClientIp.String())
+			}
+			return nil
+		}),
 	)
 
-	if err := tcipApi.RegisterRpcCrossChainHandlerFromEndpoint(ctx, gwmux, endPoint, dopts); err != nil {
-		log.Errorf("new gateway failed, RegisterRpcNodeHandlerFromEndpoint err: %v", err)
+	if err := tcipApi.RegisterRpcCrossChainHandler(ctx, gwmux, conn); err != nil {
+		log.Errorf("new gateway failed, RegisterRpcCrossChainHandler err: %v", err)
 		return nil, err
 	}
 
 	return gwmux, nil
 }
+
+// dialGateway 建立restful代理到grpc服务的进程内连接
+//
+//	@param ctx
+//	@param gatewayListener
+//	@return *grpc.ClientConn
+//	@return error
+func dialGateway(ctx context.Context, gatewayListener *bufconn.Listener) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, gatewayNetwork,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return gatewayListener.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+}
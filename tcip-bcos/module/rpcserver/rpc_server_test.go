@@ -0,0 +1,154 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	cmtls "chainmaker.org/chainmaker/common/v2/crypto/tls"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGetKeepalive(t *testing.T) {
+	permit := false
+	tests := []struct {
+		name   string
+		config *conf.KeepaliveConfig
+		want   [4]time.Duration
+		permit bool
+	}{
+		{
+			name:   "default",
+			config: &conf.KeepaliveConfig{},
+			want:   [4]time.Duration{2 * time.Second, 5 * time.Second, time.Second, 0},
+			permit: true,
+		},
+		{
+			name: "configured",
+			config: &conf.KeepaliveConfig{MinTime: 10, PermitWithoutStream: &permit, Time: 30, Timeout: 3,
+				MaxConnectionIdle: 600},
+			want:   [4]time.Duration{10 * time.Second, 30 * time.Second, 3 * time.Second, 600 * time.Second},
+			permit: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kaep, kasp := getKeepalive(tt.config)
+			assert.Equal(t, tt.want, [4]time.Duration{kaep.MinTime, kasp.Time, kasp.Timeout, kasp.MaxConnectionIdle})
+			assert.Equal(t, tt.permit, kaep.PermitWithoutStream)
+		})
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-unix")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := path.Join(dir, "tcip.sock")
+	// 上次没有清理的socket文件
+	assert.Nil(t, ioutil.WriteFile(socketPath, nil, 0644))
+
+	listener, err := listenUnix(socketPath)
+	assert.Nil(t, err)
+	info, err := os.Stat(socketPath)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(unixSocketMode), info.Mode().Perm())
+	assert.Nil(t, listener.Close())
+	_, err = os.Stat(socketPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDialGateway(t *testing.T) {
+	listener := bufconn.Listen(gatewayBufSize)
+	gatewayConn := make(chan bool, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		gatewayConn <- isGatewayConn(ctx)
+		return handler(ctx, req)
+	}))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := dialGateway(context.Background(), listener)
+	assert.Nil(t, err)
+	defer conn.Close()
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.True(t, <-gatewayConn)
+
+	// 其他连接不是restful代理
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = server.Serve(tcpListener)
+	}()
+	tcpConn, err := grpc.Dial(tcpListener.Addr().String(), grpc.WithInsecure())
+	assert.Nil(t, err)
+	defer tcpConn.Close()
+	_, err = grpc_health_v1.NewHealthClient(tcpConn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.False(t, <-gatewayConn)
+}
+
+func TestLoadTlsConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc_tls")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	keyPem, err := ioutil.ReadFile("../../config/cert/server/server.key")
+	assert.Nil(t, err)
+	block, _ := pem.Decode(keyPem)
+	encrypted, err := x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte("password"),
+		x509.PEMCipherAES256)
+	assert.Nil(t, err)
+	encryptedFile := path.Join(dir, "server.key")
+	assert.Nil(t, ioutil.WriteFile(encryptedFile, pem.EncodeToMemory(encrypted), 0600))
+
+	tests := []struct {
+		name     string
+		keyFile  string
+		password string
+		wantErr  bool
+	}{
+		{name: "encrypted key", keyFile: encryptedFile, password: "password"},
+		{name: "password for plain key", keyFile: "../../config/cert/server/server.key", password: "password",
+			wantErr: true},
+		{name: "missing key", keyFile: path.Join(dir, "missing.key"), password: "password", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := loadTlsConfig(&conf.TlsConfig{
+				CaFile:      "../../config/cert/server/ca.crt",
+				CertFile:    "../../config/cert/server/server.crt",
+				KeyFile:     tt.keyFile,
+				KeyPassword: tt.password,
+			})
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(config.Certificates))
+			assert.Equal(t, cmtls.RequireAndVerifyClientCert, config.ClientAuth)
+		})
+	}
+}
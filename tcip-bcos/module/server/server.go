@@ -6,18 +6,34 @@ SPDX-License-Identifier: Apache-2.0
 package server
 
 import (
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/certwatch"
 	chain_client "chainmaker.org/chainmaker/tcip-bcos/v2/module/chain-client"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/event"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/gateway"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/health"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
 	"chainmaker.org/chainmaker/tcip-bcos/v2/module/request"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/txrecord"
 )
 
+// defaultTxRecordRetention 跨链交易记录默认保存的天数
+const defaultTxRecordRetention = 30
+
 // InitServer 初始化服务
 //
 //	@param errorC
 func InitServer(errorC chan error) {
 	// 初始化db
 	db.NewDbHandle()
+	// 加载网关禁用状态
+	if err := gateway.Load(); err != nil {
+		errorC <- err
+		return
+	}
 	// 初始化跨链触发器
 	event.InitEventManager()
 	// 初始化 request manager
@@ -30,4 +46,36 @@ func InitServer(errorC chan error) {
 		errorC <- err
 		return
 	}
+	// 定时对账跨链事件配置缓存
+	go event.EventManagerV1.StartSync()
+	// 监控证书有效期
+	registerCerts()
+	certwatch.Start(conf.Config.CertMonitor)
+	// 后台刷新健康状态
+	health.Start()
+	// 定时清理过期的跨链交易记录
+	go txrecord.StartPrune(getTxRecordRetention(), logger.GetLogger(logger.ModuleDb))
+}
+
+// registerCerts 登记rpc服务和中继网关客户端使用的证书
+func registerCerts() {
+	if conf.Config.RpcConfig != nil && !conf.Config.RpcConfig.Plaintext {
+		certwatch.Register("rpc_server", conf.Config.RpcConfig.TLSConfig.CertFile)
+		certwatch.Register("rpc_ca", conf.Config.RpcConfig.TLSConfig.CaFile)
+	}
+	if conf.Config.Relay != nil {
+		certwatch.Register("relay_client", conf.Config.Relay.ClientCert)
+		certwatch.Register("relay_ca", conf.Config.Relay.Tlsca)
+	}
+}
+
+// getTxRecordRetention 跨链交易记录的保存时间
+//
+//	@return time.Duration
+func getTxRecordRetention() time.Duration {
+	retention := uint64(defaultTxRecordRetention)
+	if conf.Config.TxRecord != nil && conf.Config.TxRecord.Retention > 0 {
+		retention = conf.Config.TxRecord.Retention
+	}
+	return time.Duration(retention) * 24 * time.Hour
 }
@@ -0,0 +1,142 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txrecord
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// PhaseBegin 源链事件转发给中继网关
+	PhaseBegin = "begin"
+	// PhaseTry 目标链执行跨链交易
+	PhaseTry = "try"
+	// PhaseConfirm 跨链成功后的确认交易
+	PhaseConfirm = "confirm"
+	// PhaseCancel 跨链失败后的回滚交易
+	PhaseCancel = "cancel"
+
+	// keyFormat 交易记录的key: txrecord#纳秒时间#阶段，按时间排序
+	keyFormat = "txrecord#%020d#%s"
+	// keyPrefix 全部交易记录的前缀
+	keyPrefix = "txrecord#"
+	// keyLimit 全部交易记录迭代的上界，'$'是'#'的下一个字符
+	keyLimit = "txrecord$"
+	// pruneInterval 检查过期交易记录的间隔
+	pruneInterval = time.Hour
+)
+
+// Record 网关经手的一笔跨链交易
+type Record struct {
+	CrossChainId string `json:"cross_chain_id"`
+	Phase        string `json:"phase"`
+	ChainRid     string `json:"chain_rid"`
+	TxId         string `json:"tx_id,omitempty"`
+	BlockHeight  int64  `json:"block_height,omitempty"`
+	Success      bool   `json:"success"`
+	Message      string `json:"message,omitempty"`
+	// Time 纳秒时间戳
+	Time int64 `json:"time"`
+}
+
+// Put 保存交易记录，Time为空时使用当前时间
+//
+//	@param record
+//	@return error
+func Put(record *Record) error {
+	if record.Time == 0 {
+		record.Time = time.Now().UnixNano()
+	}
+	recordByte, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal tx record error: %s", err.Error())
+	}
+	return db.Db.Put([]byte(fmt.Sprintf(keyFormat, record.Time, record.Phase)), recordByte)
+}
+
+// List 按时间倒序列出交易记录，crossChainId和chainRid为空时不过滤
+//
+//	@param crossChainId
+//	@param chainRid
+//	@param limit
+//	@return []*Record
+//	@return error
+func List(crossChainId, chainRid string, limit int) ([]*Record, error) {
+	iter, err := db.Db.NewIteratorWithRange([]byte(keyPrefix), []byte(keyLimit))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+	records := make([]*Record, 0)
+	for ok := iter.Last(); ok && len(records) < limit; ok = iter.Prev() {
+		record := &Record{}
+		if err = json.Unmarshal(iter.Value(), record); err != nil {
+			return nil, fmt.Errorf("unmarshal tx record error: %s", err.Error())
+		}
+		if crossChainId != "" && record.CrossChainId != crossChainId {
+			continue
+		}
+		if chainRid != "" && record.ChainRid != chainRid {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Prune 删除before之前的交易记录
+//
+//	@param before
+//	@return int 删除的条数
+//	@return error
+func Prune(before time.Time) (int, error) {
+	iter, err := db.Db.NewIteratorWithRange([]byte(keyPrefix),
+		[]byte(fmt.Sprintf(keyFormat, before.UnixNano(), "")))
+	if err != nil {
+		return 0, err
+	}
+	keys := make([][]byte, 0)
+	for iter.Next() {
+		keys = append(keys, append([]byte{}, iter.Key()...))
+	}
+	err = iter.Error()
+	iter.Release()
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err = db.Db.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+// StartPrune 定时删除超过保存时间的交易记录
+//
+//	@param retention
+//	@param log
+func StartPrune(retention time.Duration, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		count, err := Prune(time.Now().Add(-retention))
+		if err != nil {
+			log.Errorf("[StartPrune] %s", err.Error())
+		} else if count != 0 {
+			log.Infof("[StartPrune] %d tx records older than %s pruned", count, retention)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txrecord
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/conf"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/db"
+	"chainmaker.org/chainmaker/tcip-bcos/v2/module/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTest() {
+	log := []*logger.LogModuleConfig{
+		{
+			ModuleName:   "default",
+			FilePath:     path.Join(os.TempDir(), time.Now().String()),
+			LogInConsole: true,
+		},
+	}
+	conf.Config.DbPath = path.Join(os.TempDir(), time.Now().String())
+	logger.InitLogConfig(log)
+	db.NewDbHandle()
+}
+
+func TestPutListPrune(t *testing.T) {
+	initTest()
+	now := time.Now()
+	records := []*Record{
+		{CrossChainId: "1", Phase: PhaseBegin, ChainRid: "chain1", Success: true,
+			Time: now.Add(-48 * time.Hour).UnixNano()},
+		{CrossChainId: "1", Phase: PhaseTry, ChainRid: "chain2", Success: true, Time: now.Add(-time.Hour).UnixNano()},
+		{CrossChainId: "2", Phase: PhaseBegin, ChainRid: "chain1", Message: "timeout"},
+	}
+	for _, record := range records {
+		assert.Nil(t, Put(record))
+	}
+
+	tests := []struct {
+		name         string
+		crossChainId string
+		chainRid     string
+		limit        int
+		wantPhases   []string
+	}{
+		{name: "all", limit: 10, wantPhases: []string{PhaseBegin, PhaseTry, PhaseBegin}},
+		{name: "limit", limit: 1, wantPhases: []string{PhaseBegin}},
+		{name: "cross chain id", crossChainId: "1", limit: 10, wantPhases: []string{PhaseTry, PhaseBegin}},
+		{name: "chain rid", chainRid: "chain2", limit: 10, wantPhases: []string{PhaseTry}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := List(tt.crossChainId, tt.chainRid, tt.limit)
+			assert.Nil(t, err)
+			phases := make([]string, 0)
+			for _, record := range got {
+				phases = append(phases, record.Phase)
+			}
+			assert.Equal(t, tt.wantPhases, phases)
+		})
+	}
+
+	count, err := Prune(now.Add(-24 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	got, err := List("", "", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, "2", got[0].CrossChainId)
+}
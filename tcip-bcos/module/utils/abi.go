@@ -0,0 +1,88 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bcosabi "github.com/FISCO-BCOS/go-sdk/abi"
+)
+
+// ParseAbi 解析合约abi
+//
+//	@param abiStr
+//	@return *bcosabi.ABI
+//	@return error
+func ParseAbi(abiStr string) (*bcosabi.ABI, error) {
+	parsed, err := bcosabi.JSON(strings.NewReader(abiStr))
+	if err != nil {
+		return nil, fmt.Errorf("abi read error: %s", err.Error())
+	}
+	return &parsed, nil
+}
+
+// CheckAbiMethod 检查abi中存在该方法，并且参数个数在[minInputs, maxInputs]之间，maxInputs小于0表示不限制上限
+//
+//	@param parsed
+//	@param method
+//	@param minInputs
+//	@param maxInputs
+//	@return error
+func CheckAbiMethod(parsed *bcosabi.ABI, method string, minInputs, maxInputs int) error {
+	abiMethod, ok := parsed.Methods[method]
+	if !ok {
+		return fmt.Errorf("method %s not found in abi", method)
+	}
+	inputs := len(abiMethod.Inputs)
+	if inputs < minInputs || (maxInputs >= 0 && inputs > maxInputs) {
+		if maxInputs < 0 {
+			return fmt.Errorf("method %s has %d inputs, need at least %d", method, inputs, minInputs)
+		}
+		if minInputs == maxInputs {
+			return fmt.Errorf("method %s has %d inputs, need %d", method, inputs, minInputs)
+		}
+		return fmt.Errorf("method %s has %d inputs, need %d to %d", method, inputs, minInputs, maxInputs)
+	}
+	return nil
+}
+
+// InputStrings 取出合约调用输入中的string参数，不知道方法的abi时参数类型无法确定，
+// 头部中每个可以作为合法偏移量的值都按照string解析，调用方需要自己校验结果
+//
+//	@param input 十六进制的交易输入，前4个字节是方法选择器
+//	@return []string
+func InputStrings(input string) []string {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil || len(data) < 4 {
+		return nil
+	}
+	data = data[4:]
+	args := make([]string, 0)
+	// 动态参数的数据都在头部之后，最小的偏移量就是头部的长度
+	headEnd := len(data)
+	for i := 0; i+32 <= headEnd; i += 32 {
+		offset := new(big.Int).SetBytes(data[i : i+32])
+		if !offset.IsInt64() || offset.Int64() > int64(len(data)) {
+			continue
+		}
+		start := int(offset.Int64())
+		if start%32 != 0 || start < i+32 || start+32 > len(data) {
+			continue
+		}
+		length := new(big.Int).SetBytes(data[start : start+32])
+		if !length.IsInt64() || length.Int64() > int64(len(data)-start-32) {
+			continue
+		}
+		args = append(args, string(data[start+32:start+32+int(length.Int64())]))
+		if start < headEnd {
+			headEnd = start
+		}
+	}
+	return args
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bcosabi "github.com/FISCO-BCOS/go-sdk/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAbi = `[{"constant":false,"inputs":[{"name":"a","type":"string"},{"name":"b","type":"string"}],` +
+	`"name":"confirm","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},` +
+	`{"constant":false,"inputs":[],"name":"cancel","outputs":[],"payable":false,` +
+	`"stateMutability":"nonpayable","type":"function"}]`
+
+func TestCheckAbiMethod(t *testing.T) {
+	_, err := ParseAbi("not abi")
+	assert.NotNil(t, err)
+
+	parsed, err := ParseAbi(testAbi)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		method    string
+		minInputs int
+		maxInputs int
+		wantErr   bool
+	}{
+		{"exact", "confirm", 2, 2, false},
+		{"unlimited", "confirm", 1, -1, false},
+		{"too many", "confirm", 1, 1, true},
+		{"too few", "cancel", 1, -1, true},
+		{"not found", "try", 0, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckAbiMethod(parsed, tt.method, tt.minInputs, tt.maxInputs); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAbiMethod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInputStrings(t *testing.T) {
+	stringType, _ := bcosabi.NewType("string", "", nil)
+	uintType, _ := bcosabi.NewType("uint256", "", nil)
+	args := bcosabi.Arguments{{Type: stringType}, {Type: uintType}, {Type: stringType}}
+	packed, err := args.Pack("cross001", big.NewInt(64), "")
+	assert.Nil(t, err)
+	selector := "0x12345678"
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"strings", selector + hex.EncodeToString(packed), []string{"cross001", ""}},
+		{"no args", selector, []string{}},
+		{"not hex", "0xzz", nil},
+		{"too short", "0x12", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, InputStrings(tt.input))
+		})
+	}
+}
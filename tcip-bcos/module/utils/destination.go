@@ -0,0 +1,136 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+)
+
+// Destination 跨链事件配置中的一个跨链目标
+//
+// NewCrossChain中的Dest字段只能描述一个目标，多个目标时DestGatewayId填写json字符串数组，
+// 例如["1","2"]，其余Dest字段可以填写同样长度的json字符串数组按下标对应，也可以填写单个值由所有目标共用
+type Destination struct {
+	GatewayId     string
+	ChainRid      string
+	ContractName  string
+	TryMethod     string
+	ConfirmMethod string
+	CancelMethod  string
+	Abi           string
+}
+
+// GetDestinations 解析跨链事件配置中的全部跨链目标
+//
+//	@param event
+//	@return []*Destination
+//	@return error
+func GetDestinations(event *common.NewCrossChain) ([]*Destination, error) {
+	gatewayIds, ok := parseMultiValue(event.DestGatewayId)
+	if !ok {
+		return []*Destination{
+			{
+				GatewayId:     event.DestGatewayId,
+				ChainRid:      event.DestChainRid,
+				ContractName:  event.DestContractName,
+				TryMethod:     event.DestTryMethod,
+				ConfirmMethod: event.DestConfirmMethod,
+				CancelMethod:  event.DestCancelMethod,
+				Abi:           event.DestAbi,
+			},
+		}, nil
+	}
+	count := len(gatewayIds)
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"DestChainRid", event.DestChainRid},
+		{"DestContractName", event.DestContractName},
+		{"DestTryMethod", event.DestTryMethod},
+		{"DestConfirmMethod", event.DestConfirmMethod},
+		{"DestCancelMethod", event.DestCancelMethod},
+		{"DestAbi", event.DestAbi},
+	}
+	values := make([][]string, len(fields))
+	for i, field := range fields {
+		multiValue, ok := parseMultiValue(field.value)
+		if !ok {
+			multiValue = make([]string, count)
+			for j := range multiValue {
+				multiValue[j] = field.value
+			}
+		}
+		if len(multiValue) != count {
+			return nil, fmt.Errorf("%s has %d values, but DestGatewayId has %d",
+				field.name, len(multiValue), count)
+		}
+		values[i] = multiValue
+	}
+	destinations := make([]*Destination, count)
+	for i := 0; i < count; i++ {
+		destinations[i] = &Destination{
+			GatewayId:     gatewayIds[i],
+			ChainRid:      values[0][i],
+			ContractName:  values[1][i],
+			TryMethod:     values[2][i],
+			ConfirmMethod: values[3][i],
+			CancelMethod:  values[4][i],
+			Abi:           values[5][i],
+		}
+	}
+	return destinations, nil
+}
+
+// SplitDestParam 按照跨链目标的数量拆分TriggerInfo中的目标参数
+//
+// 只有一个目标时参数原样返回，多个目标时参数必须是同样长度的json字符串数组，空参数表示所有目标都没有参数
+//
+//	@param name 参数名称，用于错误信息
+//	@param param
+//	@param count 跨链目标数量
+//	@return []string
+//	@return error
+func SplitDestParam(name, param string, count int) ([]string, error) {
+	if count == 1 {
+		return []string{param}, nil
+	}
+	if param == "" {
+		return make([]string, count), nil
+	}
+	params := make([]string, 0)
+	if err := json.Unmarshal([]byte(param), &params); err != nil {
+		return nil, fmt.Errorf("%s must be a json string array for %d destinations: %s",
+			name, count, err.Error())
+	}
+	if len(params) != count {
+		return nil, fmt.Errorf("%s has %d values, but there are %d destinations", name, len(params), count)
+	}
+	return params, nil
+}
+
+// parseMultiValue 解析json字符串数组形式的多值字段，不是数组或者少于两个值时返回false
+//
+//	@param value
+//	@return []string
+//	@return bool
+func parseMultiValue(value string) ([]string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		return nil, false
+	}
+	values := make([]string, 0)
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, false
+	}
+	if len(values) < 2 {
+		return nil, false
+	}
+	return values, true
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/tcip-go/v2/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDestinations(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   *common.NewCrossChain
+		want    []*Destination
+		wantErr bool
+	}{
+		{
+			name: "single",
+			event: &common.NewCrossChain{
+				DestGatewayId:    "1",
+				DestChainRid:     "chain1",
+				DestContractName: "contract1",
+				DestTryMethod:    "try",
+				DestAbi:          `[{"type":"function"}]`,
+			},
+			want: []*Destination{
+				{
+					GatewayId:    "1",
+					ChainRid:     "chain1",
+					ContractName: "contract1",
+					TryMethod:    "try",
+					Abi:          `[{"type":"function"}]`,
+				},
+			},
+		},
+		{
+			name: "multiple",
+			event: &common.NewCrossChain{
+				DestGatewayId:    `["1","2"]`,
+				DestChainRid:     `["chain1","chain2"]`,
+				DestContractName: "contract",
+				DestTryMethod:    `["try1","try2"]`,
+				DestAbi:          `[{"type":"function"}]`,
+			},
+			want: []*Destination{
+				{
+					GatewayId:    "1",
+					ChainRid:     "chain1",
+					ContractName: "contract",
+					TryMethod:    "try1",
+					Abi:          `[{"type":"function"}]`,
+				},
+				{
+					GatewayId:    "2",
+					ChainRid:     "chain2",
+					ContractName: "contract",
+					TryMethod:    "try2",
+					Abi:          `[{"type":"function"}]`,
+				},
+			},
+		},
+		{
+			name: "count not match",
+			event: &common.NewCrossChain{
+				DestGatewayId: `["1","2"]`,
+				DestChainRid:  `["chain1","chain2","chain3"]`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDestinations(tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDestinations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDestinations() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitDestParam(t *testing.T) {
+	params, err := SplitDestParam("DestTryParam", `["a","b"]`, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`["a","b"]`}, params)
+
+	params, err = SplitDestParam("DestTryParam", `["a","b"]`, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, params)
+
+	params, err = SplitDestParam("DestTryParam", "", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", ""}, params)
+
+	_, err = SplitDestParam("DestTryParam", `["a"]`, 2)
+	assert.NotNil(t, err)
+
+	_, err = SplitDestParam("DestTryParam", "a", 2)
+	assert.NotNil(t, err)
+}
@@ -137,6 +137,8 @@ type EventInfo struct {
 	Tx           []byte
 	TxId         string
 	BlockHeight  int64
+	// Replay 运维重放的事件，转发后不推进事件游标
+	Replay bool
 }
 
 // ToString 转为string展示